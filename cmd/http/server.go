@@ -10,6 +10,7 @@ import (
 	"syscall"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/worker"
 	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
 	"github.com/marcelobritu/isayoga-api/pkg/config"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
@@ -19,14 +20,16 @@ import (
 )
 
 type Server struct {
-	Config *config.Config
-	Router *chi.Mux
+	Config  *config.Config
+	Router  *chi.Mux
+	Workers []*worker.Worker
 }
 
-func NewServer(cfg *config.Config, r *chi.Mux) *Server {
+func NewServer(cfg *config.Config, r *chi.Mux, workers []*worker.Worker) *Server {
 	return &Server{
-		Config: cfg,
-		Router: r,
+		Config:  cfg,
+		Router:  r,
+		Workers: workers,
 	}
 }
 
@@ -44,6 +47,7 @@ func main() {
 	defer logger.Sync()
 
 	pkgAuth.SetJWTSecret(srv.Config.Auth.JWTSecret)
+	pkgAuth.SetAccessTokenTTL(srv.Config.Auth.AccessTokenTTL)
 
 	tp, shutdown, err := telemetry.InitTracer(telemetry.Config{
 		ServiceName:    srv.Config.Telemetry.ServiceName,
@@ -92,6 +96,13 @@ func main() {
 		zap.String("zipkin_ui", "http://localhost:9411"),
 	)
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	for _, w := range srv.Workers {
+		w.Start(workerCtx)
+	}
+
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
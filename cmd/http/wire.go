@@ -4,18 +4,33 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/google/wire"
+	"github.com/marcelobritu/isayoga-api/internal/domain/event"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"github.com/marcelobritu/isayoga-api/internal/infrastructure/database"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/eventbus"
+	customMiddleware "github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
 	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/router"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/notification"
 	"github.com/marcelobritu/isayoga-api/internal/infrastructure/payment"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/receipt"
 	mongoRepo "github.com/marcelobritu/isayoga-api/internal/infrastructure/repository/mongodb"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/worker"
 	"github.com/marcelobritu/isayoga-api/internal/interface/http/handler"
 	authUC "github.com/marcelobritu/isayoga-api/internal/usecase/auth"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/class"
+	couponUC "github.com/marcelobritu/isayoga-api/internal/usecase/coupon"
+	creditUC "github.com/marcelobritu/isayoga-api/internal/usecase/credit"
 	enrollmentUC "github.com/marcelobritu/isayoga-api/internal/usecase/enrollment"
+	membershipUC "github.com/marcelobritu/isayoga-api/internal/usecase/membership"
 	paymentUC "github.com/marcelobritu/isayoga-api/internal/usecase/payment"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/user"
+	waitlistUC "github.com/marcelobritu/isayoga-api/internal/usecase/waitlist"
 	"github.com/marcelobritu/isayoga-api/pkg/config"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -27,10 +42,30 @@ func InitializeServer() (*Server, error) {
 		provideMongoDatabase,
 		provideMongoClient,
 		provideUserRepository,
+		provideAvailabilityBroker,
 		provideClassRepository,
 		provideEnrollmentRepository,
 		providePaymentRepository,
+		provideOutboxRepository,
+		provideWaitlistRepository,
+		provideClassSeriesRepository,
+		provideRefundRepository,
+		provideReconciliationRepository,
+		provideCreditPackRepository,
+		provideCreditPurchaseRepository,
+		provideCreditRepository,
+		provideMembershipPlanRepository,
+		provideMembershipRepository,
+		provideCouponRepository,
+		provideReceiptRepository,
+		provideWebhookEventRepository,
+		provideSessionRepository,
+		provideRefreshTokenRepository,
 		provideMercadoPagoClient,
+		provideFakeGateway,
+		providePaymentGateway,
+		provideSubscriptionGateway,
+		provideNotifier,
 		user.NewCreateUserUseCase,
 		user.NewGetUserUseCase,
 		user.NewListUsersUseCase,
@@ -39,18 +74,79 @@ func InitializeServer() (*Server, error) {
 		user.NewChangePasswordUseCase,
 		class.NewCreateClassUseCase,
 		class.NewListClassesUseCase,
+		class.NewGetClassUseCase,
+		class.NewWatchClassAvailabilityUseCase,
+		class.NewUpdateClassUseCase,
+		class.NewPublishClassUseCase,
+		class.NewCancelClassUseCase,
+		class.NewAdvanceClassLifecycleUseCase,
+		class.NewMaterializeClassSeriesUseCase,
+		class.NewCreateClassSeriesUseCase,
+		class.NewUpdateSeriesOccurrenceUseCase,
+		class.NewCancelSeriesOccurrenceUseCase,
 		enrollmentUC.NewEnrollStudentUseCase,
 		enrollmentUC.NewCancelEnrollmentUseCase,
+		enrollmentUC.NewGetEnrollmentUseCase,
+		enrollmentUC.NewListMyEnrollmentsUseCase,
+		enrollmentUC.NewProcessCheckoutOutboxUseCase,
+		enrollmentUC.NewExpirePendingEnrollmentsUseCase,
 		paymentUC.NewProcessWebhookUseCase,
+		paymentUC.NewRefundPaymentUseCase,
+		paymentUC.NewProcessRefundOutboxUseCase,
+		paymentUC.NewListRefundsUseCase,
+		paymentUC.NewReconcilePaymentsUseCase,
+		paymentUC.NewListReconciliationsUseCase,
+		paymentUC.NewGetReceiptUseCase,
+		paymentUC.NewProcessWebhookEventUseCase,
+		paymentUC.NewReceiveWebhookUseCase,
+		paymentUC.NewListWebhookEventsUseCase,
+		paymentUC.NewReplayWebhookEventUseCase,
+		receipt.NewRenderer,
+		creditUC.NewCreateCreditPackUseCase,
+		creditUC.NewUpdateCreditPackUseCase,
+		creditUC.NewListCreditPacksUseCase,
+		creditUC.NewPurchaseCreditPackUseCase,
+		creditUC.NewSettleCreditPurchaseUseCase,
+		creditUC.NewGetMyCreditsUseCase,
+		creditUC.NewExpireCreditsUseCase,
+		membershipUC.NewCreateMembershipPlanUseCase,
+		membershipUC.NewUpdateMembershipPlanUseCase,
+		membershipUC.NewListMembershipPlansUseCase,
+		membershipUC.NewSubscribeUseCase,
+		membershipUC.NewGetMyMembershipUseCase,
+		membershipUC.NewCancelMembershipUseCase,
+		membershipUC.NewSyncSubscriptionUseCase,
+		couponUC.NewCreateCouponUseCase,
+		couponUC.NewUpdateCouponUseCase,
+		couponUC.NewListCouponsUseCase,
+		waitlistUC.NewReleaseSeatUseCase,
+		waitlistUC.NewJoinWaitlistUseCase,
+		waitlistUC.NewLeaveWaitlistUseCase,
+		waitlistUC.NewGetWaitlistPositionUseCase,
 		authUC.NewLoginUseCase,
 		authUC.NewRegisterUseCase,
+		authUC.NewStartSessionUseCase,
+		authUC.NewRefreshTokenUseCase,
+		authUC.NewLogoutUseCase,
+		authUC.NewListSessionsUseCase,
+		authUC.NewRevokeSessionUseCase,
+		customMiddleware.NewAuthenticator,
 		handler.NewHealthHandler,
 		handler.NewUserHandler,
 		handler.NewClassHandler,
 		handler.NewEnrollmentHandler,
 		handler.NewWebhookHandler,
 		handler.NewAuthHandler,
+		handler.NewDevPaymentHandler,
+		handler.NewWaitlistHandler,
+		handler.NewClassSeriesHandler,
+		handler.NewClassAvailabilityHandler,
+		handler.NewPaymentHandler,
+		handler.NewCreditHandler,
+		handler.NewMembershipHandler,
+		handler.NewCouponHandler,
 		router.Setup,
+		provideWorkers,
 		NewServer,
 	)
 	return &Server{}, nil
@@ -72,8 +168,19 @@ func provideUserRepository(db *mongo.Database) repository.UserRepository {
 	return mongoRepo.NewUserRepository(db)
 }
 
-func provideClassRepository(db *mongo.Database, client *mongo.Client) repository.ClassRepository {
-	return mongoRepo.NewClassRepository(db, client)
+func provideClassRepository(db *mongo.Database, client *mongo.Client, broker event.AvailabilityBroker) (repository.ClassRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongoRepo.NewClassRepository(db, client)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return eventbus.NewAvailabilityClassRepository(repo, broker), nil
+}
+
+func provideAvailabilityBroker() event.AvailabilityBroker {
+	return eventbus.NewAvailabilityBus()
 }
 
 func provideEnrollmentRepository(db *mongo.Database) repository.EnrollmentRepository {
@@ -84,6 +191,153 @@ func providePaymentRepository(db *mongo.Database) repository.PaymentRepository {
 	return mongoRepo.NewPaymentRepository(db)
 }
 
+func provideOutboxRepository(db *mongo.Database) repository.OutboxRepository {
+	return mongoRepo.NewOutboxRepository(db)
+}
+
+func provideWaitlistRepository(db *mongo.Database) (repository.WaitlistRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongoRepo.NewWaitlistRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideClassSeriesRepository(db *mongo.Database) repository.ClassSeriesRepository {
+	return mongoRepo.NewClassSeriesRepository(db)
+}
+
+func provideRefundRepository(db *mongo.Database) repository.RefundRepository {
+	return mongoRepo.NewRefundRepository(db)
+}
+
+func provideReconciliationRepository(db *mongo.Database) repository.ReconciliationRepository {
+	return mongoRepo.NewReconciliationRepository(db)
+}
+
+func provideCreditPackRepository(db *mongo.Database) repository.CreditPackRepository {
+	return mongoRepo.NewCreditPackRepository(db)
+}
+
+func provideCreditPurchaseRepository(db *mongo.Database) repository.CreditPurchaseRepository {
+	return mongoRepo.NewCreditPurchaseRepository(db)
+}
+
+func provideCreditRepository(db *mongo.Database) repository.CreditRepository {
+	return mongoRepo.NewCreditRepository(db)
+}
+
+func provideMembershipPlanRepository(db *mongo.Database) repository.MembershipPlanRepository {
+	return mongoRepo.NewMembershipPlanRepository(db)
+}
+
+func provideMembershipRepository(db *mongo.Database) repository.MembershipRepository {
+	return mongoRepo.NewMembershipRepository(db)
+}
+
+func provideCouponRepository(db *mongo.Database) (repository.CouponRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongoRepo.NewCouponRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideReceiptRepository(db *mongo.Database) (repository.ReceiptRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongoRepo.NewReceiptRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideWebhookEventRepository(db *mongo.Database) (repository.WebhookEventRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongoRepo.NewWebhookEventRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideSessionRepository(db *mongo.Database) (repository.SessionRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongoRepo.NewSessionRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideRefreshTokenRepository(db *mongo.Database) (repository.RefreshTokenRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongoRepo.NewRefreshTokenRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
 func provideMercadoPagoClient(cfg *config.Config) *payment.MercadoPagoClient {
 	return payment.NewMercadoPagoClient(cfg.MercadoPago.AccessToken)
 }
+
+func provideFakeGateway(cfg *config.Config) *payment.FakeGateway {
+	return payment.NewFakeGateway(fmt.Sprintf("http://localhost:%s", cfg.Server.Port))
+}
+
+func providePaymentGateway(cfg *config.Config, mercadoPago *payment.MercadoPagoClient, fake *payment.FakeGateway) gateway.PaymentGateway {
+	if cfg.Payment.Provider == "fake" {
+		return fake
+	}
+	return mercadoPago
+}
+
+func provideSubscriptionGateway(cfg *config.Config, mercadoPago *payment.MercadoPagoClient, fake *payment.FakeGateway) gateway.SubscriptionGateway {
+	if cfg.Payment.Provider == "fake" {
+		return fake
+	}
+	return mercadoPago
+}
+
+func provideNotifier() gateway.Notifier {
+	return notification.NewLogNotifier()
+}
+
+func provideWorkers(
+	cfg *config.Config,
+	processCheckout *enrollmentUC.ProcessCheckoutOutboxUseCase,
+	expirePending *enrollmentUC.ExpirePendingEnrollmentsUseCase,
+	materializeSeries *class.MaterializeClassSeriesUseCase,
+	advanceLifecycle *class.AdvanceClassLifecycleUseCase,
+	expireCredits *creditUC.ExpireCreditsUseCase,
+	reconcilePayments *paymentUC.ReconcilePaymentsUseCase,
+	processWebhookEvents *paymentUC.ProcessWebhookEventUseCase,
+	processRefunds *paymentUC.ProcessRefundOutboxUseCase,
+) []*worker.Worker {
+	return []*worker.Worker{
+		worker.New("checkout-outbox", cfg.Worker.OutboxInterval, processCheckout.Execute),
+		worker.New("pending-enrollment-expiry", cfg.Worker.ExpiryInterval, expirePending.Execute),
+		worker.New("class-series-materializer", cfg.Worker.SeriesInterval, materializeSeries.Execute),
+		worker.New("class-lifecycle", cfg.Worker.LifecycleInterval, advanceLifecycle.Execute),
+		worker.New("credit-expiry", cfg.Worker.CreditInterval, expireCredits.Execute),
+		worker.New("payment-reconciliation", cfg.Worker.ReconcileInterval, reconcilePayments.Execute),
+		worker.New("webhook-retry", cfg.Worker.WebhookInterval, processWebhookEvents.Execute),
+		worker.New("refund-outbox", cfg.Worker.OutboxInterval, processRefunds.Execute),
+	}
+}
@@ -7,19 +7,33 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"github.com/marcelobritu/isayoga-api/internal/domain/event"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"github.com/marcelobritu/isayoga-api/internal/infrastructure/database"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/eventbus"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
 	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/router"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/notification"
 	payment2 "github.com/marcelobritu/isayoga-api/internal/infrastructure/payment"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/receipt"
 	"github.com/marcelobritu/isayoga-api/internal/infrastructure/repository/mongodb"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/worker"
 	"github.com/marcelobritu/isayoga-api/internal/interface/http/handler"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/auth"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/class"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/coupon"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/credit"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/enrollment"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/membership"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/payment"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/user"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/waitlist"
 	"github.com/marcelobritu/isayoga-api/pkg/config"
 	"go.mongodb.org/mongo-driver/mongo"
+	"time"
 )
 
 // Injectors from wire.go:
@@ -39,28 +53,130 @@ func InitializeServer() (*Server, error) {
 	createUserUseCase := user.NewCreateUserUseCase(userRepository)
 	getUserUseCase := user.NewGetUserUseCase(userRepository)
 	listUsersUseCase := user.NewListUsersUseCase(userRepository)
-	updateUserUseCase := user.NewUpdateUserUseCase(userRepository)
-	deleteUserUseCase := user.NewDeleteUserUseCase(userRepository)
-	changePasswordUseCase := user.NewChangePasswordUseCase(userRepository)
+	sessionRepository, err := provideSessionRepository(database)
+	if err != nil {
+		return nil, err
+	}
+	updateUserUseCase := user.NewUpdateUserUseCase(userRepository, sessionRepository)
+	deleteUserUseCase := user.NewDeleteUserUseCase(userRepository, sessionRepository)
+	changePasswordUseCase := user.NewChangePasswordUseCase(userRepository, sessionRepository)
 	userHandler := handler.NewUserHandler(createUserUseCase, getUserUseCase, listUsersUseCase, updateUserUseCase, deleteUserUseCase, changePasswordUseCase)
 	client := provideMongoClient(mongoDB)
-	classRepository := provideClassRepository(database, client)
+	availabilityBroker := provideAvailabilityBroker()
+	classRepository, err := provideClassRepository(database, client, availabilityBroker)
+	if err != nil {
+		return nil, err
+	}
 	createClassUseCase := class.NewCreateClassUseCase(classRepository)
 	listClassesUseCase := class.NewListClassesUseCase(classRepository)
-	classHandler := handler.NewClassHandler(createClassUseCase, listClassesUseCase)
+	waitlistRepository, err := provideWaitlistRepository(database)
+	if err != nil {
+		return nil, err
+	}
 	enrollmentRepository := provideEnrollmentRepository(database)
+	getClassUseCase := class.NewGetClassUseCase(classRepository, waitlistRepository, enrollmentRepository, userRepository)
+	notifier := provideNotifier()
+	updateClassUseCase := class.NewUpdateClassUseCase(classRepository, enrollmentRepository, userRepository, notifier)
+	publishClassUseCase := class.NewPublishClassUseCase(classRepository)
 	paymentRepository := providePaymentRepository(database)
+	creditRepository := provideCreditRepository(database)
+	membershipRepository := provideMembershipRepository(database)
+	couponRepository, err := provideCouponRepository(database)
+	if err != nil {
+		return nil, err
+	}
+	refundRepository := provideRefundRepository(database)
+	outboxRepository := provideOutboxRepository(database)
 	mercadoPagoClient := provideMercadoPagoClient(configConfig)
-	enrollStudentUseCase := enrollment.NewEnrollStudentUseCase(classRepository, enrollmentRepository, paymentRepository, userRepository, mercadoPagoClient, configConfig)
-	cancelEnrollmentUseCase := enrollment.NewCancelEnrollmentUseCase(enrollmentRepository, classRepository)
-	enrollmentHandler := handler.NewEnrollmentHandler(enrollStudentUseCase, cancelEnrollmentUseCase)
-	processWebhookUseCase := payment.NewProcessWebhookUseCase(paymentRepository, enrollmentRepository)
-	webhookHandler := handler.NewWebhookHandler(processWebhookUseCase)
-	loginUseCase := auth.NewLoginUseCase(userRepository)
-	registerUseCase := auth.NewRegisterUseCase(userRepository)
-	authHandler := handler.NewAuthHandler(loginUseCase, registerUseCase)
-	mux := router.Setup(healthHandler, userHandler, classHandler, enrollmentHandler, webhookHandler, authHandler)
-	server := NewServer(configConfig, mux)
+	fakeGateway := provideFakeGateway(configConfig)
+	paymentGateway := providePaymentGateway(configConfig, mercadoPagoClient, fakeGateway)
+	refundPaymentUseCase := payment.NewRefundPaymentUseCase(paymentRepository, refundRepository, outboxRepository, classRepository, paymentGateway)
+	processRefundOutboxUseCase := payment.NewProcessRefundOutboxUseCase(outboxRepository, refundRepository, paymentRepository, refundPaymentUseCase, configConfig)
+	cancelClassUseCase := class.NewCancelClassUseCase(classRepository, enrollmentRepository, paymentRepository, waitlistRepository, userRepository, creditRepository, membershipRepository, couponRepository, refundPaymentUseCase, processRefundOutboxUseCase, notifier)
+	classHandler := handler.NewClassHandler(createClassUseCase, listClassesUseCase, getClassUseCase, updateClassUseCase, publishClassUseCase, cancelClassUseCase)
+	releaseSeatUseCase := waitlist.NewReleaseSeatUseCase(classRepository, waitlistRepository, enrollmentRepository, paymentRepository, outboxRepository, creditRepository, membershipRepository, configConfig)
+	processCheckoutOutboxUseCase := enrollment.NewProcessCheckoutOutboxUseCase(outboxRepository, classRepository, enrollmentRepository, paymentRepository, couponRepository, userRepository, waitlistRepository, paymentGateway, notifier, releaseSeatUseCase, configConfig)
+	enrollStudentUseCase := enrollment.NewEnrollStudentUseCase(classRepository, enrollmentRepository, paymentRepository, userRepository, outboxRepository, creditRepository, membershipRepository, couponRepository, processCheckoutOutboxUseCase, configConfig)
+	cancelEnrollmentUseCase := enrollment.NewCancelEnrollmentUseCase(enrollmentRepository, classRepository, paymentRepository, creditRepository, membershipRepository, refundPaymentUseCase, processRefundOutboxUseCase, releaseSeatUseCase, configConfig)
+	getEnrollmentUseCase := enrollment.NewGetEnrollmentUseCase(enrollmentRepository, paymentRepository)
+	listMyEnrollmentsUseCase := enrollment.NewListMyEnrollmentsUseCase(enrollmentRepository, classRepository, paymentRepository)
+	enrollmentHandler := handler.NewEnrollmentHandler(enrollStudentUseCase, cancelEnrollmentUseCase, getEnrollmentUseCase, listMyEnrollmentsUseCase)
+	webhookEventRepository, err := provideWebhookEventRepository(database)
+	if err != nil {
+		return nil, err
+	}
+	creditPurchaseRepository := provideCreditPurchaseRepository(database)
+	settleCreditPurchaseUseCase := credit.NewSettleCreditPurchaseUseCase(creditPurchaseRepository, creditRepository, configConfig)
+	subscriptionGateway := provideSubscriptionGateway(configConfig, mercadoPagoClient, fakeGateway)
+	syncSubscriptionUseCase := membership.NewSyncSubscriptionUseCase(membershipRepository, paymentRepository, classRepository, subscriptionGateway)
+	processWebhookUseCase := payment.NewProcessWebhookUseCase(paymentRepository, enrollmentRepository, classRepository, paymentGateway, releaseSeatUseCase, refundPaymentUseCase, settleCreditPurchaseUseCase, membershipRepository, couponRepository, syncSubscriptionUseCase)
+	processWebhookEventUseCase := payment.NewProcessWebhookEventUseCase(webhookEventRepository, processWebhookUseCase, configConfig)
+	receiveWebhookUseCase := payment.NewReceiveWebhookUseCase(webhookEventRepository, processWebhookEventUseCase)
+	listWebhookEventsUseCase := payment.NewListWebhookEventsUseCase(webhookEventRepository)
+	replayWebhookEventUseCase := payment.NewReplayWebhookEventUseCase(webhookEventRepository, processWebhookEventUseCase)
+	webhookHandler := handler.NewWebhookHandler(receiveWebhookUseCase, listWebhookEventsUseCase, replayWebhookEventUseCase, configConfig)
+	refreshTokenRepository, err := provideRefreshTokenRepository(database)
+	if err != nil {
+		return nil, err
+	}
+	startSessionUseCase := auth.NewStartSessionUseCase(sessionRepository, refreshTokenRepository, configConfig)
+	loginUseCase := auth.NewLoginUseCase(userRepository, startSessionUseCase)
+	registerUseCase := auth.NewRegisterUseCase(userRepository, startSessionUseCase)
+	refreshTokenUseCase := auth.NewRefreshTokenUseCase(sessionRepository, refreshTokenRepository, userRepository, configConfig)
+	logoutUseCase := auth.NewLogoutUseCase(sessionRepository, refreshTokenRepository)
+	listSessionsUseCase := auth.NewListSessionsUseCase(sessionRepository)
+	revokeSessionUseCase := auth.NewRevokeSessionUseCase(sessionRepository)
+	authHandler := handler.NewAuthHandler(loginUseCase, registerUseCase, refreshTokenUseCase, logoutUseCase, listSessionsUseCase, revokeSessionUseCase)
+	devPaymentHandler := handler.NewDevPaymentHandler(fakeGateway, processWebhookUseCase, configConfig)
+	joinWaitlistUseCase := waitlist.NewJoinWaitlistUseCase(classRepository, userRepository, enrollmentRepository, waitlistRepository)
+	leaveWaitlistUseCase := waitlist.NewLeaveWaitlistUseCase(classRepository, waitlistRepository, enrollmentRepository, paymentRepository, releaseSeatUseCase)
+	getWaitlistPositionUseCase := waitlist.NewGetWaitlistPositionUseCase(waitlistRepository, paymentRepository)
+	waitlistHandler := handler.NewWaitlistHandler(joinWaitlistUseCase, leaveWaitlistUseCase, getWaitlistPositionUseCase)
+	classSeriesRepository := provideClassSeriesRepository(database)
+	materializeClassSeriesUseCase := class.NewMaterializeClassSeriesUseCase(classSeriesRepository, classRepository, configConfig)
+	createClassSeriesUseCase := class.NewCreateClassSeriesUseCase(classSeriesRepository, materializeClassSeriesUseCase)
+	updateSeriesOccurrenceUseCase := class.NewUpdateSeriesOccurrenceUseCase(classSeriesRepository, classRepository, updateClassUseCase)
+	cancelSeriesOccurrenceUseCase := class.NewCancelSeriesOccurrenceUseCase(classSeriesRepository, classRepository, cancelClassUseCase)
+	classSeriesHandler := handler.NewClassSeriesHandler(createClassSeriesUseCase, updateSeriesOccurrenceUseCase, cancelSeriesOccurrenceUseCase)
+	watchClassAvailabilityUseCase := class.NewWatchClassAvailabilityUseCase(classRepository, availabilityBroker)
+	classAvailabilityHandler := handler.NewClassAvailabilityHandler(watchClassAvailabilityUseCase, configConfig)
+	listRefundsUseCase := payment.NewListRefundsUseCase(paymentRepository, refundRepository)
+	reconciliationRepository := provideReconciliationRepository(database)
+	reconcilePaymentsUseCase := payment.NewReconcilePaymentsUseCase(paymentRepository, reconciliationRepository, paymentGateway, processWebhookUseCase, configConfig)
+	listReconciliationsUseCase := payment.NewListReconciliationsUseCase(reconciliationRepository)
+	receiptRepository, err := provideReceiptRepository(database)
+	if err != nil {
+		return nil, err
+	}
+	creditPackRepository := provideCreditPackRepository(database)
+	membershipPlanRepository := provideMembershipPlanRepository(database)
+	getReceiptUseCase := payment.NewGetReceiptUseCase(paymentRepository, receiptRepository, userRepository, enrollmentRepository, classRepository, creditPackRepository, creditPurchaseRepository, membershipRepository, membershipPlanRepository, configConfig)
+	renderer := receipt.NewRenderer()
+	paymentHandler := handler.NewPaymentHandler(refundPaymentUseCase, listRefundsUseCase, reconcilePaymentsUseCase, listReconciliationsUseCase, getReceiptUseCase, renderer)
+	createCreditPackUseCase := credit.NewCreateCreditPackUseCase(creditPackRepository)
+	updateCreditPackUseCase := credit.NewUpdateCreditPackUseCase(creditPackRepository)
+	listCreditPacksUseCase := credit.NewListCreditPacksUseCase(creditPackRepository)
+	purchaseCreditPackUseCase := credit.NewPurchaseCreditPackUseCase(classRepository, creditPackRepository, creditPurchaseRepository, paymentRepository, userRepository, paymentGateway, configConfig)
+	getMyCreditsUseCase := credit.NewGetMyCreditsUseCase(creditRepository)
+	creditHandler := handler.NewCreditHandler(createCreditPackUseCase, updateCreditPackUseCase, listCreditPacksUseCase, purchaseCreditPackUseCase, getMyCreditsUseCase)
+	createMembershipPlanUseCase := membership.NewCreateMembershipPlanUseCase(membershipPlanRepository)
+	updateMembershipPlanUseCase := membership.NewUpdateMembershipPlanUseCase(membershipPlanRepository)
+	listMembershipPlansUseCase := membership.NewListMembershipPlansUseCase(membershipPlanRepository)
+	subscribeUseCase := membership.NewSubscribeUseCase(membershipPlanRepository, membershipRepository, userRepository, subscriptionGateway, configConfig)
+	getMyMembershipUseCase := membership.NewGetMyMembershipUseCase(membershipRepository)
+	cancelMembershipUseCase := membership.NewCancelMembershipUseCase(membershipRepository, subscriptionGateway)
+	membershipHandler := handler.NewMembershipHandler(createMembershipPlanUseCase, updateMembershipPlanUseCase, listMembershipPlansUseCase, subscribeUseCase, getMyMembershipUseCase, cancelMembershipUseCase)
+	createCouponUseCase := coupon.NewCreateCouponUseCase(couponRepository)
+	updateCouponUseCase := coupon.NewUpdateCouponUseCase(couponRepository)
+	listCouponsUseCase := coupon.NewListCouponsUseCase(couponRepository)
+	couponHandler := handler.NewCouponHandler(createCouponUseCase, updateCouponUseCase, listCouponsUseCase)
+	authenticator := middleware.NewAuthenticator(userRepository, sessionRepository)
+	mux := router.Setup(healthHandler, userHandler, classHandler, enrollmentHandler, webhookHandler, authHandler, devPaymentHandler, waitlistHandler, classSeriesHandler, classAvailabilityHandler, paymentHandler, creditHandler, membershipHandler, couponHandler, authenticator)
+	expirePendingEnrollmentsUseCase := enrollment.NewExpirePendingEnrollmentsUseCase(classRepository, enrollmentRepository, paymentRepository, couponRepository, releaseSeatUseCase)
+	advanceClassLifecycleUseCase := class.NewAdvanceClassLifecycleUseCase(classRepository)
+	expireCreditsUseCase := credit.NewExpireCreditsUseCase(creditRepository)
+	v := provideWorkers(configConfig, processCheckoutOutboxUseCase, expirePendingEnrollmentsUseCase, materializeClassSeriesUseCase, advanceClassLifecycleUseCase, expireCreditsUseCase, reconcilePaymentsUseCase, processWebhookEventUseCase, processRefundOutboxUseCase)
+	server := NewServer(configConfig, mux, v)
 	return server, nil
 }
 
@@ -82,8 +198,19 @@ func provideUserRepository(db *mongo.Database) repository.UserRepository {
 	return mongodb.NewUserRepository(db)
 }
 
-func provideClassRepository(db *mongo.Database, client *mongo.Client) repository.ClassRepository {
-	return mongodb.NewClassRepository(db, client)
+func provideClassRepository(db *mongo.Database, client *mongo.Client, broker event.AvailabilityBroker) (repository.ClassRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongodb.NewClassRepository(db, client)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return eventbus.NewAvailabilityClassRepository(repo, broker), nil
+}
+
+func provideAvailabilityBroker() event.AvailabilityBroker {
+	return eventbus.NewAvailabilityBus()
 }
 
 func provideEnrollmentRepository(db *mongo.Database) repository.EnrollmentRepository {
@@ -94,6 +221,144 @@ func providePaymentRepository(db *mongo.Database) repository.PaymentRepository {
 	return mongodb.NewPaymentRepository(db)
 }
 
+func provideOutboxRepository(db *mongo.Database) repository.OutboxRepository {
+	return mongodb.NewOutboxRepository(db)
+}
+
+func provideWaitlistRepository(db *mongo.Database) (repository.WaitlistRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongodb.NewWaitlistRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideClassSeriesRepository(db *mongo.Database) repository.ClassSeriesRepository {
+	return mongodb.NewClassSeriesRepository(db)
+}
+
+func provideRefundRepository(db *mongo.Database) repository.RefundRepository {
+	return mongodb.NewRefundRepository(db)
+}
+
+func provideReconciliationRepository(db *mongo.Database) repository.ReconciliationRepository {
+	return mongodb.NewReconciliationRepository(db)
+}
+
+func provideCreditPackRepository(db *mongo.Database) repository.CreditPackRepository {
+	return mongodb.NewCreditPackRepository(db)
+}
+
+func provideCreditPurchaseRepository(db *mongo.Database) repository.CreditPurchaseRepository {
+	return mongodb.NewCreditPurchaseRepository(db)
+}
+
+func provideCreditRepository(db *mongo.Database) repository.CreditRepository {
+	return mongodb.NewCreditRepository(db)
+}
+
+func provideMembershipPlanRepository(db *mongo.Database) repository.MembershipPlanRepository {
+	return mongodb.NewMembershipPlanRepository(db)
+}
+
+func provideMembershipRepository(db *mongo.Database) repository.MembershipRepository {
+	return mongodb.NewMembershipRepository(db)
+}
+
+func provideCouponRepository(db *mongo.Database) (repository.CouponRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongodb.NewCouponRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideReceiptRepository(db *mongo.Database) (repository.ReceiptRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongodb.NewReceiptRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideWebhookEventRepository(db *mongo.Database) (repository.WebhookEventRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongodb.NewWebhookEventRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideSessionRepository(db *mongo.Database) (repository.SessionRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongodb.NewSessionRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+func provideRefreshTokenRepository(db *mongo.Database) (repository.RefreshTokenRepository, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repo := mongodb.NewRefreshTokenRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
 func provideMercadoPagoClient(cfg *config.Config) *payment2.MercadoPagoClient {
 	return payment2.NewMercadoPagoClient(cfg.MercadoPago.AccessToken)
 }
+
+func provideFakeGateway(cfg *config.Config) *payment2.FakeGateway {
+	return payment2.NewFakeGateway(fmt.Sprintf("http://localhost:%s", cfg.Server.Port))
+}
+
+func providePaymentGateway(cfg *config.Config, mercadoPago *payment2.MercadoPagoClient, fake *payment2.FakeGateway) gateway.PaymentGateway {
+	if cfg.Payment.Provider == "fake" {
+		return fake
+	}
+	return mercadoPago
+}
+
+func provideSubscriptionGateway(cfg *config.Config, mercadoPago *payment2.MercadoPagoClient, fake *payment2.FakeGateway) gateway.SubscriptionGateway {
+	if cfg.Payment.Provider == "fake" {
+		return fake
+	}
+	return mercadoPago
+}
+
+func provideNotifier() gateway.Notifier {
+	return notification.NewLogNotifier()
+}
+
+func provideWorkers(
+	cfg *config.Config,
+	processCheckout *enrollment.ProcessCheckoutOutboxUseCase,
+	expirePending *enrollment.ExpirePendingEnrollmentsUseCase,
+	materializeSeries *class.MaterializeClassSeriesUseCase,
+	advanceLifecycle *class.AdvanceClassLifecycleUseCase,
+	expireCredits *credit.ExpireCreditsUseCase,
+	reconcilePayments *payment.ReconcilePaymentsUseCase,
+	processWebhookEvents *payment.ProcessWebhookEventUseCase,
+	processRefunds *payment.ProcessRefundOutboxUseCase,
+) []*worker.Worker {
+	return []*worker.Worker{worker.New("checkout-outbox", cfg.Worker.OutboxInterval, processCheckout.Execute), worker.New("pending-enrollment-expiry", cfg.Worker.ExpiryInterval, expirePending.Execute), worker.New("class-series-materializer", cfg.Worker.SeriesInterval, materializeSeries.Execute), worker.New("class-lifecycle", cfg.Worker.LifecycleInterval, advanceLifecycle.Execute), worker.New("credit-expiry", cfg.Worker.CreditInterval, expireCredits.Execute), worker.New("payment-reconciliation", cfg.Worker.ReconcileInterval, reconcilePayments.Execute), worker.New("webhook-retry", cfg.Worker.WebhookInterval, processWebhookEvents.Execute), worker.New("refund-outbox", cfg.Worker.OutboxInterval, processRefunds.Execute)}
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrCancellationClosed = errors.New("não é possível cancelar a inscrição após o início da aula")
+
+// CancellationPolicy define a compensação de um cancelamento feito pelo aluno: reembolso
+// integral até FullRefundWindow antes do início da aula; dentro da janela, um crédito de
+// aula quando LateCredit está ativo ou LateRefundPercent do valor pago. Depois do início
+// da aula o cancelamento não é permitido.
+type CancellationPolicy struct {
+	FullRefundWindow  time.Duration
+	LateRefundPercent int
+	LateCredit        bool
+}
+
+type CancellationDecision struct {
+	Late          bool
+	RefundInCents int64
+	Credit        bool
+}
+
+func (p CancellationPolicy) Evaluate(classStart, now time.Time, paidInCents int64) (*CancellationDecision, error) {
+	if !now.Before(classStart) {
+		return nil, ErrCancellationClosed
+	}
+
+	if classStart.Sub(now) > p.FullRefundWindow {
+		return &CancellationDecision{RefundInCents: paidInCents}, nil
+	}
+
+	if p.LateCredit {
+		return &CancellationDecision{Late: true, Credit: true}, nil
+	}
+
+	return &CancellationDecision{
+		Late:          true,
+		RefundInCents: paidInCents * int64(p.LateRefundPercent) / 100,
+	}, nil
+}
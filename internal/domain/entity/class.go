@@ -1,28 +1,52 @@
 package entity
 
 import (
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Ciclo de vida da aula: draft → published → in_progress → completed.
+// Aulas em draft ou published podem ser canceladas.
+const (
+	ClassStatusDraft      = "draft"
+	ClassStatusPublished  = "published"
+	ClassStatusInProgress = "in_progress"
+	ClassStatusCompleted  = "completed"
+	ClassStatusCancelled  = "cancelled"
+
+	// ClassStatusActive é o status das aulas criadas antes do ciclo de vida e equivale a published.
+	ClassStatusActive = "active"
+)
+
+var (
+	ErrInvalidClassTransition = errors.New("transição de status da aula inválida")
+	ErrInvalidClassCapacity   = errors.New("capacidade deve ser maior que zero")
+	ErrInvalidClassPrice      = errors.New("preço não pode ser negativo")
+)
+
 type Class struct {
-	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Title            string             `json:"title" bson:"title"`
-	Description      string             `json:"description" bson:"description"`
-	InstructorID     primitive.ObjectID `json:"instructor_id" bson:"instructor_id"`
-	InstructorName   string             `json:"instructor_name" bson:"instructor_name"`
-	StartTime        time.Time          `json:"start_time" bson:"start_time"`
-	EndTime          time.Time          `json:"end_time" bson:"end_time"`
-	MaxCapacity      int                `json:"max_capacity" bson:"max_capacity"`
-	CurrentEnrolled  int                `json:"current_enrolled" bson:"current_enrolled"`
-	PriceInCents     int64              `json:"price_in_cents" bson:"price_in_cents"`
-	Status           string             `json:"status" bson:"status"`
-	CreatedAt        time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt        time.Time          `json:"updated_at" bson:"updated_at"`
-	Version          int                `json:"version" bson:"version"`
+	ID              primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Title           string              `json:"title" bson:"title"`
+	Description     string              `json:"description" bson:"description"`
+	InstructorID    primitive.ObjectID  `json:"instructor_id" bson:"instructor_id"`
+	InstructorName  string              `json:"instructor_name" bson:"instructor_name"`
+	StartTime       time.Time           `json:"start_time" bson:"start_time"`
+	EndTime         time.Time           `json:"end_time" bson:"end_time"`
+	MaxCapacity     int                 `json:"max_capacity" bson:"max_capacity"`
+	CurrentEnrolled int                 `json:"current_enrolled" bson:"current_enrolled"`
+	PriceInCents    int64               `json:"price_in_cents" bson:"price_in_cents"`
+	Status          string              `json:"status" bson:"status"`
+	SeriesID        *primitive.ObjectID `json:"series_id,omitempty" bson:"series_id,omitempty"`
+	OccurrenceStart *time.Time          `json:"occurrence_start,omitempty" bson:"occurrence_start,omitempty"`
+	CreatedAt       time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at" bson:"updated_at"`
+	Version         int                 `json:"version" bson:"version"`
 }
 
+// NewClass cria a aula já publicada, como antes do ciclo de vida; rascunhos são pedidos
+// explicitamente na criação.
 func NewClass(title, description string, instructorID primitive.ObjectID, instructorName string, startTime, endTime time.Time, maxCapacity int, priceInCents int64) *Class {
 	now := time.Now()
 	return &Class{
@@ -36,13 +60,24 @@ func NewClass(title, description string, instructorID primitive.ObjectID, instru
 		MaxCapacity:     maxCapacity,
 		CurrentEnrolled: 0,
 		PriceInCents:    priceInCents,
-		Status:          "active",
+		Status:          ClassStatusPublished,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 		Version:         0,
 	}
 }
 
+// Validate verifica a capacidade e o preço, tanto na criação quanto após uma edição.
+func (c *Class) Validate() error {
+	if c.MaxCapacity <= 0 {
+		return ErrInvalidClassCapacity
+	}
+	if c.PriceInCents < 0 {
+		return ErrInvalidClassPrice
+	}
+	return nil
+}
+
 func (c *Class) HasAvailableSpots() bool {
 	return c.CurrentEnrolled < c.MaxCapacity
 }
@@ -61,3 +96,56 @@ func (c *Class) DecrementEnrollment() {
 	}
 }
 
+func (c *Class) IsPublished() bool {
+	return c.Status == ClassStatusPublished || c.Status == ClassStatusActive
+}
+
+func (c *Class) IsCancelled() bool {
+	return c.Status == ClassStatusCancelled
+}
+
+// IsEditable indica se a aula ainda pode ter dados, horário ou capacidade alterados.
+func (c *Class) IsEditable() bool {
+	return c.Status == ClassStatusDraft || c.IsPublished()
+}
+
+// IsOpenForEnrollment indica se a aula aceita inscrições e entradas na lista de espera.
+func (c *Class) IsOpenForEnrollment(now time.Time) bool {
+	return c.IsPublished() && c.StartTime.After(now)
+}
+
+func (c *Class) Publish() error {
+	if c.Status != ClassStatusDraft {
+		return ErrInvalidClassTransition
+	}
+	c.Status = ClassStatusPublished
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+func (c *Class) Start() error {
+	if !c.IsPublished() {
+		return ErrInvalidClassTransition
+	}
+	c.Status = ClassStatusInProgress
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+func (c *Class) Complete() error {
+	if c.Status != ClassStatusInProgress {
+		return ErrInvalidClassTransition
+	}
+	c.Status = ClassStatusCompleted
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+func (c *Class) Cancel() error {
+	if !c.IsEditable() {
+		return ErrInvalidClassTransition
+	}
+	c.Status = ClassStatusCancelled
+	c.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,292 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RecurrenceDaily  = "DAILY"
+	RecurrenceWeekly = "WEEKLY"
+
+	ClassSeriesStatusActive = "active"
+	ClassSeriesStatusEnded  = "ended"
+
+	DefaultTimezone = "America/Sao_Paulo"
+)
+
+// FormatLocalTime formata o instante no fuso do estúdio, como aparece em avisos e recibos.
+func FormatLocalTime(t time.Time) string {
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("02/01/2006 às 15:04")
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Recurrence segue o subconjunto de RRULE usado pelo estúdio: FREQ, INTERVAL, BYDAY,
+// UNTIL e COUNT, além de datas excluídas (EXDATE) para feriados.
+type Recurrence struct {
+	Frequency  string      `json:"frequency" bson:"frequency"`
+	Interval   int         `json:"interval" bson:"interval"`
+	ByDay      []string    `json:"by_day,omitempty" bson:"by_day,omitempty"`
+	Until      *time.Time  `json:"until,omitempty" bson:"until,omitempty"`
+	Count      int         `json:"count,omitempty" bson:"count,omitempty"`
+	Exceptions []time.Time `json:"exceptions,omitempty" bson:"exceptions,omitempty"`
+}
+
+func (r Recurrence) Validate() error {
+	if r.Frequency != RecurrenceDaily && r.Frequency != RecurrenceWeekly {
+		return fmt.Errorf("frequência inválida: deve ser DAILY ou WEEKLY")
+	}
+	if r.Interval < 1 {
+		return fmt.Errorf("intervalo deve ser maior que zero")
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("quantidade de ocorrências inválida")
+	}
+	if r.Until != nil && r.Count > 0 {
+		return fmt.Errorf("informe apenas until ou count")
+	}
+	for _, day := range r.ByDay {
+		if _, ok := weekdayCodes[day]; !ok {
+			return fmt.Errorf("dia da semana inválido: %s", day)
+		}
+	}
+	return nil
+}
+
+type ClassSeries struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Title             string             `json:"title" bson:"title"`
+	Description       string             `json:"description" bson:"description"`
+	InstructorID      primitive.ObjectID `json:"instructor_id" bson:"instructor_id"`
+	InstructorName    string             `json:"instructor_name" bson:"instructor_name"`
+	StartTime         time.Time          `json:"start_time" bson:"start_time"`
+	DurationMinutes   int                `json:"duration_minutes" bson:"duration_minutes"`
+	Timezone          string             `json:"timezone" bson:"timezone"`
+	MaxCapacity       int                `json:"max_capacity" bson:"max_capacity"`
+	PriceInCents      int64              `json:"price_in_cents" bson:"price_in_cents"`
+	Recurrence        Recurrence         `json:"recurrence" bson:"recurrence"`
+	MaterializedUntil time.Time          `json:"materialized_until" bson:"materialized_until"`
+	Status            string             `json:"status" bson:"status"`
+	CreatedAt         time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+func NewClassSeries(title, description string, instructorID primitive.ObjectID, instructorName string, startTime time.Time, durationMinutes int, timezone string, maxCapacity int, priceInCents int64, recurrence Recurrence) *ClassSeries {
+	now := time.Now()
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	return &ClassSeries{
+		ID:              primitive.NewObjectID(),
+		Title:           title,
+		Description:     description,
+		InstructorID:    instructorID,
+		InstructorName:  instructorName,
+		StartTime:       startTime,
+		DurationMinutes: durationMinutes,
+		Timezone:        timezone,
+		MaxCapacity:     maxCapacity,
+		PriceInCents:    priceInCents,
+		Recurrence:      recurrence,
+		Status:          ClassSeriesStatusActive,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+func (s *ClassSeries) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func (s *ClassSeries) Duration() time.Duration {
+	return time.Duration(s.DurationMinutes) * time.Minute
+}
+
+// OccurrencesUntil devolve o início de cada ocorrência da série até limit (inclusive),
+// respeitando UNTIL, COUNT e as datas excluídas. COUNT considera também as datas
+// excluídas, como no RRULE.
+func (s *ClassSeries) OccurrencesUntil(limit time.Time) []time.Time {
+	occurrences, _ := s.occurrences(limit)
+	return occurrences
+}
+
+// HasOccurrencesAfter indica se a série ainda pode gerar ocorrências depois de t.
+func (s *ClassSeries) HasOccurrencesAfter(t time.Time) bool {
+	if s.Recurrence.Until != nil && !s.Recurrence.Until.After(t) {
+		return false
+	}
+	if s.Recurrence.Count > 0 {
+		_, generated := s.occurrences(t)
+		return generated < s.Recurrence.Count
+	}
+	return true
+}
+
+func (s *ClassSeries) occurrences(limit time.Time) ([]time.Time, int) {
+	loc := s.Location()
+	first := s.StartTime.In(loc)
+	startDay := civilDay(first)
+
+	byDay := make(map[time.Weekday]bool)
+	for _, code := range s.Recurrence.ByDay {
+		byDay[weekdayCodes[code]] = true
+	}
+	if len(byDay) == 0 {
+		byDay[first.Weekday()] = true
+	}
+
+	interval := s.Recurrence.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var occurrences []time.Time
+	generated := 0
+
+	for offset := 0; ; offset++ {
+		day := startDay.AddDate(0, 0, offset)
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), first.Hour(), first.Minute(), first.Second(), 0, loc)
+
+		if occurrence.After(limit) {
+			break
+		}
+		if s.Recurrence.Until != nil && occurrence.After(*s.Recurrence.Until) {
+			break
+		}
+		if s.Recurrence.Count > 0 && generated >= s.Recurrence.Count {
+			break
+		}
+
+		if !s.matches(startDay, day, byDay, interval) {
+			continue
+		}
+
+		generated++
+		if s.IsException(occurrence) {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+
+	return occurrences, generated
+}
+
+// HasOccurrence indica se a série gera uma ocorrência exatamente no instante informado.
+func (s *ClassSeries) HasOccurrence(start time.Time) bool {
+	occurrences := s.OccurrencesUntil(start)
+	return len(occurrences) > 0 && occurrences[len(occurrences)-1].Equal(start)
+}
+
+func (s *ClassSeries) IsException(occurrence time.Time) bool {
+	loc := s.Location()
+	day := civilDay(occurrence.In(loc))
+	for _, exception := range s.Recurrence.Exceptions {
+		if civilDay(exception.In(loc)).Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ClassSeries) AddException(occurrence time.Time) {
+	if s.IsException(occurrence) {
+		return
+	}
+	s.Recurrence.Exceptions = append(s.Recurrence.Exceptions, occurrence)
+	s.UpdatedAt = time.Now()
+}
+
+// EndBefore encerra a série imediatamente antes da ocorrência informada.
+func (s *ClassSeries) EndBefore(occurrence time.Time) {
+	until := occurrence.Add(-time.Second)
+	s.Recurrence.Until = &until
+	s.Recurrence.Count = 0
+	s.UpdatedAt = time.Now()
+}
+
+// Split encerra a série antes da ocorrência informada e devolve uma cópia, com novo ID,
+// que gera esta e as ocorrências seguintes. COUNT é ajustado para o que restava.
+func (s *ClassSeries) Split(occurrence time.Time) *ClassSeries {
+	following := *s
+	following.ID = primitive.NewObjectID()
+	following.Recurrence.ByDay = append([]string(nil), s.Recurrence.ByDay...)
+	following.Recurrence.Exceptions = append([]time.Time(nil), s.Recurrence.Exceptions...)
+	if s.Recurrence.Count > 0 {
+		_, generated := s.occurrences(occurrence.Add(-time.Second))
+		following.Recurrence.Count -= generated
+	}
+	following.Status = ClassSeriesStatusActive
+	following.CreatedAt = time.Now()
+	following.UpdatedAt = following.CreatedAt
+
+	s.EndBefore(occurrence)
+	return &following
+}
+
+func (s *ClassSeries) End() {
+	s.Status = ClassSeriesStatusEnded
+	s.UpdatedAt = time.Now()
+}
+
+func (s *ClassSeries) NewOccurrence(start time.Time) *Class {
+	class := NewClass(
+		s.Title,
+		s.Description,
+		s.InstructorID,
+		s.InstructorName,
+		start,
+		start.Add(s.Duration()),
+		s.MaxCapacity,
+		s.PriceInCents,
+	)
+	class.SeriesID = &s.ID
+	class.OccurrenceStart = &start
+	return class
+}
+
+func (s *ClassSeries) matches(startDay, day time.Time, byDay map[time.Weekday]bool, interval int) bool {
+	switch s.Recurrence.Frequency {
+	case RecurrenceDaily:
+		return daysBetween(startDay, day)%interval == 0
+	default:
+		if !byDay[day.Weekday()] {
+			return false
+		}
+		weeks := daysBetween(weekStart(startDay), weekStart(day)) / 7
+		return weeks%interval == 0
+	}
+}
+
+// civilDay representa a data do calendário em UTC, sem influência de horário de verão.
+func civilDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// weekStart considera a semana iniciando na segunda-feira (WKST=MO).
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,160 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CouponTypePercent        = "percent"
+	CouponTypeFixed          = "fixed"
+	CouponTypeFirstClassFree = "first_class_free"
+
+	CouponRedemptionStatusActive   = "active"
+	CouponRedemptionStatusReleased = "released"
+)
+
+var (
+	ErrCouponInactive      = errors.New("cupom fora do período de validade")
+	ErrCouponNotApplicable = errors.New("cupom não se aplica a esta aula")
+	ErrCouponFirstClass    = errors.New("cupom válido apenas para a primeira aula do aluno")
+)
+
+// Coupon é um código promocional aplicado ao preço da aula. Value é o percentual (1 a 100)
+// em cupons percent e o valor em centavos em cupons fixed; cupons first_class_free zeram
+// o preço da primeira aula do aluno. MaxRedemptions e MaxPerUser iguais a zero não
+// limitam o uso. Sem ClassIDs nem InstructorIDs, o cupom vale para qualquer aula.
+type Coupon struct {
+	ID             primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Code           string               `json:"code" bson:"code"`
+	Type           string               `json:"type" bson:"type"`
+	Value          int64                `json:"value" bson:"value"`
+	ValidFrom      *time.Time           `json:"valid_from,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil     *time.Time           `json:"valid_until,omitempty" bson:"valid_until,omitempty"`
+	MaxRedemptions int                  `json:"max_redemptions" bson:"max_redemptions"`
+	MaxPerUser     int                  `json:"max_per_user" bson:"max_per_user"`
+	Redemptions    int                  `json:"redemptions" bson:"redemptions"`
+	ClassIDs       []primitive.ObjectID `json:"class_ids,omitempty" bson:"class_ids,omitempty"`
+	InstructorIDs  []primitive.ObjectID `json:"instructor_ids,omitempty" bson:"instructor_ids,omitempty"`
+	Active         bool                 `json:"active" bson:"active"`
+	CreatedAt      time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" bson:"updated_at"`
+}
+
+func NewCoupon(code, couponType string, value int64) *Coupon {
+	now := time.Now()
+	coupon := &Coupon{
+		ID:        primitive.NewObjectID(),
+		Code:      NormalizeCouponCode(code),
+		Type:      couponType,
+		Value:     value,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if couponType == CouponTypeFirstClassFree {
+		coupon.MaxPerUser = 1
+	}
+	return coupon
+}
+
+// NormalizeCouponCode padroniza o código digitado pelo aluno.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsValid verifica o tipo, o valor e o período de validade informados pelo estúdio.
+func (c *Coupon) IsValid() bool {
+	if c.Code == "" {
+		return false
+	}
+	if c.ValidFrom != nil && c.ValidUntil != nil && !c.ValidUntil.After(*c.ValidFrom) {
+		return false
+	}
+	if c.MaxRedemptions < 0 || c.MaxPerUser < 0 {
+		return false
+	}
+
+	switch c.Type {
+	case CouponTypePercent:
+		return c.Value > 0 && c.Value <= 100
+	case CouponTypeFixed:
+		return c.Value > 0
+	case CouponTypeFirstClassFree:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Coupon) IsFirstClassOnly() bool {
+	return c.Type == CouponTypeFirstClassFree
+}
+
+// Check verifica se o cupom pode ser usado na aula em now. Os limites de uso são
+// verificados no resgate.
+func (c *Coupon) Check(class *Class, now time.Time) error {
+	if !c.Active {
+		return ErrCouponInactive
+	}
+	if c.ValidFrom != nil && now.Before(*c.ValidFrom) {
+		return ErrCouponInactive
+	}
+	if c.ValidUntil != nil && !now.Before(*c.ValidUntil) {
+		return ErrCouponInactive
+	}
+
+	if len(c.ClassIDs) > 0 && !slices.Contains(c.ClassIDs, class.ID) {
+		return ErrCouponNotApplicable
+	}
+	if len(c.InstructorIDs) > 0 && !slices.Contains(c.InstructorIDs, class.InstructorID) {
+		return ErrCouponNotApplicable
+	}
+
+	return nil
+}
+
+// Discount calcula o desconto sobre priceInCents, nunca maior que o próprio preço.
+func (c *Coupon) Discount(priceInCents int64) int64 {
+	var discount int64
+	switch c.Type {
+	case CouponTypePercent:
+		discount = priceInCents * c.Value / 100
+	case CouponTypeFixed:
+		discount = c.Value
+	case CouponTypeFirstClassFree:
+		discount = priceInCents
+	}
+	return min(discount, priceInCents)
+}
+
+// CouponRedemption registra o uso de um cupom em uma inscrição. O resgate é liberado
+// quando a inscrição não chega a ser paga, devolvendo o uso ao cupom e ao aluno.
+type CouponRedemption struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CouponID        primitive.ObjectID `json:"coupon_id" bson:"coupon_id"`
+	UserID          primitive.ObjectID `json:"user_id" bson:"user_id"`
+	EnrollmentID    primitive.ObjectID `json:"enrollment_id" bson:"enrollment_id"`
+	DiscountInCents int64              `json:"discount_in_cents" bson:"discount_in_cents"`
+	Status          string             `json:"status" bson:"status"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+func NewCouponRedemption(coupon *Coupon, userID, enrollmentID primitive.ObjectID, discountInCents int64) *CouponRedemption {
+	now := time.Now()
+	return &CouponRedemption{
+		ID:              primitive.NewObjectID(),
+		CouponID:        coupon.ID,
+		UserID:          userID,
+		EnrollmentID:    enrollmentID,
+		DiscountInCents: discountInCents,
+		Status:          CouponRedemptionStatusActive,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
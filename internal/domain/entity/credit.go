@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CreditLotStatusActive  = "active"
+	CreditLotStatusExpired = "expired"
+	CreditLotStatusRevoked = "revoked"
+)
+
+const (
+	CreditEntryPurchase = "purchase"
+	CreditEntryConsume  = "consume"
+	CreditEntryRestore  = "restore"
+	CreditEntryGrant    = "grant"
+	CreditEntryExpire   = "expire"
+	CreditEntryRevoke   = "revoke"
+)
+
+// CreditLot é um lote de créditos com a mesma validade, originado de uma compra de pacote
+// ou concedido pelo estúdio. Os créditos são consumidos do lote que vence primeiro.
+type CreditLot struct {
+	ID         primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	PurchaseID *primitive.ObjectID `json:"purchase_id,omitempty" bson:"purchase_id,omitempty"`
+	Total      int                 `json:"total" bson:"total"`
+	Remaining  int                 `json:"remaining" bson:"remaining"`
+	Status     string              `json:"status" bson:"status"`
+	ExpiresAt  time.Time           `json:"expires_at" bson:"expires_at"`
+	CreatedAt  time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+func NewCreditLot(userID primitive.ObjectID, credits int, expiresAt time.Time) *CreditLot {
+	now := time.Now()
+	return &CreditLot{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Total:     credits,
+		Remaining: credits,
+		Status:    CreditLotStatusActive,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NewPurchasedCreditLot cria o lote correspondente a uma compra de pacote aprovada.
+func NewPurchasedCreditLot(purchase *CreditPurchase, expiresAt time.Time) *CreditLot {
+	lot := NewCreditLot(purchase.UserID, purchase.Credits, expiresAt)
+	lot.PurchaseID = &purchase.ID
+	return lot
+}
+
+// CreditEntry é um lançamento no extrato de créditos do aluno. Amount é positivo para
+// entradas (compra, devolução) e negativo para saídas (uso, expiração, revogação).
+type CreditEntry struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID       primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	LotID        primitive.ObjectID  `json:"lot_id" bson:"lot_id"`
+	Type         string              `json:"type" bson:"type"`
+	Amount       int                 `json:"amount" bson:"amount"`
+	EnrollmentID *primitive.ObjectID `json:"enrollment_id,omitempty" bson:"enrollment_id,omitempty"`
+	CreatedAt    time.Time           `json:"created_at" bson:"created_at"`
+}
+
+func NewCreditEntry(userID, lotID primitive.ObjectID, entryType string, amount int, enrollmentID *primitive.ObjectID) *CreditEntry {
+	return &CreditEntry{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		LotID:        lotID,
+		Type:         entryType,
+		Amount:       amount,
+		EnrollmentID: enrollmentID,
+		CreatedAt:    time.Now(),
+	}
+}
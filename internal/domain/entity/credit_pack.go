@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CreditPurchaseStatusPending  = "pending"
+	CreditPurchaseStatusPaid     = "paid"
+	CreditPurchaseStatusFailed   = "failed"
+	CreditPurchaseStatusRefunded = "refunded"
+)
+
+// CreditPack é um pacote de aulas vendido por um preço fechado (por exemplo, 10 aulas).
+type CreditPack struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name         string             `json:"name" bson:"name"`
+	Description  string             `json:"description" bson:"description"`
+	Credits      int                `json:"credits" bson:"credits"`
+	PriceInCents int64              `json:"price_in_cents" bson:"price_in_cents"`
+	Active       bool               `json:"active" bson:"active"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+func NewCreditPack(name, description string, credits int, priceInCents int64) *CreditPack {
+	now := time.Now()
+	return &CreditPack{
+		ID:           primitive.NewObjectID(),
+		Name:         name,
+		Description:  description,
+		Credits:      credits,
+		PriceInCents: priceInCents,
+		Active:       true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// CreditPurchase é a compra de um pacote por um aluno. Os créditos só são liberados quando
+// o pagamento é aprovado.
+type CreditPurchase struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID       primitive.ObjectID `json:"user_id" bson:"user_id"`
+	PackID       primitive.ObjectID `json:"pack_id" bson:"pack_id"`
+	Credits      int                `json:"credits" bson:"credits"`
+	PriceInCents int64              `json:"price_in_cents" bson:"price_in_cents"`
+	Status       string             `json:"status" bson:"status"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+func NewCreditPurchase(userID primitive.ObjectID, pack *CreditPack) *CreditPurchase {
+	now := time.Now()
+	return &CreditPurchase{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		PackID:       pack.ID,
+		Credits:      pack.Credits,
+		PriceInCents: pack.PriceInCents,
+		Status:       CreditPurchaseStatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func (p *CreditPurchase) IsPending() bool {
+	return p.Status == CreditPurchaseStatusPending
+}
+
+func (p *CreditPurchase) IsPaid() bool {
+	return p.Status == CreditPurchaseStatusPaid
+}
+
+func (p *CreditPurchase) MarkPaid() {
+	p.Status = CreditPurchaseStatusPaid
+	p.UpdatedAt = time.Now()
+}
+
+func (p *CreditPurchase) MarkFailed() {
+	p.Status = CreditPurchaseStatusFailed
+	p.UpdatedAt = time.Now()
+}
+
+func (p *CreditPurchase) MarkRefunded() {
+	p.Status = CreditPurchaseStatusRefunded
+	p.UpdatedAt = time.Now()
+}
@@ -6,16 +6,29 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	EnrollmentStatusPending   = "pending"
+	EnrollmentStatusConfirmed = "confirmed"
+	EnrollmentStatusCancelled = "cancelled"
+	EnrollmentStatusRejected  = "rejected"
+	EnrollmentStatusExpired   = "expired"
+)
+
 type Enrollment struct {
-	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	UserID       primitive.ObjectID `json:"user_id" bson:"user_id"`
-	ClassID      primitive.ObjectID `json:"class_id" bson:"class_id"`
-	PaymentID    string             `json:"payment_id" bson:"payment_id"`
-	Status       string             `json:"status" bson:"status"`
-	EnrolledAt   time.Time          `json:"enrolled_at" bson:"enrolled_at"`
-	CancelledAt  *time.Time         `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
-	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+	ID           primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID       primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	ClassID      primitive.ObjectID  `json:"class_id" bson:"class_id"`
+	PaymentID    string              `json:"payment_id" bson:"payment_id"`
+	CreditLotID  *primitive.ObjectID `json:"credit_lot_id,omitempty" bson:"credit_lot_id,omitempty"`
+	MembershipID *primitive.ObjectID `json:"membership_id,omitempty" bson:"membership_id,omitempty"`
+	Status       string              `json:"status" bson:"status"`
+	EnrolledAt   time.Time           `json:"enrolled_at" bson:"enrolled_at"`
+	CancelledAt  *time.Time          `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+	ExpiresAt    *time.Time          `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	EnrolledBy   *primitive.ObjectID `json:"enrolled_by,omitempty" bson:"enrolled_by,omitempty"`
+	CancelledBy  *primitive.ObjectID `json:"cancelled_by,omitempty" bson:"cancelled_by,omitempty"`
+	CreatedAt    time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at" bson:"updated_at"`
 }
 
 func NewEnrollment(userID, classID primitive.ObjectID) *Enrollment {
@@ -24,23 +37,90 @@ func NewEnrollment(userID, classID primitive.ObjectID) *Enrollment {
 		ID:        primitive.NewObjectID(),
 		UserID:    userID,
 		ClassID:   classID,
-		Status:    "pending",
+		Status:    EnrollmentStatusPending,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 }
 
 func (e *Enrollment) Confirm(paymentID string) {
-	e.Status = "confirmed"
+	e.Status = EnrollmentStatusConfirmed
 	e.PaymentID = paymentID
 	e.EnrolledAt = time.Now()
 	e.UpdatedAt = time.Now()
 }
 
+// ConfirmWithCredit confirma a inscrição paga com um crédito do lote informado.
+func (e *Enrollment) ConfirmWithCredit(lotID primitive.ObjectID) {
+	e.Status = EnrollmentStatusConfirmed
+	e.CreditLotID = &lotID
+	e.EnrolledAt = time.Now()
+	e.UpdatedAt = time.Now()
+}
+
+func (e *Enrollment) PaidWithCredit() bool {
+	return e.CreditLotID != nil
+}
+
+// ConfirmWithMembership confirma a inscrição coberta pela assinatura mensal do aluno.
+func (e *Enrollment) ConfirmWithMembership(membershipID primitive.ObjectID) {
+	e.Status = EnrollmentStatusConfirmed
+	e.MembershipID = &membershipID
+	e.EnrolledAt = time.Now()
+	e.UpdatedAt = time.Now()
+}
+
+func (e *Enrollment) PaidWithMembership() bool {
+	return e.MembershipID != nil
+}
+
+// OnBehalfOf registra o administrador ou instrutor que fez a inscrição pelo aluno.
+func (e *Enrollment) OnBehalfOf(actorID primitive.ObjectID) {
+	e.EnrolledBy = &actorID
+	e.UpdatedAt = time.Now()
+}
+
+// CancelBy cancela a inscrição registrando quem solicitou o cancelamento.
+func (e *Enrollment) CancelBy(actorID primitive.ObjectID) {
+	e.Cancel()
+	e.CancelledBy = &actorID
+}
+
 func (e *Enrollment) Cancel() {
-	e.Status = "cancelled"
+	e.Status = EnrollmentStatusCancelled
 	now := time.Now()
 	e.CancelledAt = &now
 	e.UpdatedAt = now
 }
 
+// HoldUntil define até quando a vaga fica reservada aguardando pagamento.
+func (e *Enrollment) HoldUntil(expiresAt time.Time) {
+	e.ExpiresAt = &expiresAt
+	e.UpdatedAt = time.Now()
+}
+
+func (e *Enrollment) Expire() {
+	e.Status = EnrollmentStatusExpired
+	e.UpdatedAt = time.Now()
+}
+
+func (e *Enrollment) IsExpired() bool {
+	return e.Status == EnrollmentStatusExpired
+}
+
+func (e *Enrollment) Reject() {
+	e.Status = EnrollmentStatusRejected
+	e.UpdatedAt = time.Now()
+}
+
+func (e *Enrollment) IsPending() bool {
+	return e.Status == EnrollmentStatusPending
+}
+
+func (e *Enrollment) IsCancelled() bool {
+	return e.Status == EnrollmentStatusCancelled
+}
+
+func (e *Enrollment) IsConfirmed() bool {
+	return e.Status == EnrollmentStatusConfirmed
+}
@@ -0,0 +1,132 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MembershipStatusPending    = "pending"
+	MembershipStatusAuthorized = "authorized"
+	MembershipStatusPaused     = "paused"
+	MembershipStatusCancelled  = "cancelled"
+)
+
+// MembershipPlan é um plano mensal cobrado automaticamente. ClassesPerMonth igual a zero
+// indica aulas ilimitadas.
+type MembershipPlan struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name            string             `json:"name" bson:"name"`
+	Description     string             `json:"description" bson:"description"`
+	PriceInCents    int64              `json:"price_in_cents" bson:"price_in_cents"`
+	ClassesPerMonth int                `json:"classes_per_month" bson:"classes_per_month"`
+	Active          bool               `json:"active" bson:"active"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+func NewMembershipPlan(name, description string, priceInCents int64, classesPerMonth int) *MembershipPlan {
+	now := time.Now()
+	return &MembershipPlan{
+		ID:              primitive.NewObjectID(),
+		Name:            name,
+		Description:     description,
+		PriceInCents:    priceInCents,
+		ClassesPerMonth: classesPerMonth,
+		Active:          true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Membership é a assinatura de um aluno a um plano, vinculada a uma preapproval do
+// Mercado Pago. O período vigente começa a cada cobrança aprovada e dura um mês; o limite
+// de aulas e o preço são os do plano na época da assinatura.
+type Membership struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID          primitive.ObjectID `json:"user_id" bson:"user_id"`
+	PlanID          primitive.ObjectID `json:"plan_id" bson:"plan_id"`
+	PreapprovalID   string             `json:"preapproval_id,omitempty" bson:"preapproval_id,omitempty"`
+	Status          string             `json:"status" bson:"status"`
+	PriceInCents    int64              `json:"price_in_cents" bson:"price_in_cents"`
+	ClassesPerMonth int                `json:"classes_per_month" bson:"classes_per_month"`
+	ClassesUsed     int                `json:"classes_used" bson:"classes_used"`
+	PeriodStart     *time.Time         `json:"period_start,omitempty" bson:"period_start,omitempty"`
+	PeriodEnd       *time.Time         `json:"period_end,omitempty" bson:"period_end,omitempty"`
+	LastPaymentID   string             `json:"last_payment_id,omitempty" bson:"last_payment_id,omitempty"`
+	CheckoutURL     string             `json:"checkout_url,omitempty" bson:"checkout_url,omitempty"`
+	CancelledAt     *time.Time         `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+func NewMembership(userID primitive.ObjectID, plan *MembershipPlan) *Membership {
+	now := time.Now()
+	return &Membership{
+		ID:              primitive.NewObjectID(),
+		UserID:          userID,
+		PlanID:          plan.ID,
+		Status:          MembershipStatusPending,
+		PriceInCents:    plan.PriceInCents,
+		ClassesPerMonth: plan.ClassesPerMonth,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+func (m *Membership) SetPreapproval(preapprovalID, checkoutURL string) {
+	m.PreapprovalID = preapprovalID
+	m.CheckoutURL = checkoutURL
+	m.UpdatedAt = time.Now()
+}
+
+// UpdateStatus aplica o status da preapproval informado pelo gateway.
+func (m *Membership) UpdateStatus(status string) {
+	if status == MembershipStatusCancelled && m.CancelledAt == nil {
+		now := time.Now()
+		m.CancelledAt = &now
+	}
+	m.Status = status
+	m.UpdatedAt = time.Now()
+}
+
+// StartPeriod inicia um novo mês de assinatura a partir da cobrança aprovada em paidAt,
+// zerando as aulas usadas.
+func (m *Membership) StartPeriod(paymentID string, paidAt time.Time) {
+	end := paidAt.AddDate(0, 1, 0)
+	m.LastPaymentID = paymentID
+	m.PeriodStart = &paidAt
+	m.PeriodEnd = &end
+	m.ClassesUsed = 0
+	m.UpdatedAt = time.Now()
+}
+
+// StartsNewPeriod indica se a cobrança aprovada ainda não foi aplicada e é mais recente que
+// o período vigente. Notificações repetidas ou fora de ordem são ignoradas.
+func (m *Membership) StartsNewPeriod(paymentID string, paidAt time.Time) bool {
+	if m.LastPaymentID == paymentID {
+		return false
+	}
+	return m.PeriodStart == nil || paidAt.After(*m.PeriodStart)
+}
+
+// IsOpen indica que a assinatura ainda gera cobranças ou aguarda a autorização do aluno.
+func (m *Membership) IsOpen() bool {
+	return m.Status == MembershipStatusPending || m.Status == MembershipStatusAuthorized || m.Status == MembershipStatusPaused
+}
+
+// IsActive indica que há um período pago em vigor. Uma assinatura cancelada continua
+// ativa até o fim do mês já pago.
+func (m *Membership) IsActive(now time.Time) bool {
+	return m.PeriodStart != nil && m.PeriodEnd != nil && !now.Before(*m.PeriodStart) && now.Before(*m.PeriodEnd)
+}
+
+func (m *Membership) Unlimited() bool {
+	return m.ClassesPerMonth == 0
+}
+
+// HasClassesLeft indica se ainda há aulas disponíveis no período vigente.
+func (m *Membership) HasClassesLeft() bool {
+	return m.Unlimited() || m.ClassesUsed < m.ClassesPerMonth
+}
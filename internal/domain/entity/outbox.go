@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OutboxTypeCreateCheckout = "create_checkout"
+	OutboxTypeRefund         = "refund"
+
+	OutboxStatusPending   = "pending"
+	OutboxStatusProcessed = "processed"
+	OutboxStatusFailed    = "failed"
+)
+
+// OutboxMessage registra uma chamada externa pendente gravada na mesma transação
+// que a alteração de estado que a originou.
+type OutboxMessage struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Type          string             `json:"type" bson:"type"`
+	AggregateID   primitive.ObjectID `json:"aggregate_id" bson:"aggregate_id"`
+	Status        string             `json:"status" bson:"status"`
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	LastError     string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttemptAt time.Time          `json:"next_attempt_at" bson:"next_attempt_at"`
+	ProcessedAt   *time.Time         `json:"processed_at,omitempty" bson:"processed_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+func NewOutboxMessage(messageType string, aggregateID primitive.ObjectID) *OutboxMessage {
+	now := time.Now()
+	return &OutboxMessage{
+		ID:            primitive.NewObjectID(),
+		Type:          messageType,
+		AggregateID:   aggregateID,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Lease reserva a mensagem para uma tentativa até o instante informado.
+func (m *OutboxMessage) Lease(until time.Time) {
+	m.Attempts++
+	m.NextAttemptAt = until
+	m.UpdatedAt = time.Now()
+}
+
+func (m *OutboxMessage) MarkProcessed() {
+	now := time.Now()
+	m.Status = OutboxStatusProcessed
+	m.LastError = ""
+	m.ProcessedAt = &now
+	m.UpdatedAt = now
+}
+
+func (m *OutboxMessage) RetryAt(err error, at time.Time) {
+	m.LastError = err.Error()
+	m.NextAttemptAt = at
+	m.UpdatedAt = time.Now()
+}
+
+func (m *OutboxMessage) MarkFailed(err error) {
+	m.Status = OutboxStatusFailed
+	m.LastError = err.Error()
+	m.UpdatedAt = time.Now()
+}
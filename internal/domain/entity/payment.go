@@ -6,17 +6,50 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	PaymentStatusPending           = "pending"
+	PaymentStatusApproved          = "approved"
+	PaymentStatusInProcess         = "in_process"
+	PaymentStatusRejected          = "rejected"
+	PaymentStatusCancelled         = "cancelled"
+	PaymentStatusRefunded          = "refunded"
+	PaymentStatusPartiallyRefunded = "partially_refunded"
+	PaymentStatusChargedBack       = "charged_back"
+	PaymentStatusExpired           = "expired"
+
+	PaymentChannelCheckout = "checkout"
+	PaymentChannelPix      = "pix"
+)
+
 type Payment struct {
-	ID                  primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	EnrollmentID        primitive.ObjectID `json:"enrollment_id" bson:"enrollment_id"`
-	MercadoPagoID       string             `json:"mercado_pago_id" bson:"mercado_pago_id"`
-	Status              string             `json:"status" bson:"status"`
-	AmountInCents       int64              `json:"amount_in_cents" bson:"amount_in_cents"`
-	PaymentMethod       string             `json:"payment_method" bson:"payment_method"`
-	PreferenceID        string             `json:"preference_id" bson:"preference_id"`
-	InitPointURL        string             `json:"init_point_url" bson:"init_point_url"`
-	CreatedAt           time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt           time.Time          `json:"updated_at" bson:"updated_at"`
+	ID               primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	EnrollmentID     primitive.ObjectID  `json:"enrollment_id" bson:"enrollment_id,omitempty"`
+	CreditPurchaseID *primitive.ObjectID `json:"credit_purchase_id,omitempty" bson:"credit_purchase_id,omitempty"`
+	MembershipID     *primitive.ObjectID `json:"membership_id,omitempty" bson:"membership_id,omitempty"`
+	MercadoPagoID    string              `json:"mercado_pago_id" bson:"mercado_pago_id"`
+	Status           string              `json:"status" bson:"status"`
+	AmountInCents    int64               `json:"amount_in_cents" bson:"amount_in_cents"`
+	DiscountInCents  int64               `json:"discount_in_cents,omitempty" bson:"discount_in_cents,omitempty"`
+	CouponID         *primitive.ObjectID `json:"coupon_id,omitempty" bson:"coupon_id,omitempty"`
+	CouponCode       string              `json:"coupon_code,omitempty" bson:"coupon_code,omitempty"`
+	RefundedInCents  int64               `json:"refunded_in_cents" bson:"refunded_in_cents"`
+	PaymentMethod    string              `json:"payment_method" bson:"payment_method"`
+	PreferenceID     string              `json:"preference_id" bson:"preference_id"`
+	InitPointURL     string              `json:"init_point_url" bson:"init_point_url"`
+	Channel          string              `json:"channel,omitempty" bson:"channel,omitempty"`
+	Pix              *PixCharge          `json:"-" bson:"pix,omitempty"`
+	ApprovedAt       *time.Time          `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
+	CreatedAt        time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// PixCharge guarda o QR code de um pagamento Pix criado diretamente no gateway. QRCode é
+// o código copia e cola e QRCodeBase64 a imagem PNG do QR code.
+type PixCharge struct {
+	QRCode       string    `json:"qr_code" bson:"qr_code"`
+	QRCodeBase64 string    `json:"qr_code_base64" bson:"qr_code_base64"`
+	TicketURL    string    `json:"ticket_url,omitempty" bson:"ticket_url,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at" bson:"expires_at"`
 }
 
 func NewPayment(enrollmentID primitive.ObjectID, amountInCents int64) *Payment {
@@ -24,18 +57,85 @@ func NewPayment(enrollmentID primitive.ObjectID, amountInCents int64) *Payment {
 	return &Payment{
 		ID:            primitive.NewObjectID(),
 		EnrollmentID:  enrollmentID,
-		Status:        "pending",
+		Status:        PaymentStatusPending,
 		AmountInCents: amountInCents,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
 }
 
+// NewCreditPurchasePayment cria o pagamento da compra de um pacote de créditos.
+func NewCreditPurchasePayment(purchase *CreditPurchase) *Payment {
+	now := time.Now()
+	return &Payment{
+		ID:               primitive.NewObjectID(),
+		CreditPurchaseID: &purchase.ID,
+		Status:           PaymentStatusPending,
+		AmountInCents:    purchase.PriceInCents,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// NewMembershipPayment registra uma cobrança mensal já processada pelo gateway na
+// assinatura informada.
+func NewMembershipPayment(membership *Membership, mpID, status string, amountInCents int64) *Payment {
+	now := time.Now()
+	payment := &Payment{
+		ID:            primitive.NewObjectID(),
+		MembershipID:  &membership.ID,
+		MercadoPagoID: mpID,
+		Status:        status,
+		AmountInCents: amountInCents,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if status == PaymentStatusApproved {
+		payment.ApprovedAt = &now
+	}
+	return payment
+}
+
+// ExternalRef é a referência enviada ao gateway: a inscrição, a compra de créditos ou a
+// assinatura paga.
+func (p *Payment) ExternalRef() string {
+	if p.CreditPurchaseID != nil {
+		return p.CreditPurchaseID.Hex()
+	}
+	if p.MembershipID != nil {
+		return p.MembershipID.Hex()
+	}
+	return p.EnrollmentID.Hex()
+}
+
+// ApplyCoupon abate o desconto do cupom; AmountInCents passa a ser o preço final cobrado.
+func (p *Payment) ApplyCoupon(coupon *Coupon, discountInCents int64) {
+	p.CouponID = &coupon.ID
+	p.CouponCode = coupon.Code
+	p.DiscountInCents = discountInCents
+	p.AmountInCents -= discountInCents
+	p.UpdatedAt = time.Now()
+}
+
+// MarkFree quita um pagamento zerado pelo desconto, sem passar pelo gateway.
+func (p *Payment) MarkFree() {
+	now := time.Now()
+	p.Status = PaymentStatusApproved
+	p.PaymentMethod = "coupon"
+	p.ApprovedAt = &now
+	p.UpdatedAt = now
+}
+
+// UpdateFromMercadoPago aplica o status do gateway e registra o momento da primeira aprovação.
 func (p *Payment) UpdateFromMercadoPago(mpID, status, paymentMethod string) {
+	now := time.Now()
 	p.MercadoPagoID = mpID
 	p.Status = status
 	p.PaymentMethod = paymentMethod
-	p.UpdatedAt = time.Now()
+	if status == PaymentStatusApproved && p.ApprovedAt == nil {
+		p.ApprovedAt = &now
+	}
+	p.UpdatedAt = now
 }
 
 func (p *Payment) SetPreference(preferenceID, initPointURL string) {
@@ -44,3 +144,88 @@ func (p *Payment) SetPreference(preferenceID, initPointURL string) {
 	p.UpdatedAt = time.Now()
 }
 
+// UsePix indica que o pagamento será feito por Pix, sem o redirecionamento do checkout.
+func (p *Payment) UsePix() {
+	p.Channel = PaymentChannelPix
+	p.UpdatedAt = time.Now()
+}
+
+func (p *Payment) IsPix() bool {
+	return p.Channel == PaymentChannelPix
+}
+
+// SetPix registra o pagamento Pix criado no gateway e o QR code que o aluno deve pagar.
+func (p *Payment) SetPix(mpID string, pix *PixCharge) {
+	p.MercadoPagoID = mpID
+	p.PaymentMethod = PaymentChannelPix
+	p.Pix = pix
+	p.UpdatedAt = time.Now()
+}
+
+// CheckoutCreated indica que o gateway já devolveu o link de pagamento ou o QR code Pix.
+func (p *Payment) CheckoutCreated() bool {
+	return p.PreferenceID != "" || p.Pix != nil
+}
+
+func (p *Payment) MarkCancelled() {
+	p.Status = PaymentStatusCancelled
+	p.UpdatedAt = time.Now()
+}
+
+func (p *Payment) MarkExpired() {
+	p.Status = PaymentStatusExpired
+	p.UpdatedAt = time.Now()
+}
+
+// ApplyRefund soma um estorno ao total devolvido.
+func (p *Payment) ApplyRefund(amountInCents int64) {
+	p.SetRefunded(p.RefundedInCents + amountInCents)
+}
+
+// SetRefunded define o total já devolvido e ajusta o status para estorno parcial ou integral.
+func (p *Payment) SetRefunded(totalInCents int64) {
+	if totalInCents <= 0 {
+		return
+	}
+
+	if totalInCents >= p.AmountInCents {
+		p.RefundedInCents = p.AmountInCents
+		p.Status = PaymentStatusRefunded
+	} else {
+		p.RefundedInCents = totalInCents
+		p.Status = PaymentStatusPartiallyRefunded
+	}
+	p.UpdatedAt = time.Now()
+}
+
+// RefundableInCents retorna o saldo que ainda pode ser estornado.
+func (p *Payment) RefundableInCents() int64 {
+	if p.Status != PaymentStatusApproved && p.Status != PaymentStatusPartiallyRefunded {
+		return 0
+	}
+	return p.AmountInCents - p.RefundedInCents
+}
+
+// IsPaid indica que o pagamento está aprovado, ainda que parcialmente estornado.
+func (p *Payment) IsPaid() bool {
+	return p.IsApproved() || p.Status == PaymentStatusPartiallyRefunded
+}
+
+// IsAwaiting indica que o pagamento ainda não foi concluído no gateway.
+func (p *Payment) IsAwaiting() bool {
+	return p.Status == PaymentStatusPending || p.Status == PaymentStatusInProcess
+}
+
+func (p *Payment) IsApproved() bool {
+	return p.Status == PaymentStatusApproved
+}
+
+// IsFailed indica que o pagamento não foi concluído e a vaga pode ser liberada.
+func (p *Payment) IsFailed() bool {
+	return p.Status == PaymentStatusRejected || p.Status == PaymentStatusCancelled
+}
+
+// IsReversed indica que um pagamento aprovado foi devolvido ao comprador.
+func (p *Payment) IsReversed() bool {
+	return p.Status == PaymentStatusRefunded || p.Status == PaymentStatusChargedBack
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Receipt é o recibo de um pagamento aprovado. Os dados do estúdio, do aluno e do item
+// são copiados na emissão, de modo que o recibo não muda se o cadastro for alterado
+// depois. Number é sequencial e único entre todos os recibos.
+type Receipt struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Number          int64              `json:"number" bson:"number"`
+	PaymentID       primitive.ObjectID `json:"payment_id" bson:"payment_id"`
+	UserID          primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Studio          ReceiptStudio      `json:"studio" bson:"studio"`
+	Student         ReceiptStudent     `json:"student" bson:"student"`
+	Item            ReceiptItem        `json:"item" bson:"item"`
+	AmountInCents   int64              `json:"amount_in_cents" bson:"amount_in_cents"`
+	DiscountInCents int64              `json:"discount_in_cents,omitempty" bson:"discount_in_cents,omitempty"`
+	CouponCode      string             `json:"coupon_code,omitempty" bson:"coupon_code,omitempty"`
+	PaymentMethod   string             `json:"payment_method" bson:"payment_method"`
+	MercadoPagoID   string             `json:"mercado_pago_id,omitempty" bson:"mercado_pago_id,omitempty"`
+	PaidAt          time.Time          `json:"paid_at" bson:"paid_at"`
+	IssuedAt        time.Time          `json:"issued_at" bson:"issued_at"`
+}
+
+type ReceiptStudio struct {
+	Name     string `json:"name" bson:"name"`
+	Document string `json:"document,omitempty" bson:"document,omitempty"`
+	Address  string `json:"address,omitempty" bson:"address,omitempty"`
+	Email    string `json:"email,omitempty" bson:"email,omitempty"`
+}
+
+type ReceiptStudent struct {
+	Name  string `json:"name" bson:"name"`
+	Email string `json:"email" bson:"email"`
+	CPF   string `json:"cpf,omitempty" bson:"cpf,omitempty"`
+}
+
+// ReceiptItem descreve o que foi pago: uma aula, um pacote de créditos ou uma mensalidade.
+type ReceiptItem struct {
+	Description string `json:"description" bson:"description"`
+	Details     string `json:"details,omitempty" bson:"details,omitempty"`
+}
+
+// NewReceipt monta o recibo do pagamento, ainda sem número.
+func NewReceipt(payment *Payment, user *User, studio ReceiptStudio, item ReceiptItem) *Receipt {
+	now := time.Now()
+	paidAt := payment.UpdatedAt
+	if payment.ApprovedAt != nil {
+		paidAt = *payment.ApprovedAt
+	}
+
+	return &Receipt{
+		ID:        primitive.NewObjectID(),
+		PaymentID: payment.ID,
+		UserID:    user.ID,
+		Studio:    studio,
+		Student: ReceiptStudent{
+			Name:  user.Name,
+			Email: user.Email,
+			CPF:   user.CPF,
+		},
+		Item:            item,
+		AmountInCents:   payment.AmountInCents,
+		DiscountInCents: payment.DiscountInCents,
+		CouponCode:      payment.CouponCode,
+		PaymentMethod:   payment.PaymentMethod,
+		MercadoPagoID:   payment.MercadoPagoID,
+		PaidAt:          paidAt,
+		IssuedAt:        now,
+	}
+}
+
+// Code é o número do recibo formatado para exibição.
+func (r *Receipt) Code() string {
+	return fmt.Sprintf("%06d", r.Number)
+}
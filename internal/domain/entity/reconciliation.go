@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ReconciliationTriggerWorker = "worker"
+	ReconciliationTriggerManual = "manual"
+
+	// DiscrepancyPaidNotConfirmed: o gateway aprovou um pagamento que não consta como pago.
+	DiscrepancyPaidNotConfirmed = "paid_not_confirmed"
+	// DiscrepancyConfirmedNotPaid: o pagamento consta como pago, mas não foi aprovado no gateway.
+	DiscrepancyConfirmedNotPaid = "confirmed_not_paid"
+	// DiscrepancyStatusMismatch: qualquer outra diferença de status ou de valor estornado.
+	DiscrepancyStatusMismatch = "status_mismatch"
+)
+
+// ReconciliationReport registra uma execução da conciliação de pagamentos com o gateway.
+type ReconciliationReport struct {
+	ID            primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Trigger       string               `json:"trigger" bson:"trigger"`
+	RequestedBy   *primitive.ObjectID  `json:"requested_by,omitempty" bson:"requested_by,omitempty"`
+	Since         time.Time            `json:"since" bson:"since"`
+	Checked       int                  `json:"checked" bson:"checked"`
+	Corrected     int                  `json:"corrected" bson:"corrected"`
+	Unresolved    int                  `json:"unresolved" bson:"unresolved"`
+	Discrepancies []PaymentDiscrepancy `json:"discrepancies" bson:"discrepancies"`
+	StartedAt     time.Time            `json:"started_at" bson:"started_at"`
+	FinishedAt    time.Time            `json:"finished_at" bson:"finished_at"`
+}
+
+// PaymentDiscrepancy descreve a diferença encontrada em um pagamento. Corrected indica que
+// o estado local foi atualizado a partir do gateway; sem correção, Error explica o motivo.
+type PaymentDiscrepancy struct {
+	PaymentID            primitive.ObjectID `json:"payment_id" bson:"payment_id"`
+	ExternalRef          string             `json:"external_reference" bson:"external_reference"`
+	MercadoPagoID        string             `json:"mercado_pago_id,omitempty" bson:"mercado_pago_id,omitempty"`
+	Kind                 string             `json:"kind" bson:"kind"`
+	LocalStatus          string             `json:"local_status" bson:"local_status"`
+	GatewayStatus        string             `json:"gateway_status,omitempty" bson:"gateway_status,omitempty"`
+	LocalAmountInCents   int64              `json:"local_amount_in_cents" bson:"local_amount_in_cents"`
+	GatewayAmountInCents int64              `json:"gateway_amount_in_cents,omitempty" bson:"gateway_amount_in_cents,omitempty"`
+	Corrected            bool               `json:"corrected" bson:"corrected"`
+	Error                string             `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+func NewReconciliationReport(trigger string, requestedBy *primitive.ObjectID, since time.Time) *ReconciliationReport {
+	return &ReconciliationReport{
+		ID:            primitive.NewObjectID(),
+		Trigger:       trigger,
+		RequestedBy:   requestedBy,
+		Since:         since,
+		Discrepancies: []PaymentDiscrepancy{},
+		StartedAt:     time.Now(),
+	}
+}
+
+func (r *ReconciliationReport) Add(discrepancy PaymentDiscrepancy) {
+	r.Discrepancies = append(r.Discrepancies, discrepancy)
+	if discrepancy.Corrected {
+		r.Corrected++
+	} else {
+		r.Unresolved++
+	}
+}
+
+func (r *ReconciliationReport) Finish() {
+	r.FinishedAt = time.Now()
+}
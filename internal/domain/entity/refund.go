@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RefundStatusPending    = "pending"
+	RefundStatusApproved   = "approved"
+	RefundStatusInProcess  = "in_process"
+	RefundStatusRejected   = "rejected"
+	RefundStatusCancelled  = "cancelled"
+	RefundStatusAuthorized = "authorized"
+	RefundStatusFailed     = "failed"
+)
+
+// Refund registra um estorno, total ou parcial, de um pagamento. Estornos feitos
+// diretamente no painel do Mercado Pago são registrados ao processar o webhook e não
+// têm RequestedBy.
+type Refund struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	PaymentID     primitive.ObjectID  `json:"payment_id" bson:"payment_id"`
+	MercadoPagoID string              `json:"mercado_pago_id,omitempty" bson:"mercado_pago_id,omitempty"`
+	AmountInCents int64               `json:"amount_in_cents" bson:"amount_in_cents"`
+	Status        string              `json:"status" bson:"status"`
+	Reason        string              `json:"reason,omitempty" bson:"reason,omitempty"`
+	RequestedBy   *primitive.ObjectID `json:"requested_by,omitempty" bson:"requested_by,omitempty"`
+	LastError     string              `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	CreatedAt     time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+func NewRefund(paymentID primitive.ObjectID, amountInCents int64, reason string, requestedBy *primitive.ObjectID) *Refund {
+	now := time.Now()
+	return &Refund{
+		ID:            primitive.NewObjectID(),
+		PaymentID:     paymentID,
+		AmountInCents: amountInCents,
+		Status:        RefundStatusPending,
+		Reason:        reason,
+		RequestedBy:   requestedBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// UpdateFromMercadoPago aplica o id, o status e o valor efetivamente estornado pelo gateway.
+func (r *Refund) UpdateFromMercadoPago(mpID, status string, amountInCents int64) {
+	r.MercadoPagoID = mpID
+	r.Status = status
+	if amountInCents > 0 {
+		r.AmountInCents = amountInCents
+	}
+	r.LastError = ""
+	r.UpdatedAt = time.Now()
+}
+
+func (r *Refund) Fail(reason string) {
+	r.Status = RefundStatusFailed
+	r.LastError = reason
+	r.UpdatedAt = time.Now()
+}
+
+// Counts indica que o valor saiu (ou está saindo) da conta e deve ser abatido do pagamento.
+func (r *Refund) Counts() bool {
+	return r.Status == RefundStatusApproved || r.Status == RefundStatusInProcess || r.Status == RefundStatusAuthorized
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SessionRevokedLogout          = "logout"
+	SessionRevokedByUser          = "revoked_by_user"
+	SessionRevokedReuse           = "refresh_token_reuse"
+	SessionRevokedPasswordChanged = "password_changed"
+	SessionRevokedRoleChanged     = "role_changed"
+	SessionRevokedUserDeleted     = "user_deleted"
+)
+
+// Session é uma família de refresh tokens: nasce no login e acompanha cada rotação até
+// expirar ou ser revogada. Revogar a sessão invalida todos os tokens da família.
+type Session struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `json:"user_id" bson:"user_id"`
+	UserAgent     string             `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	IP            string             `json:"ip,omitempty" bson:"ip,omitempty"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	LastUsedAt    time.Time          `json:"last_used_at" bson:"last_used_at"`
+	ExpiresAt     time.Time          `json:"expires_at" bson:"expires_at"`
+	RevokedAt     *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	RevokedReason string             `json:"revoked_reason,omitempty" bson:"revoked_reason,omitempty"`
+}
+
+func NewSession(userID primitive.ObjectID, userAgent, ip string, expiresAt time.Time) *Session {
+	now := time.Now()
+	return &Session{
+		ID:         primitive.NewObjectID(),
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  expiresAt,
+	}
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Touch registra uma rotação: o uso mais recente e o novo prazo da sessão.
+func (s *Session) Touch(userAgent, ip string, expiresAt time.Time) {
+	s.LastUsedAt = time.Now()
+	s.ExpiresAt = expiresAt
+	if userAgent != "" {
+		s.UserAgent = userAgent
+	}
+	if ip != "" {
+		s.IP = ip
+	}
+}
+
+// RefreshToken guarda apenas o hash do token entregue ao cliente. Um token já rotacionado
+// (RotatedAt preenchido) que volta a ser apresentado indica vazamento.
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	SessionID primitive.ObjectID `bson:"session_id"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	TokenHash string             `bson:"token_hash"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+	RotatedAt *time.Time         `bson:"rotated_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+func NewRefreshToken(session *Session, tokenHash string) *RefreshToken {
+	return &RefreshToken{
+		ID:        primitive.NewObjectID(),
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		TokenHash: tokenHash,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+}
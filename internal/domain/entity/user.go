@@ -19,8 +19,10 @@ type User struct {
 	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name         string             `json:"name" bson:"name"`
 	Email        string             `json:"email" bson:"email"`
+	CPF          string             `json:"cpf,omitempty" bson:"cpf,omitempty"`
 	PasswordHash string             `json:"-" bson:"password_hash"`
 	Role         UserRole           `json:"role" bson:"role"`
+	TokenVersion int                `json:"-" bson:"token_version"`
 	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
 }
@@ -63,6 +65,57 @@ func (u *User) Update(name, email string, role UserRole) {
 	u.UpdatedAt = time.Now()
 }
 
+// SetCPF grava o CPF já normalizado por NormalizeCPF; vazio remove o documento.
+func (u *User) SetCPF(cpf string) {
+	u.CPF = cpf
+	u.UpdatedAt = time.Now()
+}
+
+// NormalizeCPF remove a pontuação do CPF e verifica os dígitos verificadores. CPF vazio é
+// aceito, pois o documento é opcional no cadastro.
+func NormalizeCPF(cpf string) (string, bool) {
+	digits := make([]byte, 0, 11)
+	for i := 0; i < len(cpf); i++ {
+		switch c := cpf[i]; {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case c == '.' || c == '-' || c == ' ':
+		default:
+			return "", false
+		}
+	}
+	if len(digits) == 0 {
+		return "", true
+	}
+	if len(digits) != 11 {
+		return "", false
+	}
+
+	repeated := true
+	for _, d := range digits[1:] {
+		if d != digits[0] {
+			repeated = false
+			break
+		}
+	}
+	if repeated {
+		return "", false
+	}
+
+	for _, length := range []int{9, 10} {
+		sum := 0
+		for i := 0; i < length; i++ {
+			sum += int(digits[i]-'0') * (length + 1 - i)
+		}
+		check := sum * 10 % 11 % 10
+		if check != int(digits[length]-'0') {
+			return "", false
+		}
+	}
+
+	return string(digits), true
+}
+
 func (u *User) SetPassword(password string) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
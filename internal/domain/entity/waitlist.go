@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	WaitlistStatusWaiting   = "waiting"
+	WaitlistStatusOffered   = "offered"
+	WaitlistStatusClaimed   = "claimed"
+	WaitlistStatusSkipped   = "skipped"
+	WaitlistStatusLeft      = "left"
+	WaitlistStatusCancelled = "cancelled"
+)
+
+type WaitlistEntry struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ClassID        primitive.ObjectID  `json:"class_id" bson:"class_id"`
+	UserID         primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	Status         string              `json:"status" bson:"status"`
+	UseCredit      bool                `json:"use_credit" bson:"use_credit"`
+	EnrollmentID   *primitive.ObjectID `json:"enrollment_id,omitempty" bson:"enrollment_id,omitempty"`
+	OfferExpiresAt *time.Time          `json:"offer_expires_at,omitempty" bson:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+func NewWaitlistEntry(classID, userID primitive.ObjectID) *WaitlistEntry {
+	now := time.Now()
+	return &WaitlistEntry{
+		ID:        primitive.NewObjectID(),
+		ClassID:   classID,
+		UserID:    userID,
+		Status:    WaitlistStatusWaiting,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (w *WaitlistEntry) IsWaiting() bool {
+	return w.Status == WaitlistStatusWaiting
+}
+
+func (w *WaitlistEntry) IsOffered() bool {
+	return w.Status == WaitlistStatusOffered
+}
+
+// Offer reserva a vaga liberada para o aluno até o fim da janela de confirmação.
+func (w *WaitlistEntry) Offer(enrollmentID primitive.ObjectID, expiresAt time.Time) {
+	w.Status = WaitlistStatusOffered
+	w.EnrollmentID = &enrollmentID
+	w.OfferExpiresAt = &expiresAt
+	w.UpdatedAt = time.Now()
+}
+
+// ClaimCovered confirma a vaga liberada sem oferta, quando a assinatura ou um crédito do
+// aluno paga a aula.
+func (w *WaitlistEntry) ClaimCovered(enrollmentID primitive.ObjectID) {
+	w.Status = WaitlistStatusClaimed
+	w.EnrollmentID = &enrollmentID
+	w.UpdatedAt = time.Now()
+}
+
+func (w *WaitlistEntry) Claim() {
+	w.Status = WaitlistStatusClaimed
+	w.UpdatedAt = time.Now()
+}
+
+func (w *WaitlistEntry) Skip() {
+	w.Status = WaitlistStatusSkipped
+	w.UpdatedAt = time.Now()
+}
+
+func (w *WaitlistEntry) Leave() {
+	w.Status = WaitlistStatusLeft
+	w.UpdatedAt = time.Now()
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	WebhookSourceMercadoPago = "mercadopago"
+
+	// WebhookEventStatusPending: aguardando processamento, inclusive entre novas tentativas.
+	WebhookEventStatusPending   = "pending"
+	WebhookEventStatusProcessed = "processed"
+	// WebhookEventStatusFailed: tentativas esgotadas; só volta a ser processado por replay.
+	WebhookEventStatusFailed = "failed"
+)
+
+// WebhookEvent guarda uma notificação recebida exatamente como chegou, identificada pela
+// chave de deduplicação, junto com o andamento do seu processamento.
+type WebhookEvent struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Source        string              `json:"source" bson:"source"`
+	DedupKey      string              `json:"dedup_key" bson:"dedup_key"`
+	Type          string              `json:"type" bson:"type"`
+	Action        string              `json:"action,omitempty" bson:"action,omitempty"`
+	DataID        string              `json:"data_id,omitempty" bson:"data_id,omitempty"`
+	RequestID     string              `json:"request_id,omitempty" bson:"request_id,omitempty"`
+	Payload       string              `json:"payload" bson:"payload"`
+	Status        string              `json:"status" bson:"status"`
+	Attempts      int                 `json:"attempts" bson:"attempts"`
+	LastError     string              `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttemptAt time.Time           `json:"next_attempt_at" bson:"next_attempt_at"`
+	LockedUntil   *time.Time          `json:"-" bson:"locked_until"`
+	ProcessedAt   *time.Time          `json:"processed_at,omitempty" bson:"processed_at,omitempty"`
+	ReplayedBy    *primitive.ObjectID `json:"replayed_by,omitempty" bson:"replayed_by,omitempty"`
+	ReplayedAt    *time.Time          `json:"replayed_at,omitempty" bson:"replayed_at,omitempty"`
+	ReceivedAt    time.Time           `json:"received_at" bson:"received_at"`
+	UpdatedAt     time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+func NewWebhookEvent(source, dedupKey, eventType, action, dataID, requestID string, payload []byte) *WebhookEvent {
+	now := time.Now()
+	return &WebhookEvent{
+		ID:            primitive.NewObjectID(),
+		Source:        source,
+		DedupKey:      dedupKey,
+		Type:          eventType,
+		Action:        action,
+		DataID:        dataID,
+		RequestID:     requestID,
+		Payload:       string(payload),
+		Status:        WebhookEventStatusPending,
+		NextAttemptAt: now,
+		ReceivedAt:    now,
+		UpdatedAt:     now,
+	}
+}
+
+// Lease reserva o evento para uma tentativa até o instante informado. Diferente do
+// NextAttemptAt, a reserva não adia a próxima tentativa agendada, o que permite distinguir
+// um evento em processamento de um que só aguarda o intervalo entre tentativas.
+func (e *WebhookEvent) Lease(until time.Time) {
+	e.Attempts++
+	e.LockedUntil = &until
+	e.UpdatedAt = time.Now()
+}
+
+func (e *WebhookEvent) MarkProcessed() {
+	now := time.Now()
+	e.Status = WebhookEventStatusProcessed
+	e.LastError = ""
+	e.LockedUntil = nil
+	e.ProcessedAt = &now
+	e.UpdatedAt = now
+}
+
+func (e *WebhookEvent) RetryAt(err error, at time.Time) {
+	e.Status = WebhookEventStatusPending
+	e.LastError = err.Error()
+	e.NextAttemptAt = at
+	e.LockedUntil = nil
+	e.UpdatedAt = time.Now()
+}
+
+func (e *WebhookEvent) MarkFailed(err error) {
+	e.Status = WebhookEventStatusFailed
+	e.LastError = err.Error()
+	e.LockedUntil = nil
+	e.UpdatedAt = time.Now()
+}
+
+// Replay registra o reprocessamento manual e reinicia a contagem de tentativas, com a
+// tentativa já reservada contando como a primeira do novo ciclo.
+func (e *WebhookEvent) Replay(actorID primitive.ObjectID) {
+	now := time.Now()
+	e.Attempts = 1
+	e.ReplayedBy = &actorID
+	e.ReplayedAt = &now
+	e.UpdatedAt = now
+}
@@ -0,0 +1,43 @@
+package event
+
+import (
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClassAvailability é o estado de ocupação de uma aula publicado a cada alteração.
+// ID é crescente e permite que clientes retomem o stream a partir do último evento.
+type ClassAvailability struct {
+	ID              uint64             `json:"-"`
+	ClassID         primitive.ObjectID `json:"class_id"`
+	Status          string             `json:"status"`
+	MaxCapacity     int                `json:"max_capacity"`
+	CurrentEnrolled int                `json:"current_enrolled"`
+	RemainingSpots  int                `json:"remaining_spots"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+func NewClassAvailability(class *entity.Class) ClassAvailability {
+	remaining := class.MaxCapacity - class.CurrentEnrolled
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return ClassAvailability{
+		ClassID:         class.ID,
+		Status:          class.Status,
+		MaxCapacity:     class.MaxCapacity,
+		CurrentEnrolled: class.CurrentEnrolled,
+		RemainingSpots:  remaining,
+		UpdatedAt:       class.UpdatedAt,
+	}
+}
+
+// AvailabilityBroker distribui as mudanças de ocupação para os assinantes de cada aula.
+type AvailabilityBroker interface {
+	Publish(availability ClassAvailability)
+	Subscribe(classID primitive.ObjectID) (<-chan ClassAvailability, func())
+	LastID() uint64
+}
@@ -0,0 +1,20 @@
+package gateway
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notifier envia avisos aos usuários (e-mail, push etc.).
+type Notifier interface {
+	Notify(ctx context.Context, notification *Notification) error
+}
+
+type Notification struct {
+	UserID  primitive.ObjectID
+	Name    string
+	Email   string
+	Subject string
+	Message string
+}
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"context"
+	"time"
+)
+
+type PaymentGateway interface {
+	CreateCheckout(ctx context.Context, req *CheckoutRequest) (*Checkout, error)
+	CreatePixPayment(ctx context.Context, req *PixRequest) (*PixPayment, error)
+	GetPayment(ctx context.Context, paymentID string) (*PaymentInfo, error)
+	SearchPayments(ctx context.Context, externalRef string) ([]*PaymentInfo, error)
+	// Refund devolve o valor informado. Chamadas repetidas com a mesma idempotencyKey
+	// devolvem o mesmo estorno em vez de criar outro.
+	Refund(ctx context.Context, paymentID string, amountInCents int64, idempotencyKey string) (*RefundInfo, error)
+}
+
+type CheckoutRequest struct {
+	Title         string
+	Description   string
+	AmountInCents int64
+	ExternalRef   string
+	NotifyURL     string
+	BackURL       string
+	ExpiresAt     *time.Time
+}
+
+type Checkout struct {
+	ID          string
+	CheckoutURL string
+}
+
+// PixRequest cria um pagamento Pix pela API de pagamentos. O gateway exige o e-mail do
+// pagador, e o QR code deixa de ser aceito em ExpiresAt.
+type PixRequest struct {
+	Description   string
+	AmountInCents int64
+	ExternalRef   string
+	NotifyURL     string
+	PayerEmail    string
+	PayerName     string
+	ExpiresAt     time.Time
+}
+
+type PixPayment struct {
+	ID           string
+	Status       string
+	QRCode       string
+	QRCodeBase64 string
+	TicketURL    string
+	ExpiresAt    time.Time
+}
+
+type PaymentInfo struct {
+	ID              string
+	Status          string
+	StatusDetail    string
+	PaymentMethod   string
+	ExternalRef     string
+	AmountInCents   int64
+	RefundedInCents int64
+	Refunds         []RefundInfo
+}
+
+type RefundInfo struct {
+	ID            string
+	PaymentID     string
+	Status        string
+	AmountInCents int64
+}
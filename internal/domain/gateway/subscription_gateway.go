@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"context"
+	"time"
+)
+
+// SubscriptionGateway cria e acompanha cobranças recorrentes mensais (preapproval no
+// Mercado Pago).
+type SubscriptionGateway interface {
+	CreateSubscription(ctx context.Context, req *SubscriptionRequest) (*SubscriptionInfo, error)
+	GetSubscription(ctx context.Context, subscriptionID string) (*SubscriptionInfo, error)
+	CancelSubscription(ctx context.Context, subscriptionID string) error
+	GetAuthorizedPayment(ctx context.Context, authorizedPaymentID string) (*AuthorizedPaymentInfo, error)
+}
+
+type SubscriptionRequest struct {
+	Reason        string
+	PayerEmail    string
+	AmountInCents int64
+	ExternalRef   string
+	BackURL       string
+}
+
+type SubscriptionInfo struct {
+	ID          string
+	Status      string
+	ExternalRef string
+	CheckoutURL string
+}
+
+// AuthorizedPaymentInfo é uma cobrança mensal da assinatura e o pagamento gerado por ela.
+type AuthorizedPaymentInfo struct {
+	ID             string
+	SubscriptionID string
+	ExternalRef    string
+	PaymentID      string
+	PaymentStatus  string
+	PaymentMethod  string
+	AmountInCents  int64
+	DebitDate      time.Time
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,9 +13,55 @@ type ClassRepository interface {
 	Create(ctx context.Context, class *entity.Class) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Class, error)
 	FindAll(ctx context.Context) ([]*entity.Class, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*entity.Class, error)
+	List(ctx context.Context, filter ClassFilter) ([]*entity.Class, error)
 	Update(ctx context.Context, class *entity.Class) error
+	UpdateDetails(ctx context.Context, class *entity.Class) error
+	// Touch grava apenas updated_at. Operações que decidem pela ocupação da aula sem alterá-la
+	// chamam Touch na transação para entrar em conflito com quem a altera ao mesmo tempo.
+	Touch(ctx context.Context, id primitive.ObjectID) error
+	FindBySeries(ctx context.Context, seriesID primitive.ObjectID, from time.Time) ([]*entity.Class, error)
+	FindBySeriesOccurrence(ctx context.Context, seriesID primitive.ObjectID, occurrenceStart time.Time) (*entity.Class, error)
+	StartDue(ctx context.Context, now time.Time) (int64, error)
+	CompleteDue(ctx context.Context, now time.Time) (int64, error)
 	IncrementEnrollmentWithVersion(ctx context.Context, classID primitive.ObjectID, currentVersion int) error
 	DecrementEnrollment(ctx context.Context, classID primitive.ObjectID) error
 	WithTransaction(ctx context.Context, fn func(context.Context, mongo.SessionContext) error) error
 }
 
+const (
+	ClassSortStartTime = "start_time"
+	ClassSortPrice     = "price_in_cents"
+	ClassSortCreatedAt = "created_at"
+)
+
+// ClassFilter descreve a consulta paginada de aulas. A paginação é por cursor: After
+// contém os valores de ordenação da última aula da página anterior.
+type ClassFilter struct {
+	From         *time.Time
+	To           *time.Time
+	InstructorID *primitive.ObjectID
+	Statuses     []string
+	HasSpots     bool
+	Search       string
+	SortBy       string
+	Descending   bool
+	Limit        int64
+	After        *ClassCursor
+}
+
+type ClassCursor struct {
+	ID           primitive.ObjectID `json:"id"`
+	StartTime    time.Time          `json:"start_time"`
+	PriceInCents int64              `json:"price_in_cents"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+func NewClassCursor(class *entity.Class) *ClassCursor {
+	return &ClassCursor{
+		ID:           class.ID,
+		StartTime:    class.StartTime,
+		PriceInCents: class.PriceInCents,
+		CreatedAt:    class.CreatedAt,
+	}
+}
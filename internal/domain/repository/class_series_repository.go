@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ClassSeriesRepository interface {
+	Create(ctx context.Context, series *entity.ClassSeries) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.ClassSeries, error)
+	FindActive(ctx context.Context) ([]*entity.ClassSeries, error)
+	UpdateMaterialization(ctx context.Context, series *entity.ClassSeries) error
+	AddException(ctx context.Context, id primitive.ObjectID, occurrence time.Time) error
+	UpdateRecurrenceEnd(ctx context.Context, series *entity.ClassSeries) error
+}
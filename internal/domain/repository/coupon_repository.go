@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CouponRepository guarda os cupons e seus resgates. Redeem e ReleaseByEnrollment devem
+// rodar dentro de uma transação.
+type CouponRepository interface {
+	Create(ctx context.Context, coupon *entity.Coupon) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Coupon, error)
+	FindByCode(ctx context.Context, code string) (*entity.Coupon, error)
+	FindAll(ctx context.Context) ([]*entity.Coupon, error)
+	Update(ctx context.Context, coupon *entity.Coupon) error
+	// Redeem conta o uso do cupom e registra o resgate. Retorna ErrCouponExhausted quando
+	// o limite global foi atingido e ErrCouponUserLimit quando o aluno já usou o cupom
+	// maxPerUser vezes.
+	Redeem(ctx context.Context, redemption *entity.CouponRedemption, maxPerUser int) error
+	// ReleaseByEnrollment libera o resgate feito na inscrição, se houver, devolvendo o uso
+	// ao cupom.
+	ReleaseByEnrollment(ctx context.Context, enrollmentID primitive.ObjectID) (bool, error)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreditPackRepository interface {
+	Create(ctx context.Context, pack *entity.CreditPack) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.CreditPack, error)
+	FindAll(ctx context.Context, activeOnly bool) ([]*entity.CreditPack, error)
+	Update(ctx context.Context, pack *entity.CreditPack) error
+}
+
+type CreditPurchaseRepository interface {
+	Create(ctx context.Context, purchase *entity.CreditPurchase) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.CreditPurchase, error)
+	Update(ctx context.Context, purchase *entity.CreditPurchase) error
+}
+
+// CreditRepository guarda os lotes de créditos e o extrato de cada aluno. As operações que
+// alteram o saldo de um lote são atômicas.
+type CreditRepository interface {
+	CreateLot(ctx context.Context, lot *entity.CreditLot) error
+	FindLotByPurchaseID(ctx context.Context, purchaseID primitive.ObjectID) (*entity.CreditLot, error)
+	FindLotsByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.CreditLot, error)
+	// Consume debita um crédito do lote válido que vence primeiro. Retorna ErrNoCredits
+	// quando o aluno não tem saldo.
+	Consume(ctx context.Context, userID primitive.ObjectID, now time.Time) (*entity.CreditLot, error)
+	// Restore devolve um crédito ao lote se ele ainda estiver válido.
+	Restore(ctx context.Context, lotID primitive.ObjectID, now time.Time) (bool, error)
+	// Revoke zera o lote e retorna quantos créditos ainda restavam.
+	Revoke(ctx context.Context, lotID primitive.ObjectID) (int, error)
+	// ExpireNext marca como expirado um lote vencido e o retorna com o saldo que restava,
+	// ou nil quando não há lotes a expirar.
+	ExpireNext(ctx context.Context, now time.Time) (*entity.CreditLot, error)
+	AddEntry(ctx context.Context, entry *entity.CreditEntry) error
+	FindEntriesByUser(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*entity.CreditEntry, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,6 +13,15 @@ type EnrollmentRepository interface {
 	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Enrollment, error)
 	FindByUserAndClass(ctx context.Context, userID, classID primitive.ObjectID) (*entity.Enrollment, error)
 	FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.Enrollment, error)
+	// HasConfirmedByUser indica se o aluno já teve alguma inscrição confirmada, mesmo que
+	// cancelada depois.
+	HasConfirmedByUser(ctx context.Context, userID primitive.ObjectID) (bool, error)
+	FindActiveByClass(ctx context.Context, classID primitive.ObjectID) ([]*entity.Enrollment, error)
 	Update(ctx context.Context, enrollment *entity.Enrollment) error
+	FindExpiredPending(ctx context.Context, now time.Time, limit int64) ([]*entity.Enrollment, error)
+	ExpireIfPending(ctx context.Context, id primitive.ObjectID, now time.Time) (bool, error)
+	// ExtendHoldIfPending prorroga a reserva até until se a inscrição ainda estiver
+	// pendente e dentro do prazo.
+	ExtendHoldIfPending(ctx context.Context, id primitive.ObjectID, until, now time.Time) (bool, error)
 }
 
@@ -0,0 +1,39 @@
+package repository
+
+import "errors"
+
+var (
+	ErrClassNotFound   = errors.New("aula não encontrada")
+	ErrClassFull       = errors.New("aula sem vagas disponíveis")
+	ErrVersionConflict = errors.New("versão da aula desatualizada")
+	ErrCapacityBelow   = errors.New("capacidade menor que o número de inscritos")
+
+	ErrUserNotFound = errors.New("usuário não encontrado")
+
+	ErrClassSeriesNotFound = errors.New("série de aulas não encontrada")
+	ErrOccurrenceExists    = errors.New("ocorrência da série já gerada")
+	ErrEnrollmentNotFound  = errors.New("inscrição não encontrada")
+	ErrPaymentNotFound     = errors.New("pagamento não encontrado")
+	ErrWaitlistEntryExists = errors.New("aluno já está na lista de espera")
+
+	ErrCreditPackNotFound     = errors.New("pacote de créditos não encontrado")
+	ErrCreditPurchaseNotFound = errors.New("compra de créditos não encontrada")
+	ErrNoCredits              = errors.New("sem créditos disponíveis")
+
+	ErrMembershipPlanNotFound = errors.New("plano de assinatura não encontrado")
+	ErrMembershipNotFound     = errors.New("assinatura não encontrada")
+
+	ErrCouponNotFound  = errors.New("cupom não encontrado")
+	ErrCouponCodeTaken = errors.New("já existe um cupom com este código")
+	ErrCouponExhausted = errors.New("cupom esgotado")
+	ErrCouponUserLimit = errors.New("limite de uso do cupom por aluno atingido")
+
+	ErrReceiptNotFound = errors.New("recibo não encontrado")
+	ErrReceiptExists   = errors.New("recibo já emitido para este pagamento")
+
+	ErrWebhookEventNotFound = errors.New("evento de webhook não encontrado")
+	ErrWebhookEventExists   = errors.New("evento de webhook já registrado")
+
+	ErrSessionNotFound      = errors.New("sessão não encontrada")
+	ErrRefreshTokenNotFound = errors.New("refresh token não encontrado")
+)
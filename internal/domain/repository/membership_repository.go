@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MembershipPlanRepository interface {
+	Create(ctx context.Context, plan *entity.MembershipPlan) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.MembershipPlan, error)
+	FindAll(ctx context.Context, activeOnly bool) ([]*entity.MembershipPlan, error)
+	Update(ctx context.Context, plan *entity.MembershipPlan) error
+}
+
+// MembershipRepository guarda as assinaturas dos alunos. O uso de aulas do período é
+// alterado de forma atômica.
+type MembershipRepository interface {
+	Create(ctx context.Context, membership *entity.Membership) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Membership, error)
+	FindByPreapprovalID(ctx context.Context, preapprovalID string) (*entity.Membership, error)
+	// FindLatestByUser retorna a assinatura mais recente do aluno, ou nil se não houver.
+	FindLatestByUser(ctx context.Context, userID primitive.ObjectID) (*entity.Membership, error)
+	// FindActiveByUser retorna a assinatura com período pago vigente em now, ou nil.
+	FindActiveByUser(ctx context.Context, userID primitive.ObjectID, now time.Time) (*entity.Membership, error)
+	Update(ctx context.Context, membership *entity.Membership) error
+	// ConsumeClass usa uma aula do período vigente, desde que a aula comece antes do fim do
+	// período e ainda haja aulas disponíveis. Retorna false quando não foi possível.
+	ConsumeClass(ctx context.Context, id primitive.ObjectID, now, classStart time.Time) (bool, error)
+	// ReleaseClass devolve uma aula ao período, se a inscrição foi feita no período vigente.
+	ReleaseClass(ctx context.Context, id primitive.ObjectID, enrolledAt time.Time) (bool, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, message *entity.OutboxMessage) error
+	ClaimNext(ctx context.Context, messageType string, now time.Time, lease time.Duration) (*entity.OutboxMessage, error)
+	Update(ctx context.Context, message *entity.OutboxMessage) error
+}
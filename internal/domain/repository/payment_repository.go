@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,6 +12,9 @@ type PaymentRepository interface {
 	Create(ctx context.Context, payment *entity.Payment) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Payment, error)
 	FindByEnrollmentID(ctx context.Context, enrollmentID primitive.ObjectID) (*entity.Payment, error)
+	FindByEnrollmentIDs(ctx context.Context, enrollmentIDs []primitive.ObjectID) ([]*entity.Payment, error)
+	FindByCreditPurchaseID(ctx context.Context, purchaseID primitive.ObjectID) (*entity.Payment, error)
 	FindByMercadoPagoID(ctx context.Context, mpID string) (*entity.Payment, error)
+	FindForReconciliation(ctx context.Context, since time.Time, limit int64) ([]*entity.Payment, error)
 	Update(ctx context.Context, payment *entity.Payment) error
 }
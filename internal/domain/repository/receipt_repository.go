@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReceiptRepository guarda os recibos emitidos. NextNumber reserva o próximo número da
+// sequência; chamado na mesma transação que Create, um número só é consumido se o recibo
+// for gravado, e emissões concorrentes são serializadas pelo conflito de escrita.
+type ReceiptRepository interface {
+	NextNumber(ctx context.Context) (int64, error)
+	Create(ctx context.Context, receipt *entity.Receipt) error
+	FindByPaymentID(ctx context.Context, paymentID primitive.ObjectID) (*entity.Receipt, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+)
+
+type ReconciliationRepository interface {
+	Create(ctx context.Context, report *entity.ReconciliationReport) error
+	FindRecent(ctx context.Context, limit int64) ([]*entity.ReconciliationReport, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RefundRepository interface {
+	Create(ctx context.Context, refund *entity.Refund) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Refund, error)
+	FindByPaymentID(ctx context.Context, paymentID primitive.ObjectID) ([]*entity.Refund, error)
+	FindByMercadoPagoID(ctx context.Context, mpID string) (*entity.Refund, error)
+	Update(ctx context.Context, refund *entity.Refund) error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *entity.Session) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Session, error)
+	FindActiveByUser(ctx context.Context, userID primitive.ObjectID, now time.Time) ([]*entity.Session, error)
+	Update(ctx context.Context, session *entity.Session) error
+	Revoke(ctx context.Context, id primitive.ObjectID, reason string, at time.Time) error
+	RevokeAllByUser(ctx context.Context, userID primitive.ObjectID, reason string, at time.Time) (int64, error)
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entity.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	// MarkRotated marca o token como usado e devolve false se ele já tinha sido usado.
+	MarkRotated(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error)
+}
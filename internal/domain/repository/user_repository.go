@@ -14,4 +14,6 @@ type UserRepository interface {
 	FindAll(ctx context.Context) ([]*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	FindTokenVersion(ctx context.Context, id primitive.ObjectID) (int, error)
+	IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error
 }
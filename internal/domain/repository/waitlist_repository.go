@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry *entity.WaitlistEntry) error
+	FindActiveByUserAndClass(ctx context.Context, userID, classID primitive.ObjectID) (*entity.WaitlistEntry, error)
+	FindByEnrollmentID(ctx context.Context, enrollmentID primitive.ObjectID) (*entity.WaitlistEntry, error)
+	FindNextWaiting(ctx context.Context, classID primitive.ObjectID) (*entity.WaitlistEntry, error)
+	CountWaitingBefore(ctx context.Context, entry *entity.WaitlistEntry) (int64, error)
+	CountWaiting(ctx context.Context, classID primitive.ObjectID) (int64, error)
+	Update(ctx context.Context, entry *entity.WaitlistEntry) error
+	CancelByClass(ctx context.Context, classID primitive.ObjectID) (int64, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookEventRepository interface {
+	Create(ctx context.Context, event *entity.WebhookEvent) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.WebhookEvent, error)
+	FindByDedupKey(ctx context.Context, dedupKey string) (*entity.WebhookEvent, error)
+	FindRecent(ctx context.Context, status string, limit int64) ([]*entity.WebhookEvent, error)
+	ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*entity.WebhookEvent, error)
+	Claim(ctx context.Context, id primitive.ObjectID, now time.Time, lease time.Duration) (*entity.WebhookEvent, error)
+	Update(ctx context.Context, event *entity.WebhookEvent) error
+}
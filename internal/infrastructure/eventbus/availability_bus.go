@@ -0,0 +1,80 @@
+package eventbus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/event"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const subscriberBuffer = 8
+
+// AvailabilityBus é um broker em memória. Cada evento é um retrato completo da ocupação,
+// então um assinante lento perde eventos antigos, mas sempre recebe o mais recente.
+type AvailabilityBus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	subscribers map[primitive.ObjectID]map[chan event.ClassAvailability]struct{}
+}
+
+func NewAvailabilityBus() *AvailabilityBus {
+	return &AvailabilityBus{
+		// Inicia a sequência pelo relógio para que os IDs continuem crescentes após
+		// um reinício e o Last-Event-ID dos clientes não pareça mais novo que o servidor.
+		lastID:      uint64(time.Now().UnixMicro()),
+		subscribers: make(map[primitive.ObjectID]map[chan event.ClassAvailability]struct{}),
+	}
+}
+
+func (b *AvailabilityBus) Publish(availability event.ClassAvailability) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	availability.ID = b.lastID
+
+	for ch := range b.subscribers[availability.ClassID] {
+		select {
+		case ch <- availability:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- availability
+		}
+	}
+}
+
+func (b *AvailabilityBus) Subscribe(classID primitive.ObjectID) (<-chan event.ClassAvailability, func()) {
+	ch := make(chan event.ClassAvailability, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[classID] == nil {
+		b.subscribers[classID] = make(map[chan event.ClassAvailability]struct{})
+	}
+	b.subscribers[classID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[classID], ch)
+			if len(b.subscribers[classID]) == 0 {
+				delete(b.subscribers, classID)
+			}
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+func (b *AvailabilityBus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastID
+}
@@ -0,0 +1,125 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/event"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+type pendingChangesKey struct{}
+
+// pendingChanges acumula as aulas alteradas dentro de uma transação para que os eventos
+// só sejam publicados depois do commit.
+type pendingChanges struct {
+	mu  sync.Mutex
+	ids []primitive.ObjectID
+}
+
+func (p *pendingChanges) add(id primitive.ObjectID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, existing := range p.ids {
+		if existing == id {
+			return
+		}
+	}
+	p.ids = append(p.ids, id)
+}
+
+func (p *pendingChanges) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ids = nil
+}
+
+// AvailabilityClassRepository decora o ClassRepository publicando a ocupação da aula
+// sempre que inscrições são incrementadas, decrementadas ou a aula é alterada.
+type AvailabilityClassRepository struct {
+	repository.ClassRepository
+	broker event.AvailabilityBroker
+}
+
+func NewAvailabilityClassRepository(classRepo repository.ClassRepository, broker event.AvailabilityBroker) *AvailabilityClassRepository {
+	return &AvailabilityClassRepository{
+		ClassRepository: classRepo,
+		broker:          broker,
+	}
+}
+
+func (r *AvailabilityClassRepository) Update(ctx context.Context, class *entity.Class) error {
+	if err := r.ClassRepository.Update(ctx, class); err != nil {
+		return err
+	}
+	r.changed(ctx, class.ID)
+	return nil
+}
+
+func (r *AvailabilityClassRepository) UpdateDetails(ctx context.Context, class *entity.Class) error {
+	if err := r.ClassRepository.UpdateDetails(ctx, class); err != nil {
+		return err
+	}
+	r.changed(ctx, class.ID)
+	return nil
+}
+
+func (r *AvailabilityClassRepository) IncrementEnrollmentWithVersion(ctx context.Context, classID primitive.ObjectID, currentVersion int) error {
+	if err := r.ClassRepository.IncrementEnrollmentWithVersion(ctx, classID, currentVersion); err != nil {
+		return err
+	}
+	r.changed(ctx, classID)
+	return nil
+}
+
+func (r *AvailabilityClassRepository) DecrementEnrollment(ctx context.Context, classID primitive.ObjectID) error {
+	if err := r.ClassRepository.DecrementEnrollment(ctx, classID); err != nil {
+		return err
+	}
+	r.changed(ctx, classID)
+	return nil
+}
+
+func (r *AvailabilityClassRepository) WithTransaction(ctx context.Context, fn func(context.Context, mongo.SessionContext) error) error {
+	changes := &pendingChanges{}
+	ctx = context.WithValue(ctx, pendingChangesKey{}, changes)
+
+	err := r.ClassRepository.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		changes.reset()
+		return fn(ctx, sc)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range changes.ids {
+		r.publish(ctx, id)
+	}
+	return nil
+}
+
+func (r *AvailabilityClassRepository) changed(ctx context.Context, classID primitive.ObjectID) {
+	if changes, ok := ctx.Value(pendingChangesKey{}).(*pendingChanges); ok {
+		changes.add(classID)
+		return
+	}
+	r.publish(ctx, classID)
+}
+
+func (r *AvailabilityClassRepository) publish(ctx context.Context, classID primitive.ObjectID) {
+	class, err := r.ClassRepository.FindByID(context.WithoutCancel(ctx), classID)
+	if err != nil {
+		logger.Warn("Erro ao publicar ocupação da aula",
+			zap.String("class_id", classID.Hex()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	r.broker.Publish(event.NewClassAvailability(class))
+}
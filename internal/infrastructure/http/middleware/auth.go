@@ -2,11 +2,15 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"github.com/marcelobritu/isayoga-api/pkg/auth"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
 
@@ -14,7 +18,23 @@ type contextKey string
 
 const UserClaimsKey contextKey = "user_claims"
 
-func AuthMiddleware(next http.Handler) http.Handler {
+// Authenticator valida o access token, confere a versão de tokens do usuário e a sessão
+// que emitiu o token. Trocar a senha, mudar o papel ou remover o usuário incrementa essa
+// versão (ou apaga o usuário), e logout ou revogação encerram a sessão; em todos os casos
+// os tokens emitidos antes deixam de valer imediatamente, sem esperar a expiração.
+type Authenticator struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+}
+
+func NewAuthenticator(userRepo repository.UserRepository, sessionRepo repository.SessionRepository) *Authenticator {
+	return &Authenticator{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (a *Authenticator) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
@@ -36,11 +56,98 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		if err := a.checkRevocation(r.Context(), claims); err != nil {
+			if errors.Is(err, errTokenRevoked) {
+				logger.Warn("Token revogado", zap.String("user_id", claims.UserID))
+				http.Error(w, "Token inválido ou expirado", http.StatusUnauthorized)
+				return
+			}
+			logger.Error("Erro ao verificar token", zap.Error(err))
+			http.Error(w, "Erro ao verificar autenticação", http.StatusServiceUnavailable)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// OptionalAuth identifica o usuário quando há um token válido, sem bloquear
+// requisições anônimas. Usado em rotas públicas que mudam conforme o usuário.
+func (a *Authenticator) OptionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(r.Header.Get("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := auth.ValidateToken(parts[1])
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := a.checkRevocation(r.Context(), claims); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+var errTokenRevoked = errors.New("token revogado")
+
+func (a *Authenticator) checkRevocation(ctx context.Context, claims *auth.Claims) error {
+	if err := a.checkTokenVersion(ctx, claims); err != nil {
+		return err
+	}
+	return a.checkSession(ctx, claims)
+}
+
+func (a *Authenticator) checkTokenVersion(ctx context.Context, claims *auth.Claims) error {
+	userID, err := primitive.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		return errTokenRevoked
+	}
+
+	version, err := a.userRepo.FindTokenVersion(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return errTokenRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	if version != claims.TokenVersion {
+		return errTokenRevoked
+	}
+	return nil
+}
+
+// checkSession recusa tokens cuja sessão foi revogada, expirou ou já foi removida.
+func (a *Authenticator) checkSession(ctx context.Context, claims *auth.Claims) error {
+	sessionID, err := primitive.ObjectIDFromHex(claims.SessionID)
+	if err != nil {
+		return errTokenRevoked
+	}
+
+	session, err := a.sessionRepo.FindByID(ctx, sessionID)
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return errTokenRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	if !session.IsActive(time.Now()) || session.UserID.Hex() != claims.UserID {
+		return errTokenRevoked
+	}
+	return nil
+}
+
 func AdminOnly(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := r.Context().Value(UserClaimsKey).(*auth.Claims)
@@ -61,4 +168,3 @@ func AdminOnly(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
-
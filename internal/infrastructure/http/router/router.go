@@ -15,6 +15,15 @@ func Setup(
 	enrollmentHandler *handler.EnrollmentHandler,
 	webhookHandler *handler.WebhookHandler,
 	authHandler *handler.AuthHandler,
+	devPaymentHandler *handler.DevPaymentHandler,
+	waitlistHandler *handler.WaitlistHandler,
+	classSeriesHandler *handler.ClassSeriesHandler,
+	classAvailabilityHandler *handler.ClassAvailabilityHandler,
+	paymentHandler *handler.PaymentHandler,
+	creditHandler *handler.CreditHandler,
+	membershipHandler *handler.MembershipHandler,
+	couponHandler *handler.CouponHandler,
+	authenticator *customMiddleware.Authenticator,
 ) *chi.Mux {
 	r := chi.NewRouter()
 
@@ -26,8 +35,8 @@ func Setup(
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://*", "https://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-None-Match", "Last-Event-ID"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -36,37 +45,131 @@ func Setup(
 
 	r.Route("/webhooks", func(r chi.Router) {
 		r.Post("/mercadopago", webhookHandler.MercadoPago)
+		r.Group(func(r chi.Router) {
+			r.Use(authenticator.AuthMiddleware)
+			r.Use(customMiddleware.AdminOnly)
+			r.Get("/events", webhookHandler.ListEvents)
+			r.Post("/events/{id}/replay", webhookHandler.ReplayEvent)
+		})
 	})
 
+	if devPaymentHandler.Enabled() {
+		r.Route("/dev/payments/{ref}", func(r chi.Router) {
+			r.Get("/checkout", devPaymentHandler.Checkout)
+			r.Post("/approve", devPaymentHandler.Approve)
+			r.Post("/reject", devPaymentHandler.Reject)
+		})
+		r.Route("/dev/subscriptions/{ref}", func(r chi.Router) {
+			r.Get("/checkout", devPaymentHandler.SubscriptionCheckout)
+			r.Post("/charge", devPaymentHandler.ChargeSubscription)
+		})
+	}
+
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/auth", func(r chi.Router) {
 			r.Post("/login", authHandler.Login)
 			r.Post("/register", authHandler.Register)
+			r.Post("/refresh", authHandler.Refresh)
+			r.Post("/logout", authHandler.Logout)
 		})
 
 		r.Route("/classes", func(r chi.Router) {
-			r.Get("/", classHandler.List)
+			r.With(authenticator.OptionalAuth).Get("/", classHandler.List)
+			r.With(authenticator.OptionalAuth).Get("/{id}", classHandler.Get)
+			r.Get("/{id}/availability/stream", classAvailabilityHandler.Stream)
 			r.Group(func(r chi.Router) {
-				r.Use(customMiddleware.AuthMiddleware)
+				r.Use(authenticator.AuthMiddleware)
 				r.Use(customMiddleware.AdminOnly)
 				r.Post("/", classHandler.Create)
+				r.Put("/{id}", classHandler.Update)
+				r.Post("/{id}/publish", classHandler.Publish)
+				r.Post("/{id}/cancel", classHandler.Cancel)
 			})
+
+			r.Route("/{id}/waitlist", func(r chi.Router) {
+				r.Use(authenticator.AuthMiddleware)
+				r.Get("/", waitlistHandler.Position)
+				r.Post("/", waitlistHandler.Join)
+				r.Delete("/", waitlistHandler.Leave)
+			})
+		})
+
+		r.Route("/class-series", func(r chi.Router) {
+			r.Use(authenticator.AuthMiddleware)
+			r.Use(customMiddleware.AdminOnly)
+			r.Post("/", classSeriesHandler.Create)
+			r.Put("/{id}/occurrences", classSeriesHandler.UpdateOccurrence)
+			r.Post("/{id}/cancellations", classSeriesHandler.CancelOccurrence)
 		})
 
 		r.Route("/enrollments", func(r chi.Router) {
-			r.Use(customMiddleware.AuthMiddleware)
+			r.Use(authenticator.AuthMiddleware)
 			r.Post("/", enrollmentHandler.Enroll)
+			r.With(customMiddleware.AdminOnly).Post("/on-behalf", enrollmentHandler.EnrollOnBehalf)
+			r.Get("/{id}", enrollmentHandler.Get)
 			r.Delete("/{id}", enrollmentHandler.Cancel)
 		})
 
+		r.Route("/credit-packs", func(r chi.Router) {
+			r.With(authenticator.OptionalAuth).Get("/", creditHandler.ListPacks)
+			r.With(authenticator.AuthMiddleware).Post("/{id}/purchase", creditHandler.Purchase)
+			r.Group(func(r chi.Router) {
+				r.Use(authenticator.AuthMiddleware)
+				r.Use(customMiddleware.AdminOnly)
+				r.Post("/", creditHandler.CreatePack)
+				r.Put("/{id}", creditHandler.UpdatePack)
+			})
+		})
+
+		r.Route("/membership-plans", func(r chi.Router) {
+			r.With(authenticator.OptionalAuth).Get("/", membershipHandler.ListPlans)
+			r.With(authenticator.AuthMiddleware).Post("/{id}/subscribe", membershipHandler.Subscribe)
+			r.Group(func(r chi.Router) {
+				r.Use(authenticator.AuthMiddleware)
+				r.Use(customMiddleware.AdminOnly)
+				r.Post("/", membershipHandler.CreatePlan)
+				r.Put("/{id}", membershipHandler.UpdatePlan)
+			})
+		})
+
+		r.Route("/coupons", func(r chi.Router) {
+			r.Use(authenticator.AuthMiddleware)
+			r.Use(customMiddleware.AdminOnly)
+			r.Get("/", couponHandler.List)
+			r.Post("/", couponHandler.Create)
+			r.Put("/{id}", couponHandler.Update)
+		})
+
+		r.Route("/payments", func(r chi.Router) {
+			r.Use(authenticator.AuthMiddleware)
+			r.Get("/{id}/receipt", paymentHandler.Receipt)
+			r.Group(func(r chi.Router) {
+				r.Use(customMiddleware.AdminOnly)
+				r.Get("/reconciliations", paymentHandler.ListReconciliations)
+				r.Post("/reconciliations", paymentHandler.Reconcile)
+				r.Get("/{id}/refunds", paymentHandler.ListRefunds)
+				r.Post("/{id}/refunds", paymentHandler.Refund)
+			})
+		})
+
+		r.Route("/me", func(r chi.Router) {
+			r.Use(authenticator.AuthMiddleware)
+			r.Get("/enrollments", enrollmentHandler.ListMine)
+			r.Get("/credits", creditHandler.Mine)
+			r.Get("/membership", membershipHandler.Mine)
+			r.Delete("/membership", membershipHandler.Cancel)
+			r.Get("/sessions", authHandler.ListSessions)
+			r.Delete("/sessions/{id}", authHandler.RevokeSession)
+		})
+
 		r.Route("/users", func(r chi.Router) {
 			r.Group(func(r chi.Router) {
-				r.Use(customMiddleware.AuthMiddleware)
+				r.Use(authenticator.AuthMiddleware)
 				r.Post("/change-password", userHandler.ChangePassword)
 			})
 			
 			r.Group(func(r chi.Router) {
-				r.Use(customMiddleware.AuthMiddleware)
+				r.Use(authenticator.AuthMiddleware)
 				r.Use(customMiddleware.AdminOnly)
 				r.Get("/", userHandler.List)
 				r.Post("/", userHandler.Create)
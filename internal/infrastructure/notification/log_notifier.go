@@ -0,0 +1,27 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// LogNotifier registra as notificações no log. Serve enquanto não há um provedor
+// de e-mail configurado.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification *gateway.Notification) error {
+	logger.Info("Notificação enviada",
+		zap.String("user_id", notification.UserID.Hex()),
+		zap.String("email", notification.Email),
+		zap.String("subject", notification.Subject),
+		zap.String("message", notification.Message),
+	)
+	return nil
+}
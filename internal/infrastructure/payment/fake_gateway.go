@@ -0,0 +1,285 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+)
+
+// FakeGateway é um provedor de pagamento em memória para desenvolvimento local e testes.
+// Os pagamentos só mudam de status quando simulados via Simulate, e as assinaturas só são
+// cobradas via SimulateSubscriptionCharge.
+type FakeGateway struct {
+	baseURL       string
+	mu            sync.Mutex
+	payments      map[string]*gateway.PaymentInfo
+	subscriptions map[string]*fakeSubscription
+	charges       map[string]*gateway.AuthorizedPaymentInfo
+	refunds       map[string]gateway.RefundInfo
+}
+
+type fakeSubscription struct {
+	info          gateway.SubscriptionInfo
+	amountInCents int64
+	charges       int
+}
+
+func NewFakeGateway(baseURL string) *FakeGateway {
+	return &FakeGateway{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		payments:      make(map[string]*gateway.PaymentInfo),
+		subscriptions: make(map[string]*fakeSubscription),
+		charges:       make(map[string]*gateway.AuthorizedPaymentInfo),
+		refunds:       make(map[string]gateway.RefundInfo),
+	}
+}
+
+func (g *FakeGateway) CreateCheckout(ctx context.Context, req *gateway.CheckoutRequest) (*gateway.Checkout, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	paymentID := fakePaymentID(req.ExternalRef)
+	g.payments[paymentID] = &gateway.PaymentInfo{
+		ID:            paymentID,
+		Status:        entity.PaymentStatusPending,
+		PaymentMethod: "fake",
+		ExternalRef:   req.ExternalRef,
+		AmountInCents: req.AmountInCents,
+	}
+
+	return &gateway.Checkout{
+		ID:          "fake-pref-" + req.ExternalRef,
+		CheckoutURL: fmt.Sprintf("%s/dev/payments/%s/checkout", g.baseURL, req.ExternalRef),
+	}, nil
+}
+
+// fakePixQRCode é um PNG 1x1 usado como imagem do QR code nos pagamentos Pix simulados.
+const fakePixQRCode = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="
+
+func (g *FakeGateway) CreatePixPayment(ctx context.Context, req *gateway.PixRequest) (*gateway.PixPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	paymentID := fakePaymentID(req.ExternalRef)
+	if _, ok := g.payments[paymentID]; !ok {
+		g.payments[paymentID] = &gateway.PaymentInfo{
+			ID:            paymentID,
+			Status:        entity.PaymentStatusPending,
+			PaymentMethod: entity.PaymentChannelPix,
+			ExternalRef:   req.ExternalRef,
+			AmountInCents: req.AmountInCents,
+		}
+	}
+
+	return &gateway.PixPayment{
+		ID:           paymentID,
+		Status:       entity.PaymentStatusPending,
+		QRCode:       "00020126fakepix" + req.ExternalRef,
+		QRCodeBase64: fakePixQRCode,
+		TicketURL:    fmt.Sprintf("%s/dev/payments/%s/checkout", g.baseURL, req.ExternalRef),
+		ExpiresAt:    req.ExpiresAt,
+	}, nil
+}
+
+func (g *FakeGateway) GetPayment(ctx context.Context, paymentID string) (*gateway.PaymentInfo, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, ok := g.payments[paymentID]
+	if !ok {
+		return nil, fmt.Errorf("pagamento %s não encontrado no gateway fake", paymentID)
+	}
+
+	info := *payment
+	info.Refunds = append([]gateway.RefundInfo(nil), payment.Refunds...)
+	return &info, nil
+}
+
+func (g *FakeGateway) SearchPayments(ctx context.Context, externalRef string) ([]*gateway.PaymentInfo, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payments := []*gateway.PaymentInfo{}
+	for _, payment := range g.payments {
+		if payment.ExternalRef != externalRef {
+			continue
+		}
+		info := *payment
+		info.Refunds = append([]gateway.RefundInfo(nil), payment.Refunds...)
+		payments = append(payments, &info)
+	}
+	return payments, nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, paymentID string, amountInCents int64, idempotencyKey string) (*gateway.RefundInfo, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if refund, ok := g.refunds[idempotencyKey]; ok {
+		return &refund, nil
+	}
+
+	payment, ok := g.payments[paymentID]
+	if !ok {
+		return nil, fmt.Errorf("pagamento %s não encontrado no gateway fake", paymentID)
+	}
+
+	remaining := payment.AmountInCents - payment.RefundedInCents
+	if remaining <= 0 {
+		return nil, fmt.Errorf("pagamento %s já foi estornado integralmente", paymentID)
+	}
+	if amountInCents > remaining {
+		return nil, fmt.Errorf("valor do estorno maior que o saldo do pagamento %s", paymentID)
+	}
+	if amountInCents <= 0 {
+		amountInCents = remaining
+	}
+
+	payment.RefundedInCents += amountInCents
+	if payment.RefundedInCents == payment.AmountInCents {
+		payment.Status = entity.PaymentStatusRefunded
+	}
+
+	refund := gateway.RefundInfo{
+		ID:            fmt.Sprintf("%s-refund-%d", paymentID, len(payment.Refunds)+1),
+		PaymentID:     paymentID,
+		Status:        entity.RefundStatusApproved,
+		AmountInCents: amountInCents,
+	}
+	payment.Refunds = append(payment.Refunds, refund)
+	if idempotencyKey != "" {
+		g.refunds[idempotencyKey] = refund
+	}
+
+	return &refund, nil
+}
+
+// Simulate altera o status do pagamento associado à referência externa e devolve o id
+// que deve ser usado para notificar o processamento, como faria o webhook real.
+func (g *FakeGateway) Simulate(externalRef, status string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	paymentID := fakePaymentID(externalRef)
+	payment, ok := g.payments[paymentID]
+	if !ok {
+		return "", fmt.Errorf("checkout %s não encontrado no gateway fake", externalRef)
+	}
+
+	payment.Status = status
+	return paymentID, nil
+}
+
+func (g *FakeGateway) CreateSubscription(ctx context.Context, req *gateway.SubscriptionRequest) (*gateway.SubscriptionInfo, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subscription := &fakeSubscription{
+		info: gateway.SubscriptionInfo{
+			ID:          fakeSubscriptionID(req.ExternalRef),
+			Status:      entity.MembershipStatusPending,
+			ExternalRef: req.ExternalRef,
+			CheckoutURL: fmt.Sprintf("%s/dev/subscriptions/%s/checkout", g.baseURL, req.ExternalRef),
+		},
+		amountInCents: req.AmountInCents,
+	}
+	g.subscriptions[subscription.info.ID] = subscription
+
+	info := subscription.info
+	return &info, nil
+}
+
+func (g *FakeGateway) GetSubscription(ctx context.Context, subscriptionID string) (*gateway.SubscriptionInfo, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subscription, ok := g.subscriptions[subscriptionID]
+	if !ok {
+		return nil, fmt.Errorf("assinatura %s não encontrada no gateway fake", subscriptionID)
+	}
+
+	info := subscription.info
+	return &info, nil
+}
+
+func (g *FakeGateway) CancelSubscription(ctx context.Context, subscriptionID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subscription, ok := g.subscriptions[subscriptionID]
+	if !ok {
+		return fmt.Errorf("assinatura %s não encontrada no gateway fake", subscriptionID)
+	}
+
+	subscription.info.Status = entity.MembershipStatusCancelled
+	return nil
+}
+
+func (g *FakeGateway) GetAuthorizedPayment(ctx context.Context, authorizedPaymentID string) (*gateway.AuthorizedPaymentInfo, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[authorizedPaymentID]
+	if !ok {
+		return nil, fmt.Errorf("cobrança %s não encontrada no gateway fake", authorizedPaymentID)
+	}
+
+	info := *charge
+	return &info, nil
+}
+
+// SimulateSubscriptionCharge autoriza a assinatura associada à referência externa e gera
+// uma cobrança mensal aprovada. Devolve os ids da assinatura e da cobrança, usados para
+// notificar o processamento como fariam os webhooks reais.
+func (g *FakeGateway) SimulateSubscriptionCharge(externalRef string) (string, string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subscription, ok := g.subscriptions[fakeSubscriptionID(externalRef)]
+	if !ok {
+		return "", "", fmt.Errorf("assinatura %s não encontrada no gateway fake", externalRef)
+	}
+	if subscription.info.Status == entity.MembershipStatusCancelled {
+		return "", "", fmt.Errorf("assinatura %s cancelada no gateway fake", externalRef)
+	}
+
+	subscription.info.Status = entity.MembershipStatusAuthorized
+	subscription.charges++
+
+	chargeRef := fmt.Sprintf("%s-%d", externalRef, subscription.charges)
+	paymentID := fakePaymentID(chargeRef)
+	g.payments[paymentID] = &gateway.PaymentInfo{
+		ID:            paymentID,
+		Status:        entity.PaymentStatusApproved,
+		PaymentMethod: "fake",
+		ExternalRef:   externalRef,
+		AmountInCents: subscription.amountInCents,
+	}
+
+	charge := &gateway.AuthorizedPaymentInfo{
+		ID:             "fake-charge-" + chargeRef,
+		SubscriptionID: subscription.info.ID,
+		ExternalRef:    externalRef,
+		PaymentID:      paymentID,
+		PaymentStatus:  entity.PaymentStatusApproved,
+		PaymentMethod:  "fake",
+		AmountInCents:  subscription.amountInCents,
+		DebitDate:      time.Now(),
+	}
+	g.charges[charge.ID] = charge
+
+	return subscription.info.ID, charge.ID, nil
+}
+
+func fakeSubscriptionID(externalRef string) string {
+	return "fake-sub-" + externalRef
+}
+
+func fakePaymentID(externalRef string) string {
+	return "fake-" + externalRef
+}
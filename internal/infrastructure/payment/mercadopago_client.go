@@ -2,46 +2,51 @@ package payment
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
 	"github.com/mercadopago/sdk-go/pkg/config"
+	"github.com/mercadopago/sdk-go/pkg/invoice"
+	mpPayment "github.com/mercadopago/sdk-go/pkg/payment"
+	"github.com/mercadopago/sdk-go/pkg/preapproval"
 	"github.com/mercadopago/sdk-go/pkg/preference"
+	"github.com/mercadopago/sdk-go/pkg/refund"
+	"github.com/mercadopago/sdk-go/pkg/requester"
 )
 
 type MercadoPagoClient struct {
-	client preference.Client
+	client        preference.Client
+	paymentClient mpPayment.Client
+	refundClient  refund.Client
+	preapprovals  preapproval.Client
+	invoices      invoice.Client
 }
 
 func NewMercadoPagoClient(accessToken string) *MercadoPagoClient {
 	cfg, _ := config.New(accessToken)
+	cfg.Requester = &idempotencyRequester{next: cfg.Requester}
 	
 	return &MercadoPagoClient{
-		client: preference.NewClient(cfg),
+		client:        preference.NewClient(cfg),
+		paymentClient: mpPayment.NewClient(cfg),
+		refundClient:  refund.NewClient(cfg),
+		preapprovals:  preapproval.NewClient(cfg),
+		invoices:      invoice.NewClient(cfg),
 	}
 }
 
-type PreferenceRequest struct {
-	Title        string
-	Description  string
-	Quantity     int
-	UnitPrice    float64
-	ExternalRef  string
-	NotifyURL    string
-	BackURL      string
-}
-
-type PreferenceResponse struct {
-	ID           string
-	InitPointURL string
-}
-
-func (c *MercadoPagoClient) CreatePreference(ctx context.Context, req *PreferenceRequest) (*PreferenceResponse, error) {
+func (c *MercadoPagoClient) CreateCheckout(ctx context.Context, req *gateway.CheckoutRequest) (*gateway.Checkout, error) {
 	request := preference.Request{
 		Items: []preference.ItemRequest{
 			{
 				Title:       req.Title,
 				Description: req.Description,
-				Quantity:    req.Quantity,
-				UnitPrice:   req.UnitPrice,
+				Quantity:    1,
+				UnitPrice:   centsToAmount(req.AmountInCents),
 			},
 		},
 		ExternalReference: req.ExternalRef,
@@ -54,14 +59,259 @@ func (c *MercadoPagoClient) CreatePreference(ctx context.Context, req *Preferenc
 		AutoReturn: "approved",
 	}
 
+	if req.ExpiresAt != nil {
+		now := time.Now()
+		request.Expires = true
+		request.ExpirationDateFrom = &now
+		request.ExpirationDateTo = req.ExpiresAt
+		request.DateOfExpiration = req.ExpiresAt
+	}
+
 	result, err := c.client.Create(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
-	return &PreferenceResponse{
-		ID:           result.ID,
-		InitPointURL: result.InitPoint,
+	return &gateway.Checkout{
+		ID:          result.ID,
+		CheckoutURL: result.InitPoint,
+	}, nil
+}
+
+// CreatePixPayment cria um pagamento Pix pela API de pagamentos. Como o SDK gera uma chave
+// de idempotência nova a cada chamada, um Pix ainda pendente com a mesma referência externa
+// é reaproveitado para que novas tentativas não gerem cobranças duplicadas.
+func (c *MercadoPagoClient) CreatePixPayment(ctx context.Context, req *gateway.PixRequest) (*gateway.PixPayment, error) {
+	existing, err := c.paymentClient.Search(ctx, mpPayment.SearchRequest{
+		Filters: map[string]string{
+			"external_reference": req.ExternalRef,
+			"payment_method_id":  "pix",
+			"status":             "pending",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing.Results) > 0 {
+		return pixPayment(&existing.Results[0]), nil
+	}
+
+	expiresAt := req.ExpiresAt
+	result, err := c.paymentClient.Create(ctx, mpPayment.Request{
+		TransactionAmount: centsToAmount(req.AmountInCents),
+		Description:       req.Description,
+		PaymentMethodID:   "pix",
+		ExternalReference: req.ExternalRef,
+		NotificationURL:   req.NotifyURL,
+		DateOfExpiration:  &expiresAt,
+		Payer: &mpPayment.PayerRequest{
+			Email:     req.PayerEmail,
+			FirstName: req.PayerName,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pixPayment(result), nil
+}
+
+func pixPayment(result *mpPayment.Response) *gateway.PixPayment {
+	data := result.PointOfInteraction.TransactionData
+	return &gateway.PixPayment{
+		ID:           strconv.Itoa(result.ID),
+		Status:       result.Status,
+		QRCode:       data.QRCode,
+		QRCodeBase64: data.QRCodeBase64,
+		TicketURL:    data.TicketURL,
+		ExpiresAt:    result.DateOfExpiration,
+	}
+}
+
+func (c *MercadoPagoClient) GetPayment(ctx context.Context, paymentID string) (*gateway.PaymentInfo, error) {
+	id, err := parsePaymentID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.paymentClient.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return paymentInfo(result), nil
+}
+
+// SearchPayments lista as tentativas de pagamento com a referência externa informada, da
+// mais recente para a mais antiga.
+func (c *MercadoPagoClient) SearchPayments(ctx context.Context, externalRef string) ([]*gateway.PaymentInfo, error) {
+	result, err := c.paymentClient.Search(ctx, mpPayment.SearchRequest{
+		Filters: map[string]string{
+			"external_reference": externalRef,
+			"sort":               "date_created",
+			"criteria":           "desc",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]*gateway.PaymentInfo, 0, len(result.Results))
+	for i := range result.Results {
+		payments = append(payments, paymentInfo(&result.Results[i]))
+	}
+	return payments, nil
+}
+
+func paymentInfo(result *mpPayment.Response) *gateway.PaymentInfo {
+	refunds := make([]gateway.RefundInfo, 0, len(result.Refunds))
+	for _, r := range result.Refunds {
+		refunds = append(refunds, gateway.RefundInfo{
+			ID:            strconv.Itoa(r.ID),
+			PaymentID:     strconv.Itoa(r.PaymentID),
+			Status:        r.Status,
+			AmountInCents: amountToCents(r.Amount),
+		})
+	}
+
+	return &gateway.PaymentInfo{
+		ID:              strconv.Itoa(result.ID),
+		Status:          result.Status,
+		StatusDetail:    result.StatusDetail,
+		PaymentMethod:   result.PaymentMethodID,
+		ExternalRef:     result.ExternalReference,
+		AmountInCents:   amountToCents(result.TransactionAmount),
+		RefundedInCents: amountToCents(result.TransactionAmountRefunded),
+		Refunds:         refunds,
+	}
+}
+
+// Refund devolve o valor informado; amountInCents igual a zero devolve o pagamento integral.
+// A idempotencyKey substitui a chave aleatória do SDK, para que novas tentativas do mesmo
+// estorno não devolvam o valor duas vezes.
+func (c *MercadoPagoClient) Refund(ctx context.Context, paymentID string, amountInCents int64, idempotencyKey string) (*gateway.RefundInfo, error) {
+	id, err := parsePaymentID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if idempotencyKey != "" {
+		ctx = context.WithValue(ctx, idempotencyKeyCtx{}, idempotencyKey)
+	}
+
+	var result *refund.Response
+	if amountInCents > 0 {
+		result, err = c.refundClient.CreatePartialRefund(ctx, id, centsToAmount(amountInCents))
+	} else {
+		result, err = c.refundClient.Create(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &gateway.RefundInfo{
+		ID:            strconv.Itoa(result.ID),
+		PaymentID:     strconv.Itoa(result.PaymentID),
+		Status:        result.Status,
+		AmountInCents: amountToCents(result.Amount),
 	}, nil
 }
 
+// CreateSubscription cria uma preapproval pendente; o aluno autoriza a cobrança recorrente
+// no link retornado.
+func (c *MercadoPagoClient) CreateSubscription(ctx context.Context, req *gateway.SubscriptionRequest) (*gateway.SubscriptionInfo, error) {
+	result, err := c.preapprovals.Create(ctx, preapproval.Request{
+		AutoRecurring: &preapproval.AutoRecurringRequest{
+			Frequency:         1,
+			FrequencyType:     "months",
+			TransactionAmount: centsToAmount(req.AmountInCents),
+			CurrencyID:        "BRL",
+		},
+		Reason:            req.Reason,
+		PayerEmail:        req.PayerEmail,
+		ExternalReference: req.ExternalRef,
+		BackURL:           req.BackURL,
+		Status:            "pending",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptionInfo(result), nil
+}
+
+func (c *MercadoPagoClient) GetSubscription(ctx context.Context, subscriptionID string) (*gateway.SubscriptionInfo, error) {
+	result, err := c.preapprovals.Get(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptionInfo(result), nil
+}
+
+func (c *MercadoPagoClient) CancelSubscription(ctx context.Context, subscriptionID string) error {
+	_, err := c.preapprovals.Update(ctx, subscriptionID, preapproval.UpdateRequest{Status: "cancelled"})
+	return err
+}
+
+func (c *MercadoPagoClient) GetAuthorizedPayment(ctx context.Context, authorizedPaymentID string) (*gateway.AuthorizedPaymentInfo, error) {
+	result, err := c.invoices.Get(ctx, authorizedPaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &gateway.AuthorizedPaymentInfo{
+		ID:             strconv.Itoa(result.ID),
+		SubscriptionID: result.PreapprovalID,
+		ExternalRef:    result.ExternalReference,
+		PaymentStatus:  result.Payment.Status,
+		PaymentMethod:  result.PaymentMethodID,
+		AmountInCents:  amountToCents(result.TransactionAmount),
+		DebitDate:      result.DebitDate,
+	}
+	if result.Payment.ID != 0 {
+		info.PaymentID = strconv.Itoa(result.Payment.ID)
+	}
+
+	return info, nil
+}
+
+func subscriptionInfo(result *preapproval.Response) *gateway.SubscriptionInfo {
+	return &gateway.SubscriptionInfo{
+		ID:          result.ID,
+		Status:      result.Status,
+		ExternalRef: result.ExternalReference,
+		CheckoutURL: result.InitPoint,
+	}
+}
+
+func parsePaymentID(paymentID string) (int, error) {
+	id, err := strconv.Atoi(paymentID)
+	if err != nil {
+		return 0, fmt.Errorf("id de pagamento inválido: %s", paymentID)
+	}
+	return id, nil
+}
+
+func centsToAmount(cents int64) float64 {
+	return float64(cents) / 100.0
+}
+
+func amountToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+type idempotencyKeyCtx struct{}
+
+// idempotencyRequester aplica a chave de idempotência guardada no contexto da requisição,
+// já que o SDK gera uma chave nova a cada chamada.
+type idempotencyRequester struct {
+	next requester.Requester
+}
+
+func (r *idempotencyRequester) Do(req *http.Request) (*http.Response, error) {
+	if key, ok := req.Context().Value(idempotencyKeyCtx{}).(string); ok {
+		req.Header.Set("X-Idempotency-Key", key)
+	}
+	return r.next.Do(req)
+}
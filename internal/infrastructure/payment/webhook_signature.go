@@ -0,0 +1,97 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("assinatura do webhook inválida")
+	ErrStaleSignature   = errors.New("assinatura do webhook expirada")
+	ErrDataIDMismatch   = errors.New("data.id da URL difere do corpo do webhook")
+)
+
+// SignedDataID devolve o data.id que entra no manifesto assinado. O Mercado Pago assina o
+// data.id da URL; quando ele vem junto com um data.id diferente no corpo, a notificação é
+// rejeitada, já que o processamento usa o id do corpo.
+func SignedDataID(queryDataID, bodyDataID string) (string, error) {
+	if queryDataID == "" {
+		return bodyDataID, nil
+	}
+	if bodyDataID != "" && !strings.EqualFold(queryDataID, bodyDataID) {
+		return "", ErrDataIDMismatch
+	}
+	return queryDataID, nil
+}
+
+// ValidateWebhookSignature valida o cabeçalho x-signature enviado pelo Mercado Pago.
+// O manifesto assinado segue o formato "id:<data.id>;request-id:<x-request-id>;ts:<ts>;".
+func ValidateWebhookSignature(secret, signature, requestID, dataID string, tolerance time.Duration, now time.Time) error {
+	ts, v1 := parseSignatureHeader(signature)
+	if ts == "" || v1 == "" {
+		return ErrInvalidSignature
+	}
+
+	var manifest strings.Builder
+	if dataID != "" {
+		fmt.Fprintf(&manifest, "id:%s;", strings.ToLower(dataID))
+	}
+	if requestID != "" {
+		fmt.Fprintf(&manifest, "request-id:%s;", requestID)
+	}
+	fmt.Fprintf(&manifest, "ts:%s;", ts)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(manifest.String()))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(v1))) {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		signedAt, err := parseSignatureTimestamp(ts)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if now.Sub(signedAt).Abs() > tolerance {
+			return ErrStaleSignature
+		}
+	}
+
+	return nil
+}
+
+func parseSignatureHeader(signature string) (ts, v1 string) {
+	for _, part := range strings.Split(signature, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "ts":
+			ts = strings.TrimSpace(value)
+		case "v1":
+			v1 = strings.TrimSpace(value)
+		}
+	}
+	return ts, v1
+}
+
+// parseSignatureTimestamp aceita o ts em segundos ou milissegundos.
+func parseSignatureTimestamp(ts string) (time.Time, error) {
+	value, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if value > 1e12 {
+		return time.UnixMilli(value), nil
+	}
+	return time.Unix(value, 0), nil
+}
@@ -0,0 +1,126 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func sign(secret, dataID, requestID string, ts int64) string {
+	manifest := fmt.Sprintf("id:%s;request-id:%s;ts:%d;", dataID, requestID, ts)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(manifest))
+	return fmt.Sprintf("ts=%d,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestValidateWebhookSignature(t *testing.T) {
+	const (
+		secret    = "segredo"
+		dataID    = "123456"
+		requestID = "req-1"
+		tolerance = 5 * time.Minute
+	)
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name      string
+		signature string
+		dataID    string
+		tolerance time.Duration
+		wantErr   error
+	}{
+		{
+			name:      "assinatura válida",
+			signature: sign(secret, dataID, requestID, now.Unix()),
+			dataID:    dataID,
+			tolerance: tolerance,
+		},
+		{
+			name:      "ts em milissegundos",
+			signature: sign(secret, dataID, requestID, now.UnixMilli()),
+			dataID:    dataID,
+			tolerance: tolerance,
+		},
+		{
+			name:      "segredo errado",
+			signature: sign("outro-segredo", dataID, requestID, now.Unix()),
+			dataID:    dataID,
+			tolerance: tolerance,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "ts fora da tolerância",
+			signature: sign(secret, dataID, requestID, now.Add(-10*time.Minute).Unix()),
+			dataID:    dataID,
+			tolerance: tolerance,
+			wantErr:   ErrStaleSignature,
+		},
+		{
+			name:      "ts antigo sem tolerância configurada",
+			signature: sign(secret, dataID, requestID, now.Add(-10*time.Minute).Unix()),
+			dataID:    dataID,
+		},
+		{
+			name:      "data.id diferente do assinado",
+			signature: sign(secret, dataID, requestID, now.Unix()),
+			dataID:    "999999",
+			tolerance: tolerance,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "cabeçalho sem v1",
+			signature: "ts=" + strconv.FormatInt(now.Unix(), 10),
+			dataID:    dataID,
+			tolerance: tolerance,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "cabeçalho vazio",
+			dataID:    dataID,
+			tolerance: tolerance,
+			wantErr:   ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhookSignature(secret, tt.signature, requestID, tt.dataID, tt.tolerance, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateWebhookSignature() erro = %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignedDataID(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		body    string
+		want    string
+		wantErr error
+	}{
+		{name: "somente na URL", query: "123", want: "123"},
+		{name: "somente no corpo", body: "123", want: "123"},
+		{name: "URL e corpo iguais", query: "abc", body: "ABC", want: "abc"},
+		{name: "URL e corpo diferentes", query: "123", body: "456", wantErr: ErrDataIDMismatch},
+		{name: "ausente", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SignedDataID(tt.query, tt.body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SignedDataID() erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("SignedDataID() = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
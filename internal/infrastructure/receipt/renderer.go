@@ -0,0 +1,233 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+)
+
+// Renderer gera o recibo em HTML e em PDF a partir dos dados gravados na emissão.
+type Renderer struct {
+	html *template.Template
+}
+
+func NewRenderer() *Renderer {
+	return &Renderer{
+		html: template.Must(template.New("receipt").Funcs(template.FuncMap{
+			"money":    formatMoney,
+			"datetime": entity.FormatLocalTime,
+			"cpf":      formatCPF,
+			"method":   formatMethod,
+		}).Parse(htmlTemplate)),
+	}
+}
+
+func (r *Renderer) HTML(w io.Writer, receipt *entity.Receipt) error {
+	return r.html.Execute(w, receipt)
+}
+
+func (r *Renderer) PDF(w io.Writer, receipt *entity.Receipt) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Recibo "+receipt.Code(), true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+
+	// As fontes padrão do PDF usam cp1252; o tradutor converte os acentos do UTF-8.
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, tr(receipt.Studio.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range studioLines(receipt.Studio) {
+		pdf.CellFormat(0, 5, tr(line), "", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, tr("Recibo nº "+receipt.Code()), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 5, tr("Emitido em "+entity.FormatLocalTime(receipt.IssuedAt)), "", 1, "L", false, 0, "")
+
+	pdf.Ln(6)
+	section(pdf, tr, "Aluno")
+	field(pdf, tr, "Nome", receipt.Student.Name)
+	if receipt.Student.CPF != "" {
+		field(pdf, tr, "CPF", formatCPF(receipt.Student.CPF))
+	}
+	field(pdf, tr, "E-mail", receipt.Student.Email)
+
+	pdf.Ln(4)
+	section(pdf, tr, "Referente a")
+	field(pdf, tr, "Item", receipt.Item.Description)
+	if receipt.Item.Details != "" {
+		field(pdf, tr, "Detalhes", receipt.Item.Details)
+	}
+
+	pdf.Ln(4)
+	section(pdf, tr, "Pagamento")
+	if receipt.DiscountInCents > 0 {
+		field(pdf, tr, "Valor original", formatMoney(receipt.AmountInCents+receipt.DiscountInCents))
+		field(pdf, tr, "Desconto", formatMoney(receipt.DiscountInCents)+couponSuffix(receipt.CouponCode))
+	}
+	field(pdf, tr, "Valor pago", formatMoney(receipt.AmountInCents))
+	field(pdf, tr, "Forma de pagamento", formatMethod(receipt.PaymentMethod))
+	field(pdf, tr, "Data do pagamento", entity.FormatLocalTime(receipt.PaidAt))
+	if receipt.MercadoPagoID != "" {
+		field(pdf, tr, "Transação", receipt.MercadoPagoID)
+	}
+
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(0, 5, tr(fmt.Sprintf(
+		"Recebemos de %s a importância de %s referente ao item acima.",
+		receipt.Student.Name, formatMoney(receipt.AmountInCents),
+	)), "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return fmt.Errorf("erro ao gerar PDF do recibo: %w", err)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func section(pdf *fpdf.Fpdf, tr func(string) string, title string) {
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 7, tr(title), "B", 1, "L", false, 0, "")
+	pdf.Ln(1)
+}
+
+func field(pdf *fpdf.Fpdf, tr func(string) string, label, value string) {
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(45, 6, tr(label+":"), "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(0, 6, tr(value), "", "L", false)
+}
+
+func studioLines(studio entity.ReceiptStudio) []string {
+	var lines []string
+	if studio.Document != "" {
+		lines = append(lines, "CNPJ/CPF: "+studio.Document)
+	}
+	if studio.Address != "" {
+		lines = append(lines, studio.Address)
+	}
+	if studio.Email != "" {
+		lines = append(lines, studio.Email)
+	}
+	return lines
+}
+
+func couponSuffix(code string) string {
+	if code == "" {
+		return ""
+	}
+	return " (cupom " + code + ")"
+}
+
+// formatMoney formata centavos no padrão brasileiro, como R$ 1.234,56.
+func formatMoney(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	units := fmt.Sprintf("%d", cents/100)
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
+
+func formatCPF(cpf string) string {
+	if len(cpf) != 11 {
+		return cpf
+	}
+	return cpf[0:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:11]
+}
+
+func formatMethod(method string) string {
+	switch method {
+	case "pix":
+		return "Pix"
+	case "coupon":
+		return "Cupom de desconto"
+	case "credit_card", "master", "visa", "amex", "elo", "hipercard":
+		return "Cartão de crédito"
+	case "debit_card":
+		return "Cartão de débito"
+	case "bolbradesco", "boleto":
+		return "Boleto"
+	case "account_money":
+		return "Saldo Mercado Pago"
+	case "":
+		return "Mercado Pago"
+	default:
+		return method
+	}
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>Recibo {{.Code}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; max-width: 720px; margin: 40px auto; color: #222; }
+h1 { font-size: 22px; margin-bottom: 4px; }
+h2 { font-size: 18px; margin-top: 32px; }
+h3 { font-size: 14px; border-bottom: 1px solid #ccc; padding-bottom: 4px; margin-top: 24px; }
+dl { display: grid; grid-template-columns: 180px 1fr; row-gap: 6px; margin: 0; }
+dt { font-weight: bold; }
+dd { margin: 0; }
+.studio p { margin: 2px 0; font-size: 14px; }
+</style>
+</head>
+<body>
+<div class="studio">
+<h1>{{.Studio.Name}}</h1>
+{{if .Studio.Document}}<p>CNPJ/CPF: {{.Studio.Document}}</p>{{end}}
+{{if .Studio.Address}}<p>{{.Studio.Address}}</p>{{end}}
+{{if .Studio.Email}}<p>{{.Studio.Email}}</p>{{end}}
+</div>
+
+<h2>Recibo nº {{.Code}}</h2>
+<p>Emitido em {{datetime .IssuedAt}}</p>
+
+<h3>Aluno</h3>
+<dl>
+<dt>Nome</dt><dd>{{.Student.Name}}</dd>
+{{if .Student.CPF}}<dt>CPF</dt><dd>{{cpf .Student.CPF}}</dd>{{end}}
+<dt>E-mail</dt><dd>{{.Student.Email}}</dd>
+</dl>
+
+<h3>Referente a</h3>
+<dl>
+<dt>Item</dt><dd>{{.Item.Description}}</dd>
+{{if .Item.Details}}<dt>Detalhes</dt><dd>{{.Item.Details}}</dd>{{end}}
+</dl>
+
+<h3>Pagamento</h3>
+<dl>
+{{if .DiscountInCents}}<dt>Desconto</dt><dd>{{money .DiscountInCents}}{{if .CouponCode}} (cupom {{.CouponCode}}){{end}}</dd>{{end}}
+<dt>Valor pago</dt><dd>{{money .AmountInCents}}</dd>
+<dt>Forma de pagamento</dt><dd>{{method .PaymentMethod}}</dd>
+<dt>Data do pagamento</dt><dd>{{datetime .PaidAt}}</dd>
+{{if .MercadoPagoID}}<dt>Transação</dt><dd>{{.MercadoPagoID}}</dd>{{end}}
+</dl>
+
+<p style="margin-top: 32px">Recebemos de {{.Student.Name}} a importância de {{money .AmountInCents}} referente ao item acima.</p>
+</body>
+</html>
+`
@@ -2,12 +2,16 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ClassRepository struct {
@@ -25,6 +29,9 @@ func NewClassRepository(db *mongo.Database, client *mongo.Client) *ClassReposito
 func (r *ClassRepository) Create(ctx context.Context, class *entity.Class) error {
 	_, err := r.collection.InsertOne(ctx, class)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) && class.SeriesID != nil {
+			return repository.ErrOccurrenceExists
+		}
 		return fmt.Errorf("erro ao inserir aula: %w", err)
 	}
 	return nil
@@ -35,7 +42,7 @@ func (r *ClassRepository) FindByID(ctx context.Context, id primitive.ObjectID) (
 	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&class)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("aula não encontrada")
+			return nil, repository.ErrClassNotFound
 		}
 		return nil, fmt.Errorf("erro ao buscar aula: %w", err)
 	}
@@ -61,6 +68,142 @@ func (r *ClassRepository) FindAll(ctx context.Context) ([]*entity.Class, error)
 	return classes, nil
 }
 
+func (r *ClassRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*entity.Class, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aulas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var classes []*entity.Class
+	if err = cursor.All(ctx, &classes); err != nil {
+		return nil, fmt.Errorf("erro ao processar aulas: %w", err)
+	}
+
+	if classes == nil {
+		classes = []*entity.Class{}
+	}
+
+	return classes, nil
+}
+
+// EnsureIndexes cria os índices usados pela listagem e pelas séries de aulas. Cada
+// ocorrência de uma série é única, o que impede que dois workers gerem a mesma aula.
+func (r *ClassRepository) EnsureIndexes(ctx context.Context) error {
+	// Remove o índice não único das versões anteriores, substituído pelo índice único abaixo.
+	if _, err := r.collection.Indexes().DropOne(ctx, "series_id_1_occurrence_start_1"); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("erro ao remover índice antigo de ocorrências: %w", err)
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "start_time", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "instructor_id", Value: 1}, {Key: "start_time", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "start_time", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "occurrence_start", Value: 1}},
+			Options: options.Index().
+				SetName("series_occurrence_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"series_id": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetDefaultLanguage("portuguese"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de aulas: %w", err)
+	}
+	return nil
+}
+
+// isIndexNotFound reconhece os erros de remoção de um índice ou coleção inexistente.
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == 26 || cmdErr.Code == 27
+	}
+	return false
+}
+
+func (r *ClassRepository) List(ctx context.Context, filter repository.ClassFilter) ([]*entity.Class, error) {
+	query := bson.M{}
+
+	startTime := bson.M{}
+	if filter.From != nil {
+		startTime["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		startTime["$lte"] = *filter.To
+	}
+	if len(startTime) > 0 {
+		query["start_time"] = startTime
+	}
+	if filter.InstructorID != nil {
+		query["instructor_id"] = *filter.InstructorID
+	}
+	if len(filter.Statuses) > 0 {
+		query["status"] = bson.M{"$in": filter.Statuses}
+	}
+	if filter.HasSpots {
+		query["$expr"] = bson.M{"$lt": bson.A{"$current_enrolled", "$max_capacity"}}
+	}
+	if filter.Search != "" {
+		query["$text"] = bson.M{"$search": filter.Search}
+	}
+
+	sortField := filter.SortBy
+	if sortField == "" {
+		sortField = repository.ClassSortStartTime
+	}
+	direction := 1
+	if filter.Descending {
+		direction = -1
+	}
+
+	if filter.After != nil {
+		var value interface{}
+		switch sortField {
+		case repository.ClassSortPrice:
+			value = filter.After.PriceInCents
+		case repository.ClassSortCreatedAt:
+			value = filter.After.CreatedAt
+		default:
+			value = filter.After.StartTime
+		}
+
+		operator := "$gt"
+		if filter.Descending {
+			operator = "$lt"
+		}
+		query["$or"] = bson.A{
+			bson.M{sortField: bson.M{operator: value}},
+			bson.M{sortField: value, "_id": bson.M{operator: filter.After.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(filter.Limit)
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aulas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var classes []*entity.Class
+	if err = cursor.All(ctx, &classes); err != nil {
+		return nil, fmt.Errorf("erro ao processar aulas: %w", err)
+	}
+
+	if classes == nil {
+		classes = []*entity.Class{}
+	}
+
+	return classes, nil
+}
+
 func (r *ClassRepository) Update(ctx context.Context, class *entity.Class) error {
 	update := bson.M{
 		"$set": class,
@@ -72,12 +215,144 @@ func (r *ClassRepository) Update(ctx context.Context, class *entity.Class) error
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("aula não encontrada")
+		return repository.ErrClassNotFound
+	}
+
+	return nil
+}
+
+// UpdateDetails altera apenas os dados editáveis da aula, sem sobrescrever a ocupação.
+// A atualização só é aplicada se a aula ainda estiver na versão lida: falha com
+// ErrVersionConflict se ela mudou desde então e com ErrCapacityBelow se a nova capacidade
+// for menor que os inscritos.
+func (r *ClassRepository) UpdateDetails(ctx context.Context, class *entity.Class) error {
+	update := bson.M{
+		"$set": bson.M{
+			"title":            class.Title,
+			"description":      class.Description,
+			"instructor_id":    class.InstructorID,
+			"instructor_name":  class.InstructorName,
+			"start_time":       class.StartTime,
+			"end_time":         class.EndTime,
+			"max_capacity":     class.MaxCapacity,
+			"price_in_cents":   class.PriceInCents,
+			"status":           class.Status,
+			"series_id":        class.SeriesID,
+			"occurrence_start": class.OccurrenceStart,
+			"updated_at":       time.Now(),
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	filter := bson.M{
+		"_id":              class.ID,
+		"version":          class.Version,
+		"current_enrolled": bson.M{"$lte": class.MaxCapacity},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar aula: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		current, err := r.FindByID(ctx, class.ID)
+		if err != nil {
+			return err
+		}
+		if current.Version != class.Version {
+			return repository.ErrVersionConflict
+		}
+		return repository.ErrCapacityBelow
 	}
 
+	class.Version++
 	return nil
 }
 
+func (r *ClassRepository) Touch(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"updated_at": time.Now()}})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar aula: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrClassNotFound
+	}
+
+	return nil
+}
+
+// StartDue marca como em andamento as aulas publicadas cujo horário de início já passou.
+func (r *ClassRepository) StartDue(ctx context.Context, now time.Time) (int64, error) {
+	filter := bson.M{
+		"status":     bson.M{"$in": []string{entity.ClassStatusPublished, entity.ClassStatusActive}},
+		"start_time": bson.M{"$lte": now},
+	}
+	return r.updateStatus(ctx, filter, entity.ClassStatusInProgress)
+}
+
+// CompleteDue marca como concluídas as aulas em andamento cujo horário de término já passou.
+func (r *ClassRepository) CompleteDue(ctx context.Context, now time.Time) (int64, error) {
+	filter := bson.M{
+		"status":   entity.ClassStatusInProgress,
+		"end_time": bson.M{"$lte": now},
+	}
+	return r.updateStatus(ctx, filter, entity.ClassStatusCompleted)
+}
+
+func (r *ClassRepository) updateStatus(ctx context.Context, filter bson.M, status string) (int64, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"status":     status,
+			"updated_at": time.Now(),
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao atualizar status das aulas: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *ClassRepository) FindBySeries(ctx context.Context, seriesID primitive.ObjectID, from time.Time) ([]*entity.Class, error) {
+	filter := bson.M{
+		"series_id":        seriesID,
+		"occurrence_start": bson.M{"$gte": from},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "occurrence_start", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aulas da série: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var classes []*entity.Class
+	if err = cursor.All(ctx, &classes); err != nil {
+		return nil, fmt.Errorf("erro ao processar aulas da série: %w", err)
+	}
+
+	return classes, nil
+}
+
+func (r *ClassRepository) FindBySeriesOccurrence(ctx context.Context, seriesID primitive.ObjectID, occurrenceStart time.Time) (*entity.Class, error) {
+	var class entity.Class
+	err := r.collection.FindOne(ctx, bson.M{
+		"series_id":        seriesID,
+		"occurrence_start": occurrenceStart,
+	}).Decode(&class)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar ocorrência da série: %w", err)
+	}
+	return &class, nil
+}
+
 func (r *ClassRepository) IncrementEnrollmentWithVersion(ctx context.Context, classID primitive.ObjectID, currentVersion int) error {
 	update := bson.M{
 		"$inc": bson.M{
@@ -85,7 +360,7 @@ func (r *ClassRepository) IncrementEnrollmentWithVersion(ctx context.Context, cl
 			"version": 1,
 		},
 		"$set": bson.M{
-			"updated_at": primitive.NewDateTimeFromTime(entity.Class{}.UpdatedAt),
+			"updated_at": time.Now(),
 		},
 	}
 
@@ -103,17 +378,35 @@ func (r *ClassRepository) IncrementEnrollmentWithVersion(ctx context.Context, cl
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("aula sem vagas ou versão desatualizada")
+		return r.incrementFailureReason(ctx, classID)
 	}
 
 	return nil
 }
 
+// incrementFailureReason relê a aula para distinguir lotação de conflito de versão
+// quando o update condicional não encontra documento.
+func (r *ClassRepository) incrementFailureReason(ctx context.Context, classID primitive.ObjectID) error {
+	class, err := r.FindByID(ctx, classID)
+	if err != nil {
+		return err
+	}
+
+	if !class.HasAvailableSpots() {
+		return repository.ErrClassFull
+	}
+
+	return repository.ErrVersionConflict
+}
+
 func (r *ClassRepository) DecrementEnrollment(ctx context.Context, classID primitive.ObjectID) error {
 	update := bson.M{
 		"$inc": bson.M{
 			"current_enrolled": -1,
 		},
+		"$set": bson.M{
+			"updated_at": time.Now(),
+		},
 	}
 
 	filter := bson.M{
@@ -0,0 +1,109 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ClassSeriesRepository struct {
+	collection *mongo.Collection
+}
+
+func NewClassSeriesRepository(db *mongo.Database) *ClassSeriesRepository {
+	return &ClassSeriesRepository{
+		collection: db.Collection("class_series"),
+	}
+}
+
+func (r *ClassSeriesRepository) Create(ctx context.Context, series *entity.ClassSeries) error {
+	_, err := r.collection.InsertOne(ctx, series)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir série de aulas: %w", err)
+	}
+	return nil
+}
+
+func (r *ClassSeriesRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.ClassSeries, error) {
+	var series entity.ClassSeries
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&series)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrClassSeriesNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar série de aulas: %w", err)
+	}
+	return &series, nil
+}
+
+func (r *ClassSeriesRepository) FindActive(ctx context.Context) ([]*entity.ClassSeries, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"status": entity.ClassSeriesStatusActive})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar séries de aulas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var series []*entity.ClassSeries
+	if err = cursor.All(ctx, &series); err != nil {
+		return nil, fmt.Errorf("erro ao processar séries de aulas: %w", err)
+	}
+
+	return series, nil
+}
+
+// UpdateMaterialization avança materialized_until e encerra a série quando ela não gera
+// mais ocorrências. Só esses campos são gravados, para não desfazer exceções ou divisões
+// salvas depois que o worker leu a série.
+func (r *ClassSeriesRepository) UpdateMaterialization(ctx context.Context, series *entity.ClassSeries) error {
+	set := bson.M{"updated_at": time.Now()}
+	if series.Status == entity.ClassSeriesStatusEnded {
+		set["status"] = series.Status
+	}
+
+	update := bson.M{
+		"$max": bson.M{"materialized_until": series.MaterializedUntil},
+		"$set": set,
+	}
+	return r.updateOne(ctx, series.ID, update)
+}
+
+// AddException exclui a ocorrência da série sem regravar o restante do documento.
+func (r *ClassSeriesRepository) AddException(ctx context.Context, id primitive.ObjectID, occurrence time.Time) error {
+	update := bson.M{
+		"$addToSet": bson.M{"recurrence.exceptions": occurrence},
+		"$set":      bson.M{"updated_at": time.Now()},
+	}
+	return r.updateOne(ctx, id, update)
+}
+
+// UpdateRecurrenceEnd grava o novo término da série após uma divisão.
+func (r *ClassSeriesRepository) UpdateRecurrenceEnd(ctx context.Context, series *entity.ClassSeries) error {
+	update := bson.M{
+		"$set": bson.M{
+			"recurrence.until": series.Recurrence.Until,
+			"recurrence.count": series.Recurrence.Count,
+			"status":           series.Status,
+			"updated_at":       time.Now(),
+		},
+	}
+	return r.updateOne(ctx, series.ID, update)
+}
+
+func (r *ClassSeriesRepository) updateOne(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar série de aulas: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrClassSeriesNotFound
+	}
+
+	return nil
+}
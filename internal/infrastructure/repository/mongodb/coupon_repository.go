@@ -0,0 +1,208 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CouponRepository struct {
+	coupons     *mongo.Collection
+	redemptions *mongo.Collection
+}
+
+func NewCouponRepository(db *mongo.Database) *CouponRepository {
+	return &CouponRepository{
+		coupons:     db.Collection("coupons"),
+		redemptions: db.Collection("coupon_redemptions"),
+	}
+}
+
+func (r *CouponRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.coupons.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de cupons: %w", err)
+	}
+
+	_, err = r.redemptions.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "coupon_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "enrollment_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de resgates de cupons: %w", err)
+	}
+	return nil
+}
+
+func (r *CouponRepository) Create(ctx context.Context, coupon *entity.Coupon) error {
+	_, err := r.coupons.InsertOne(ctx, coupon)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrCouponCodeTaken
+		}
+		return fmt.Errorf("erro ao inserir cupom: %w", err)
+	}
+	return nil
+}
+
+func (r *CouponRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Coupon, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *CouponRepository) FindByCode(ctx context.Context, code string) (*entity.Coupon, error) {
+	return r.findOne(ctx, bson.M{"code": code})
+}
+
+func (r *CouponRepository) findOne(ctx context.Context, filter bson.M) (*entity.Coupon, error) {
+	var coupon entity.Coupon
+	err := r.coupons.FindOne(ctx, filter).Decode(&coupon)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrCouponNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar cupom: %w", err)
+	}
+	return &coupon, nil
+}
+
+func (r *CouponRepository) FindAll(ctx context.Context) ([]*entity.Coupon, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.coupons.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cupons: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var coupons []*entity.Coupon
+	if err = cursor.All(ctx, &coupons); err != nil {
+		return nil, fmt.Errorf("erro ao processar cupons: %w", err)
+	}
+
+	if coupons == nil {
+		coupons = []*entity.Coupon{}
+	}
+
+	return coupons, nil
+}
+
+// Update grava as regras do cupom sem tocar no contador de resgates, que só é alterado
+// por Redeem e ReleaseByEnrollment.
+func (r *CouponRepository) Update(ctx context.Context, coupon *entity.Coupon) error {
+	update := bson.M{
+		"$set": bson.M{
+			"code":            coupon.Code,
+			"type":            coupon.Type,
+			"value":           coupon.Value,
+			"valid_from":      coupon.ValidFrom,
+			"valid_until":     coupon.ValidUntil,
+			"max_redemptions": coupon.MaxRedemptions,
+			"max_per_user":    coupon.MaxPerUser,
+			"class_ids":       coupon.ClassIDs,
+			"instructor_ids":  coupon.InstructorIDs,
+			"active":          coupon.Active,
+			"updated_at":      coupon.UpdatedAt,
+		},
+	}
+
+	result, err := r.coupons.UpdateOne(ctx, bson.M{"_id": coupon.ID}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrCouponCodeTaken
+		}
+		return fmt.Errorf("erro ao atualizar cupom: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrCouponNotFound
+	}
+
+	return nil
+}
+
+// Redeem incrementa o contador do cupom apenas se o limite global não foi atingido. Como
+// todo resgate altera o documento do cupom, transações concorrentes sobre o mesmo cupom
+// entram em conflito de escrita e são repetidas, o que mantém a contagem por aluno correta.
+func (r *CouponRepository) Redeem(ctx context.Context, redemption *entity.CouponRedemption, maxPerUser int) error {
+	filter := bson.M{
+		"_id": redemption.CouponID,
+		"$or": bson.A{
+			bson.M{"max_redemptions": 0},
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$redemptions", "$max_redemptions"}}},
+		},
+	}
+	update := bson.M{
+		"$inc": bson.M{"redemptions": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.coupons.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("erro ao resgatar cupom: %w", err)
+	}
+	if result.ModifiedCount == 0 {
+		return repository.ErrCouponExhausted
+	}
+
+	if maxPerUser > 0 {
+		used, err := r.redemptions.CountDocuments(ctx, bson.M{
+			"coupon_id": redemption.CouponID,
+			"user_id":   redemption.UserID,
+			"status":    entity.CouponRedemptionStatusActive,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao contar resgates do cupom: %w", err)
+		}
+		if used >= int64(maxPerUser) {
+			return repository.ErrCouponUserLimit
+		}
+	}
+
+	if _, err := r.redemptions.InsertOne(ctx, redemption); err != nil {
+		return fmt.Errorf("erro ao registrar resgate do cupom: %w", err)
+	}
+	return nil
+}
+
+func (r *CouponRepository) ReleaseByEnrollment(ctx context.Context, enrollmentID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"enrollment_id": enrollmentID,
+		"status":        entity.CouponRedemptionStatusActive,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     entity.CouponRedemptionStatusReleased,
+			"updated_at": time.Now(),
+		},
+	}
+
+	var redemption entity.CouponRedemption
+	err := r.redemptions.FindOneAndUpdate(ctx, filter, update).Decode(&redemption)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, fmt.Errorf("erro ao liberar resgate do cupom: %w", err)
+	}
+
+	_, err = r.coupons.UpdateOne(ctx,
+		bson.M{"_id": redemption.CouponID, "redemptions": bson.M{"$gt": 0}},
+		bson.M{
+			"$inc": bson.M{"redemptions": -1},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("erro ao devolver uso do cupom: %w", err)
+	}
+	return true, nil
+}
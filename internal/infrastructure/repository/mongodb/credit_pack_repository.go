@@ -0,0 +1,132 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CreditPackRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCreditPackRepository(db *mongo.Database) *CreditPackRepository {
+	return &CreditPackRepository{
+		collection: db.Collection("credit_packs"),
+	}
+}
+
+func (r *CreditPackRepository) Create(ctx context.Context, pack *entity.CreditPack) error {
+	_, err := r.collection.InsertOne(ctx, pack)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir pacote de créditos: %w", err)
+	}
+	return nil
+}
+
+func (r *CreditPackRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.CreditPack, error) {
+	var pack entity.CreditPack
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&pack)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrCreditPackNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar pacote de créditos: %w", err)
+	}
+	return &pack, nil
+}
+
+func (r *CreditPackRepository) FindAll(ctx context.Context, activeOnly bool) ([]*entity.CreditPack, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "price_in_cents", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pacotes de créditos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var packs []*entity.CreditPack
+	if err = cursor.All(ctx, &packs); err != nil {
+		return nil, fmt.Errorf("erro ao processar pacotes de créditos: %w", err)
+	}
+
+	if packs == nil {
+		packs = []*entity.CreditPack{}
+	}
+
+	return packs, nil
+}
+
+func (r *CreditPackRepository) Update(ctx context.Context, pack *entity.CreditPack) error {
+	update := bson.M{
+		"$set": pack,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": pack.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar pacote de créditos: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrCreditPackNotFound
+	}
+
+	return nil
+}
+
+type CreditPurchaseRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCreditPurchaseRepository(db *mongo.Database) *CreditPurchaseRepository {
+	return &CreditPurchaseRepository{
+		collection: db.Collection("credit_purchases"),
+	}
+}
+
+func (r *CreditPurchaseRepository) Create(ctx context.Context, purchase *entity.CreditPurchase) error {
+	_, err := r.collection.InsertOne(ctx, purchase)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir compra de créditos: %w", err)
+	}
+	return nil
+}
+
+func (r *CreditPurchaseRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.CreditPurchase, error) {
+	var purchase entity.CreditPurchase
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&purchase)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrCreditPurchaseNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar compra de créditos: %w", err)
+	}
+	return &purchase, nil
+}
+
+func (r *CreditPurchaseRepository) Update(ctx context.Context, purchase *entity.CreditPurchase) error {
+	update := bson.M{
+		"$set": purchase,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": purchase.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar compra de créditos: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrCreditPurchaseNotFound
+	}
+
+	return nil
+}
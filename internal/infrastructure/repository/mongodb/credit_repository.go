@@ -0,0 +1,190 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CreditRepository struct {
+	lots    *mongo.Collection
+	entries *mongo.Collection
+}
+
+func NewCreditRepository(db *mongo.Database) *CreditRepository {
+	return &CreditRepository{
+		lots:    db.Collection("credit_lots"),
+		entries: db.Collection("credit_entries"),
+	}
+}
+
+func (r *CreditRepository) CreateLot(ctx context.Context, lot *entity.CreditLot) error {
+	_, err := r.lots.InsertOne(ctx, lot)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir lote de créditos: %w", err)
+	}
+	return nil
+}
+
+func (r *CreditRepository) FindLotByPurchaseID(ctx context.Context, purchaseID primitive.ObjectID) (*entity.CreditLot, error) {
+	var lot entity.CreditLot
+	err := r.lots.FindOne(ctx, bson.M{"purchase_id": purchaseID}).Decode(&lot)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar lote de créditos: %w", err)
+	}
+	return &lot, nil
+}
+
+func (r *CreditRepository) FindLotsByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.CreditLot, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "expires_at", Value: 1}})
+	cursor, err := r.lots.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar lotes de créditos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var lots []*entity.CreditLot
+	if err = cursor.All(ctx, &lots); err != nil {
+		return nil, fmt.Errorf("erro ao processar lotes de créditos: %w", err)
+	}
+
+	if lots == nil {
+		lots = []*entity.CreditLot{}
+	}
+
+	return lots, nil
+}
+
+func (r *CreditRepository) Consume(ctx context.Context, userID primitive.ObjectID, now time.Time) (*entity.CreditLot, error) {
+	filter := bson.M{
+		"user_id":    userID,
+		"status":     entity.CreditLotStatusActive,
+		"remaining":  bson.M{"$gt": 0},
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{
+		"$inc": bson.M{"remaining": -1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "expires_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var lot entity.CreditLot
+	err := r.lots.FindOneAndUpdate(ctx, filter, update, opts).Decode(&lot)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrNoCredits
+		}
+		return nil, fmt.Errorf("erro ao consumir crédito: %w", err)
+	}
+	return &lot, nil
+}
+
+func (r *CreditRepository) Restore(ctx context.Context, lotID primitive.ObjectID, now time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":        lotID,
+		"status":     entity.CreditLotStatusActive,
+		"expires_at": bson.M{"$gt": now},
+		"$expr":      bson.M{"$lt": bson.A{"$remaining", "$total"}},
+	}
+	update := bson.M{
+		"$inc": bson.M{"remaining": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.lots.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("erro ao devolver crédito: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *CreditRepository) Revoke(ctx context.Context, lotID primitive.ObjectID) (int, error) {
+	filter := bson.M{
+		"_id":    lotID,
+		"status": entity.CreditLotStatusActive,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     entity.CreditLotStatusRevoked,
+			"remaining":  0,
+			"updated_at": time.Now(),
+		},
+	}
+
+	var lot entity.CreditLot
+	err := r.lots.FindOneAndUpdate(ctx, filter, update).Decode(&lot)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("erro ao revogar lote de créditos: %w", err)
+	}
+	return lot.Remaining, nil
+}
+
+func (r *CreditRepository) ExpireNext(ctx context.Context, now time.Time) (*entity.CreditLot, error) {
+	filter := bson.M{
+		"status":     entity.CreditLotStatusActive,
+		"expires_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     entity.CreditLotStatusExpired,
+			"remaining":  0,
+			"updated_at": time.Now(),
+		},
+	}
+
+	var lot entity.CreditLot
+	err := r.lots.FindOneAndUpdate(ctx, filter, update).Decode(&lot)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao expirar lote de créditos: %w", err)
+	}
+	return &lot, nil
+}
+
+func (r *CreditRepository) AddEntry(ctx context.Context, entry *entity.CreditEntry) error {
+	_, err := r.entries.InsertOne(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar lançamento de créditos: %w", err)
+	}
+	return nil
+}
+
+func (r *CreditRepository) FindEntriesByUser(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*entity.CreditEntry, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := r.entries.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar extrato de créditos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*entity.CreditEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("erro ao processar extrato de créditos: %w", err)
+	}
+
+	if entries == nil {
+		entries = []*entity.CreditEntry{}
+	}
+
+	return entries, nil
+}
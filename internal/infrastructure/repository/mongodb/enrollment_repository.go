@@ -3,11 +3,14 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type EnrollmentRepository struct {
@@ -33,13 +36,24 @@ func (r *EnrollmentRepository) FindByID(ctx context.Context, id primitive.Object
 	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&enrollment)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("inscrição não encontrada")
+			return nil, repository.ErrEnrollmentNotFound
 		}
 		return nil, fmt.Errorf("erro ao buscar inscrição: %w", err)
 	}
 	return &enrollment, nil
 }
 
+func (r *EnrollmentRepository) HasConfirmedByUser(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"user_id":     userID,
+		"enrolled_at": bson.M{"$gt": time.Time{}},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("erro ao buscar inscrições do aluno: %w", err)
+	}
+	return count > 0, nil
+}
+
 func (r *EnrollmentRepository) FindByUserAndClass(ctx context.Context, userID, classID primitive.ObjectID) (*entity.Enrollment, error) {
 	var enrollment entity.Enrollment
 	err := r.collection.FindOne(ctx, bson.M{
@@ -76,6 +90,32 @@ func (r *EnrollmentRepository) FindByUser(ctx context.Context, userID primitive.
 	return enrollments, nil
 }
 
+// FindActiveByClass busca as inscrições pendentes e confirmadas da aula.
+func (r *EnrollmentRepository) FindActiveByClass(ctx context.Context, classID primitive.ObjectID) ([]*entity.Enrollment, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"class_id": classID,
+		"status": bson.M{"$in": []string{
+			entity.EnrollmentStatusPending,
+			entity.EnrollmentStatusConfirmed,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar inscrições da aula: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var enrollments []*entity.Enrollment
+	if err = cursor.All(ctx, &enrollments); err != nil {
+		return nil, fmt.Errorf("erro ao processar inscrições: %w", err)
+	}
+
+	if enrollments == nil {
+		enrollments = []*entity.Enrollment{}
+	}
+
+	return enrollments, nil
+}
+
 func (r *EnrollmentRepository) Update(ctx context.Context, enrollment *entity.Enrollment) error {
 	update := bson.M{
 		"$set": enrollment,
@@ -87,9 +127,77 @@ func (r *EnrollmentRepository) Update(ctx context.Context, enrollment *entity.En
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("inscrição não encontrada")
+		return repository.ErrEnrollmentNotFound
 	}
 
 	return nil
 }
 
+func (r *EnrollmentRepository) FindExpiredPending(ctx context.Context, now time.Time, limit int64) ([]*entity.Enrollment, error) {
+	filter := bson.M{
+		"status":     entity.EnrollmentStatusPending,
+		"expires_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "expires_at", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar inscrições expiradas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var enrollments []*entity.Enrollment
+	if err = cursor.All(ctx, &enrollments); err != nil {
+		return nil, fmt.Errorf("erro ao processar inscrições expiradas: %w", err)
+	}
+
+	return enrollments, nil
+}
+
+// ExpireIfPending só altera a inscrição se ela ainda estiver pendente, evitando
+// sobrescrever uma confirmação recebida em paralelo pelo webhook.
+func (r *EnrollmentRepository) ExpireIfPending(ctx context.Context, id primitive.ObjectID, now time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":        id,
+		"status":     entity.EnrollmentStatusPending,
+		"expires_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     entity.EnrollmentStatusExpired,
+			"updated_at": now,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("erro ao expirar inscrição: %w", err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// ExtendHoldIfPending só prorroga reservas ainda pendentes e não vencidas, para não
+// reabrir uma inscrição que o worker de expiração já pode ter liberado.
+func (r *EnrollmentRepository) ExtendHoldIfPending(ctx context.Context, id primitive.ObjectID, until, now time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":        id,
+		"status":     entity.EnrollmentStatusPending,
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"expires_at": until,
+			"updated_at": now,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("erro ao prorrogar reserva da inscrição: %w", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,214 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MembershipPlanRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMembershipPlanRepository(db *mongo.Database) *MembershipPlanRepository {
+	return &MembershipPlanRepository{
+		collection: db.Collection("membership_plans"),
+	}
+}
+
+func (r *MembershipPlanRepository) Create(ctx context.Context, plan *entity.MembershipPlan) error {
+	_, err := r.collection.InsertOne(ctx, plan)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir plano de assinatura: %w", err)
+	}
+	return nil
+}
+
+func (r *MembershipPlanRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.MembershipPlan, error) {
+	var plan entity.MembershipPlan
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&plan)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrMembershipPlanNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar plano de assinatura: %w", err)
+	}
+	return &plan, nil
+}
+
+func (r *MembershipPlanRepository) FindAll(ctx context.Context, activeOnly bool) ([]*entity.MembershipPlan, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "price_in_cents", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar planos de assinatura: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var plans []*entity.MembershipPlan
+	if err = cursor.All(ctx, &plans); err != nil {
+		return nil, fmt.Errorf("erro ao processar planos de assinatura: %w", err)
+	}
+
+	if plans == nil {
+		plans = []*entity.MembershipPlan{}
+	}
+
+	return plans, nil
+}
+
+func (r *MembershipPlanRepository) Update(ctx context.Context, plan *entity.MembershipPlan) error {
+	update := bson.M{
+		"$set": plan,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": plan.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar plano de assinatura: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrMembershipPlanNotFound
+	}
+
+	return nil
+}
+
+type MembershipRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMembershipRepository(db *mongo.Database) *MembershipRepository {
+	return &MembershipRepository{
+		collection: db.Collection("memberships"),
+	}
+}
+
+func (r *MembershipRepository) Create(ctx context.Context, membership *entity.Membership) error {
+	_, err := r.collection.InsertOne(ctx, membership)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir assinatura: %w", err)
+	}
+	return nil
+}
+
+func (r *MembershipRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Membership, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *MembershipRepository) FindByPreapprovalID(ctx context.Context, preapprovalID string) (*entity.Membership, error) {
+	return r.findOne(ctx, bson.M{"preapproval_id": preapprovalID})
+}
+
+func (r *MembershipRepository) findOne(ctx context.Context, filter bson.M) (*entity.Membership, error) {
+	var membership entity.Membership
+	err := r.collection.FindOne(ctx, filter).Decode(&membership)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrMembershipNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar assinatura: %w", err)
+	}
+	return &membership, nil
+}
+
+func (r *MembershipRepository) FindLatestByUser(ctx context.Context, userID primitive.ObjectID) (*entity.Membership, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var membership entity.Membership
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&membership)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar assinatura: %w", err)
+	}
+	return &membership, nil
+}
+
+func (r *MembershipRepository) FindActiveByUser(ctx context.Context, userID primitive.ObjectID, now time.Time) (*entity.Membership, error) {
+	filter := bson.M{
+		"user_id":      userID,
+		"period_start": bson.M{"$lte": now},
+		"period_end":   bson.M{"$gt": now},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "period_end", Value: -1}})
+
+	var membership entity.Membership
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&membership)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar assinatura ativa: %w", err)
+	}
+	return &membership, nil
+}
+
+func (r *MembershipRepository) Update(ctx context.Context, membership *entity.Membership) error {
+	update := bson.M{
+		"$set": membership,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": membership.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar assinatura: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrMembershipNotFound
+	}
+
+	return nil
+}
+
+func (r *MembershipRepository) ConsumeClass(ctx context.Context, id primitive.ObjectID, now, classStart time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":          id,
+		"period_start": bson.M{"$lte": now},
+		"period_end":   bson.M{"$gt": classStart},
+		"$or": bson.A{
+			bson.M{"classes_per_month": 0},
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$classes_used", "$classes_per_month"}}},
+		},
+	}
+	update := bson.M{
+		"$inc": bson.M{"classes_used": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("erro ao usar aula da assinatura: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *MembershipRepository) ReleaseClass(ctx context.Context, id primitive.ObjectID, enrolledAt time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":          id,
+		"period_start": bson.M{"$lte": enrolledAt},
+		"classes_used": bson.M{"$gt": 0},
+	}
+	update := bson.M{
+		"$inc": bson.M{"classes_used": -1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("erro ao devolver aula da assinatura: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
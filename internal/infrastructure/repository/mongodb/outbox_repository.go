@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database) *OutboxRepository {
+	return &OutboxRepository{
+		collection: db.Collection("outbox"),
+	}
+}
+
+func (r *OutboxRepository) Create(ctx context.Context, message *entity.OutboxMessage) error {
+	_, err := r.collection.InsertOne(ctx, message)
+	if err != nil {
+		return fmt.Errorf("erro ao criar mensagem de outbox: %w", err)
+	}
+	return nil
+}
+
+// ClaimNext reserva atomicamente a próxima mensagem pendente, evitando que dois
+// workers processem a mesma mensagem ao mesmo tempo.
+func (r *OutboxRepository) ClaimNext(ctx context.Context, messageType string, now time.Time, lease time.Duration) (*entity.OutboxMessage, error) {
+	filter := bson.M{
+		"type":            messageType,
+		"status":          entity.OutboxStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"next_attempt_at": now.Add(lease),
+			"updated_at":      now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var message entity.OutboxMessage
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&message)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao reservar mensagem de outbox: %w", err)
+	}
+	return &message, nil
+}
+
+func (r *OutboxRepository) Update(ctx context.Context, message *entity.OutboxMessage) error {
+	update := bson.M{
+		"$set": message,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": message.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar mensagem de outbox: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("mensagem de outbox não encontrada")
+	}
+
+	return nil
+}
@@ -3,11 +3,14 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type PaymentRepository struct {
@@ -33,7 +36,7 @@ func (r *PaymentRepository) FindByID(ctx context.Context, id primitive.ObjectID)
 	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&payment)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("pagamento não encontrado")
+			return nil, repository.ErrPaymentNotFound
 		}
 		return nil, fmt.Errorf("erro ao buscar pagamento: %w", err)
 	}
@@ -52,18 +55,83 @@ func (r *PaymentRepository) FindByEnrollmentID(ctx context.Context, enrollmentID
 	return &payment, nil
 }
 
+func (r *PaymentRepository) FindByEnrollmentIDs(ctx context.Context, enrollmentIDs []primitive.ObjectID) ([]*entity.Payment, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"enrollment_id": bson.M{"$in": enrollmentIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pagamentos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var payments []*entity.Payment
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, fmt.Errorf("erro ao processar pagamentos: %w", err)
+	}
+
+	if payments == nil {
+		payments = []*entity.Payment{}
+	}
+
+	return payments, nil
+}
+
+func (r *PaymentRepository) FindByCreditPurchaseID(ctx context.Context, purchaseID primitive.ObjectID) (*entity.Payment, error) {
+	var payment entity.Payment
+	err := r.collection.FindOne(ctx, bson.M{"credit_purchase_id": purchaseID}).Decode(&payment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar pagamento: %w", err)
+	}
+	return &payment, nil
+}
+
 func (r *PaymentRepository) FindByMercadoPagoID(ctx context.Context, mpID string) (*entity.Payment, error) {
 	var payment entity.Payment
 	err := r.collection.FindOne(ctx, bson.M{"mercado_pago_id": mpID}).Decode(&payment)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("pagamento não encontrado")
+			return nil, repository.ErrPaymentNotFound
 		}
 		return nil, fmt.Errorf("erro ao buscar pagamento: %w", err)
 	}
 	return &payment, nil
 }
 
+// FindForReconciliation retorna os pagamentos criados desde since que ainda podem divergir
+// do gateway. Cobranças de assinatura e pagamentos zerados por cupom ficam de fora, pois
+// não têm checkout próprio; os menos atualizados vêm primeiro.
+func (r *PaymentRepository) FindForReconciliation(ctx context.Context, since time.Time, limit int64) ([]*entity.Payment, error) {
+	filter := bson.M{
+		"created_at":      bson.M{"$gte": since},
+		"membership_id":   bson.M{"$exists": false},
+		"amount_in_cents": bson.M{"$gt": 0},
+		"status": bson.M{"$in": []string{
+			entity.PaymentStatusPending,
+			entity.PaymentStatusInProcess,
+			entity.PaymentStatusApproved,
+			entity.PaymentStatusPartiallyRefunded,
+			entity.PaymentStatusExpired,
+		}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pagamentos para conciliação: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var payments []*entity.Payment
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, fmt.Errorf("erro ao processar pagamentos: %w", err)
+	}
+
+	return payments, nil
+}
+
 func (r *PaymentRepository) Update(ctx context.Context, payment *entity.Payment) error {
 	update := bson.M{
 		"$set": payment,
@@ -75,7 +143,7 @@ func (r *PaymentRepository) Update(ctx context.Context, payment *entity.Payment)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("pagamento não encontrado")
+		return repository.ErrPaymentNotFound
 	}
 
 	return nil
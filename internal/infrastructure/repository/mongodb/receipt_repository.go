@@ -0,0 +1,86 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const receiptCounterID = "receipts"
+
+type ReceiptRepository struct {
+	receipts *mongo.Collection
+	counters *mongo.Collection
+}
+
+func NewReceiptRepository(db *mongo.Database) *ReceiptRepository {
+	return &ReceiptRepository{
+		receipts: db.Collection("receipts"),
+		counters: db.Collection("counters"),
+	}
+}
+
+func (r *ReceiptRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.receipts.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "payment_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de recibos: %w", err)
+	}
+	return nil
+}
+
+func (r *ReceiptRepository) NextNumber(ctx context.Context) (int64, error) {
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := r.counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": receiptCounterID},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		opts,
+	).Decode(&counter)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gerar número do recibo: %w", err)
+	}
+	return counter.Seq, nil
+}
+
+func (r *ReceiptRepository) Create(ctx context.Context, receipt *entity.Receipt) error {
+	_, err := r.receipts.InsertOne(ctx, receipt)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrReceiptExists
+		}
+		return fmt.Errorf("erro ao inserir recibo: %w", err)
+	}
+	return nil
+}
+
+func (r *ReceiptRepository) FindByPaymentID(ctx context.Context, paymentID primitive.ObjectID) (*entity.Receipt, error) {
+	var receipt entity.Receipt
+	err := r.receipts.FindOne(ctx, bson.M{"payment_id": paymentID}).Decode(&receipt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrReceiptNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar recibo: %w", err)
+	}
+	return &receipt, nil
+}
@@ -0,0 +1,52 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReconciliationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReconciliationRepository(db *mongo.Database) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		collection: db.Collection("payment_reconciliations"),
+	}
+}
+
+func (r *ReconciliationRepository) Create(ctx context.Context, report *entity.ReconciliationReport) error {
+	_, err := r.collection.InsertOne(ctx, report)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir relatório de conciliação: %w", err)
+	}
+	return nil
+}
+
+func (r *ReconciliationRepository) FindRecent(ctx context.Context, limit int64) ([]*entity.ReconciliationReport, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar relatórios de conciliação: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reports []*entity.ReconciliationReport
+	if err = cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("erro ao processar relatórios de conciliação: %w", err)
+	}
+
+	if reports == nil {
+		reports = []*entity.ReconciliationReport{}
+	}
+
+	return reports, nil
+}
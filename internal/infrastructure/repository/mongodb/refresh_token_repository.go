@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRefreshTokenRepository(db *mongo.Database) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		collection: db.Collection("refresh_tokens"),
+	}
+}
+
+// EnsureIndexes garante um hash por token e remove os tokens vencidos. Tokens rotacionados
+// ficam até vencer para que a reutilização deles continue sendo detectada.
+func (r *RefreshTokenRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de refresh tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	_, err := r.collection.InsertOne(ctx, token)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrRefreshTokenNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+// MarkRotated marca o token como usado de forma atômica: entre duas requisições com o
+// mesmo token, só uma consegue rotacioná-lo.
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "rotated_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"rotated_at": at}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("erro ao rotacionar refresh token: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
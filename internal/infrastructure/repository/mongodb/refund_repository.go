@@ -0,0 +1,87 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RefundRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRefundRepository(db *mongo.Database) *RefundRepository {
+	return &RefundRepository{
+		collection: db.Collection("refunds"),
+	}
+}
+
+func (r *RefundRepository) Create(ctx context.Context, refund *entity.Refund) error {
+	_, err := r.collection.InsertOne(ctx, refund)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir estorno: %w", err)
+	}
+	return nil
+}
+
+func (r *RefundRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Refund, error) {
+	var refund entity.Refund
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&refund)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar estorno: %w", err)
+	}
+	return &refund, nil
+}
+
+func (r *RefundRepository) FindByPaymentID(ctx context.Context, paymentID primitive.ObjectID) ([]*entity.Refund, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"payment_id": paymentID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar estornos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var refunds []*entity.Refund
+	if err = cursor.All(ctx, &refunds); err != nil {
+		return nil, fmt.Errorf("erro ao processar estornos: %w", err)
+	}
+
+	return refunds, nil
+}
+
+func (r *RefundRepository) FindByMercadoPagoID(ctx context.Context, mpID string) (*entity.Refund, error) {
+	var refund entity.Refund
+	err := r.collection.FindOne(ctx, bson.M{"mercado_pago_id": mpID}).Decode(&refund)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar estorno: %w", err)
+	}
+	return &refund, nil
+}
+
+func (r *RefundRepository) Update(ctx context.Context, refund *entity.Refund) error {
+	update := bson.M{
+		"$set": refund,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": refund.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar estorno: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("estorno não encontrado")
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSessionRepository(db *mongo.Database) *SessionRepository {
+	return &SessionRepository{
+		collection: db.Collection("sessions"),
+	}
+}
+
+// EnsureIndexes cria o índice de consulta por usuário e o índice TTL que remove as
+// sessões vencidas.
+func (r *SessionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_used_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de sessões: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	_, err := r.collection.InsertOne(ctx, session)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir sessão: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Session, error) {
+	var session entity.Session
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar sessão: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) FindActiveByUser(ctx context.Context, userID primitive.ObjectID, now time.Time) ([]*entity.Session, error) {
+	filter := bson.M{
+		"user_id":    userID,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "last_used_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar sessões: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []*entity.Session
+	if err = cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("erro ao processar sessões: %w", err)
+	}
+
+	if sessions == nil {
+		sessions = []*entity.Session{}
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) Update(ctx context.Context, session *entity.Session) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": session.ID}, bson.M{"$set": session})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar sessão: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllByUser revoga todas as sessões ainda não revogadas do usuário.
+func (r *SessionRepository) RevokeAllByUser(ctx context.Context, userID primitive.ObjectID, reason string, at time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at, "revoked_reason": reason}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao revogar sessões do usuário: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// Revoke revoga a sessão uma única vez, preservando o motivo da primeira revogação.
+func (r *SessionRepository) Revoke(ctx context.Context, id primitive.ObjectID, reason string, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at, "revoked_reason": reason}},
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao revogar sessão: %w", err)
+	}
+	return nil
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository struct {
@@ -33,7 +35,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*
 	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("usuário não encontrado")
+			return nil, repository.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
 	}
@@ -45,7 +47,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity
 	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("usuário não encontrado")
+			return nil, repository.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
 	}
@@ -76,6 +78,7 @@ func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 		"$set": bson.M{
 			"name":          user.Name,
 			"email":         user.Email,
+			"cpf":           user.CPF,
 			"password_hash": user.PasswordHash,
 			"role":          user.Role,
 			"updated_at":    user.UpdatedAt,
@@ -88,7 +91,7 @@ func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("usuário não encontrado")
+		return repository.ErrUserNotFound
 	}
 
 	return nil
@@ -101,7 +104,38 @@ func (r *UserRepository) Delete(ctx context.Context, id primitive.ObjectID) erro
 	}
 
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("usuário não encontrado")
+		return repository.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// FindTokenVersion lê apenas a versão de tokens do usuário; é consultada a cada requisição
+// autenticada.
+func (r *UserRepository) FindTokenVersion(ctx context.Context, id primitive.ObjectID) (int, error) {
+	var result struct {
+		TokenVersion int `bson:"token_version"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"token_version": 1})
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, repository.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("erro ao buscar versão de tokens do usuário: %w", err)
+	}
+	return result.TokenVersion, nil
+}
+
+// IncrementTokenVersion invalida de uma vez todos os access tokens já emitidos para o usuário.
+func (r *UserRepository) IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"token_version": 1}})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar versão de tokens do usuário: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrUserNotFound
 	}
 
 	return nil
@@ -0,0 +1,148 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WaitlistRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWaitlistRepository(db *mongo.Database) *WaitlistRepository {
+	return &WaitlistRepository{
+		collection: db.Collection("waitlist"),
+	}
+}
+
+// EnsureIndexes garante no máximo uma entrada ativa (aguardando ou com vaga oferecida) por
+// aluno em cada aula, mesmo com pedidos simultâneos para entrar na fila.
+func (r *WaitlistRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "class_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().
+				SetName("active_entry_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": bson.M{"$in": []string{entity.WaitlistStatusWaiting, entity.WaitlistStatusOffered}}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices da lista de espera: %w", err)
+	}
+	return nil
+}
+
+func (r *WaitlistRepository) Create(ctx context.Context, entry *entity.WaitlistEntry) error {
+	_, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrWaitlistEntryExists
+		}
+		return fmt.Errorf("erro ao entrar na lista de espera: %w", err)
+	}
+	return nil
+}
+
+func (r *WaitlistRepository) FindActiveByUserAndClass(ctx context.Context, userID, classID primitive.ObjectID) (*entity.WaitlistEntry, error) {
+	return r.findOne(ctx, bson.M{
+		"user_id":  userID,
+		"class_id": classID,
+		"status":   bson.M{"$in": []string{entity.WaitlistStatusWaiting, entity.WaitlistStatusOffered}},
+	})
+}
+
+func (r *WaitlistRepository) FindByEnrollmentID(ctx context.Context, enrollmentID primitive.ObjectID) (*entity.WaitlistEntry, error) {
+	return r.findOne(ctx, bson.M{"enrollment_id": enrollmentID})
+}
+
+func (r *WaitlistRepository) FindNextWaiting(ctx context.Context, classID primitive.ObjectID) (*entity.WaitlistEntry, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	return r.findOne(ctx, bson.M{
+		"class_id": classID,
+		"status":   entity.WaitlistStatusWaiting,
+	}, opts)
+}
+
+func (r *WaitlistRepository) CountWaitingBefore(ctx context.Context, entry *entity.WaitlistEntry) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"class_id":   entry.ClassID,
+		"status":     entity.WaitlistStatusWaiting,
+		"created_at": bson.M{"$lt": entry.CreatedAt},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao calcular posição na lista de espera: %w", err)
+	}
+	return count, nil
+}
+
+func (r *WaitlistRepository) CountWaiting(ctx context.Context, classID primitive.ObjectID) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"class_id": classID,
+		"status":   entity.WaitlistStatusWaiting,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar lista de espera: %w", err)
+	}
+	return count, nil
+}
+
+// CancelByClass encerra todas as entradas em espera ou com oferta aberta da aula.
+func (r *WaitlistRepository) CancelByClass(ctx context.Context, classID primitive.ObjectID) (int64, error) {
+	filter := bson.M{
+		"class_id": classID,
+		"status": bson.M{"$in": []string{
+			entity.WaitlistStatusWaiting,
+			entity.WaitlistStatusOffered,
+		}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     entity.WaitlistStatusCancelled,
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao cancelar lista de espera: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *WaitlistRepository) Update(ctx context.Context, entry *entity.WaitlistEntry) error {
+	update := bson.M{
+		"$set": entry,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": entry.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar lista de espera: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("registro da lista de espera não encontrado")
+	}
+
+	return nil
+}
+
+func (r *WaitlistRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	err := r.collection.FindOne(ctx, filter, opts...).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar lista de espera: %w", err)
+	}
+	return &entry, nil
+}
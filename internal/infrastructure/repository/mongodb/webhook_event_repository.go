@@ -0,0 +1,170 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookEventRepository(db *mongo.Database) *WebhookEventRepository {
+	return &WebhookEventRepository{
+		collection: db.Collection("webhook_events"),
+	}
+}
+
+func (r *WebhookEventRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "dedup_key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "received_at", Value: -1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de eventos de webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookEventRepository) Create(ctx context.Context, event *entity.WebhookEvent) error {
+	_, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrWebhookEventExists
+		}
+		return fmt.Errorf("erro ao inserir evento de webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookEventRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.WebhookEvent, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *WebhookEventRepository) FindByDedupKey(ctx context.Context, dedupKey string) (*entity.WebhookEvent, error) {
+	return r.findOne(ctx, bson.M{"dedup_key": dedupKey})
+}
+
+func (r *WebhookEventRepository) findOne(ctx context.Context, filter bson.M) (*entity.WebhookEvent, error) {
+	var event entity.WebhookEvent
+	err := r.collection.FindOne(ctx, filter).Decode(&event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repository.ErrWebhookEventNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar evento de webhook: %w", err)
+	}
+	return &event, nil
+}
+
+// FindRecent lista os eventos mais recentes, opcionalmente filtrados pelo status.
+func (r *WebhookEventRepository) FindRecent(ctx context.Context, status string, limit int64) ([]*entity.WebhookEvent, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "received_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar eventos de webhook: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*entity.WebhookEvent
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("erro ao processar eventos de webhook: %w", err)
+	}
+
+	if events == nil {
+		events = []*entity.WebhookEvent{}
+	}
+
+	return events, nil
+}
+
+// ClaimNext reserva atomicamente o próximo evento pendente cuja tentativa já venceu,
+// evitando que dois workers processem o mesmo evento ao mesmo tempo.
+func (r *WebhookEventRepository) ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*entity.WebhookEvent, error) {
+	filter := bson.M{
+		"status":          entity.WebhookEventStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+		"$or":             unlockedFilter(now),
+	}
+
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}})
+	return r.claim(ctx, filter, now, lease, opts)
+}
+
+// Claim reserva um evento específico em qualquer status, desde que nenhuma tentativa
+// esteja em andamento. Devolve nil quando o evento está reservado.
+func (r *WebhookEventRepository) Claim(ctx context.Context, id primitive.ObjectID, now time.Time, lease time.Duration) (*entity.WebhookEvent, error) {
+	filter := bson.M{
+		"_id": id,
+		"$or": unlockedFilter(now),
+	}
+	return r.claim(ctx, filter, now, lease, options.FindOneAndUpdate())
+}
+
+func (r *WebhookEventRepository) claim(ctx context.Context, filter bson.M, now time.Time, lease time.Duration, opts *options.FindOneAndUpdateOptions) (*entity.WebhookEvent, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"locked_until": now.Add(lease),
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	var event entity.WebhookEvent
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts.SetReturnDocument(options.After)).Decode(&event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao reservar evento de webhook: %w", err)
+	}
+	return &event, nil
+}
+
+func unlockedFilter(now time.Time) bson.A {
+	return bson.A{
+		bson.M{"locked_until": nil},
+		bson.M{"locked_until": bson.M{"$lte": now}},
+	}
+}
+
+func (r *WebhookEventRepository) Update(ctx context.Context, event *entity.WebhookEvent) error {
+	update := bson.M{
+		"$set": event,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": event.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar evento de webhook: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository.ErrWebhookEventNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Job func(ctx context.Context) error
+
+// Worker executa um job periodicamente até o contexto ser cancelado.
+type Worker struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+func New(name string, interval time.Duration, job Job) *Worker {
+	return &Worker{
+		name:     name,
+		interval: interval,
+		job:      job,
+	}
+}
+
+func (w *Worker) Name() string {
+	return w.name
+}
+
+func (w *Worker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		logger.Info("Worker iniciado",
+			zap.String("worker", w.name),
+			zap.Duration("interval", w.interval),
+		)
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info("Worker finalizado", zap.String("worker", w.name))
+				return
+			case <-ticker.C:
+				w.run(ctx)
+			}
+		}
+	}()
+}
+
+func (w *Worker) run(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Panic no worker", zap.String("worker", w.name), zap.Any("panic", r))
+		}
+	}()
+
+	if err := w.job(ctx); err != nil {
+		logger.Error("Erro ao executar worker", zap.String("worker", w.name), zap.Error(err))
+	}
+}
@@ -1,23 +1,44 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/auth"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.uber.org/zap"
 )
 
 type AuthHandler struct {
-	loginUseCase    *auth.LoginUseCase
-	registerUseCase *auth.RegisterUseCase
+	loginUseCase         *auth.LoginUseCase
+	registerUseCase      *auth.RegisterUseCase
+	refreshTokenUseCase  *auth.RefreshTokenUseCase
+	logoutUseCase        *auth.LogoutUseCase
+	listSessionsUseCase  *auth.ListSessionsUseCase
+	revokeSessionUseCase *auth.RevokeSessionUseCase
 }
 
-func NewAuthHandler(loginUseCase *auth.LoginUseCase, registerUseCase *auth.RegisterUseCase) *AuthHandler {
+func NewAuthHandler(
+	loginUseCase *auth.LoginUseCase,
+	registerUseCase *auth.RegisterUseCase,
+	refreshTokenUseCase *auth.RefreshTokenUseCase,
+	logoutUseCase *auth.LogoutUseCase,
+	listSessionsUseCase *auth.ListSessionsUseCase,
+	revokeSessionUseCase *auth.RevokeSessionUseCase,
+) *AuthHandler {
 	return &AuthHandler{
-		loginUseCase:    loginUseCase,
-		registerUseCase: registerUseCase,
+		loginUseCase:         loginUseCase,
+		registerUseCase:      registerUseCase,
+		refreshTokenUseCase:  refreshTokenUseCase,
+		logoutUseCase:        logoutUseCase,
+		listSessionsUseCase:  listSessionsUseCase,
+		revokeSessionUseCase: revokeSessionUseCase,
 	}
 }
 
@@ -29,6 +50,9 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	input.UserAgent = r.UserAgent()
+	input.IP = r.RemoteAddr
+
 	output, err := h.loginUseCase.Execute(r.Context(), input)
 	if err != nil {
 		logger.Error("Erro no login", zap.Error(err))
@@ -48,6 +72,9 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	input.UserAgent = r.UserAgent()
+	input.IP = r.RemoteAddr
+
 	output, err := h.registerUseCase.Execute(r.Context(), input)
 	if err != nil {
 		logger.Error("Erro no registro", zap.Error(err))
@@ -60,3 +87,98 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(output)
 }
 
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var input auth.RefreshTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar request", zap.Error(err))
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+	input.UserAgent = r.UserAgent()
+	input.IP = r.RemoteAddr
+
+	output, err := h.refreshTokenUseCase.Execute(r.Context(), input)
+	if err != nil {
+		logger.Warn("Erro ao renovar token", zap.Error(err))
+		http.Error(w, err.Error(), authErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var input auth.LogoutInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar request", zap.Error(err))
+		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.logoutUseCase.Execute(r.Context(), input); err != nil {
+		logger.Error("Erro no logout", zap.Error(err))
+		http.Error(w, err.Error(), authErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := h.listSessionsUseCase.Execute(r.Context(), auth.ListSessionsInput{
+		UserID:           claims.UserID,
+		CurrentSessionID: claims.SessionID,
+	})
+	if err != nil {
+		logger.Error("Erro ao listar sessões", zap.Error(err))
+		http.Error(w, err.Error(), authErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.revokeSessionUseCase.Execute(r.Context(), auth.RevokeSessionInput{
+		UserID:    claims.UserID,
+		SessionID: chi.URLParam(r, "id"),
+	})
+	if err != nil {
+		logger.Error("Erro ao revogar sessão", zap.Error(err))
+		http.Error(w, err.Error(), authErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func authErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, auth.ErrInvalidSessionID),
+		errors.Is(err, auth.ErrInvalidUserID):
+		return http.StatusBadRequest
+	case errors.Is(err, auth.ErrInvalidRefreshToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, repository.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
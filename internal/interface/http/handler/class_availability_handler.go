@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/event"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/class"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type ClassAvailabilityHandler struct {
+	watchAvailability *class.WatchClassAvailabilityUseCase
+	config            *config.Config
+}
+
+func NewClassAvailabilityHandler(
+	watchAvailability *class.WatchClassAvailabilityUseCase,
+	config *config.Config,
+) *ClassAvailabilityHandler {
+	return &ClassAvailabilityHandler{
+		watchAvailability: watchAvailability,
+		config:            config,
+	}
+}
+
+// Stream envia a ocupação da aula via Server-Sent Events. Clientes que reconectam com
+// Last-Event-ID só recebem o estado atual se algo mudou desde o último evento.
+func (h *ClassAvailabilityHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	input := class.WatchClassAvailabilityInput{ClassID: chi.URLParam(r, "id")}
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		id, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			http.Error(w, "Last-Event-ID inválido", http.StatusBadRequest)
+			return
+		}
+		input.LastEventID = id
+	}
+
+	subscription, err := h.watchAvailability.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao acompanhar vagas da aula", zap.Error(err))
+		http.Error(w, err.Error(), classErrorStatus(err))
+		return
+	}
+	defer subscription.Close()
+
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", 3000)
+	if subscription.Initial != nil {
+		if err := writeAvailabilityEvent(w, *subscription.Initial); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Warn("Streaming não suportado pela conexão", zap.Error(err))
+		return
+	}
+
+	heartbeat := time.NewTicker(h.config.Class.AvailabilityHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case availability := <-subscription.Events:
+			if err := writeAvailabilityEvent(w, availability); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeAvailabilityEvent(w http.ResponseWriter, availability event.ClassAvailability) error {
+	data, err := json.Marshal(availability)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: availability\ndata: %s\n\n", availability.ID, data)
+	return err
+}
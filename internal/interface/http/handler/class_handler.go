@@ -1,26 +1,50 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/class"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.uber.org/zap"
 )
 
 type ClassHandler struct {
-	createClass *class.CreateClassUseCase
-	listClasses *class.ListClassesUseCase
+	createClass  *class.CreateClassUseCase
+	listClasses  *class.ListClassesUseCase
+	getClass     *class.GetClassUseCase
+	updateClass  *class.UpdateClassUseCase
+	publishClass *class.PublishClassUseCase
+	cancelClass  *class.CancelClassUseCase
 }
 
 func NewClassHandler(
 	createClass *class.CreateClassUseCase,
 	listClasses *class.ListClassesUseCase,
+	getClass *class.GetClassUseCase,
+	updateClass *class.UpdateClassUseCase,
+	publishClass *class.PublishClassUseCase,
+	cancelClass *class.CancelClassUseCase,
 ) *ClassHandler {
 	return &ClassHandler{
-		createClass: createClass,
-		listClasses: listClasses,
+		createClass:  createClass,
+		listClasses:  listClasses,
+		getClass:     getClass,
+		updateClass:  updateClass,
+		publishClass: publishClass,
+		cancelClass:  cancelClass,
 	}
 }
 
@@ -35,7 +59,7 @@ func (h *ClassHandler) Create(w http.ResponseWriter, r *http.Request) {
 	class, err := h.createClass.Execute(r.Context(), input)
 	if err != nil {
 		logger.Error("Erro ao criar aula", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), classErrorStatus(err))
 		return
 	}
 
@@ -45,14 +69,201 @@ func (h *ClassHandler) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ClassHandler) List(w http.ResponseWriter, r *http.Request) {
-	classes, err := h.listClasses.Execute(r.Context())
+	input, err := listClassesInput(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.listClasses.Execute(r.Context(), input)
 	if err != nil {
 		logger.Error("Erro ao listar aulas", zap.Error(err))
+		http.Error(w, err.Error(), classErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Get retorna o detalhe da aula com ETag: clientes que enviam If-None-Match com a
+// versão atual recebem 304 sem corpo.
+func (h *ClassHandler) Get(w http.ResponseWriter, r *http.Request) {
+	input := class.GetClassInput{ClassID: chi.URLParam(r, "id")}
+	if claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims); ok {
+		input.UserID = claims.UserID
+		input.Role = claims.Role
+	}
+
+	result, err := h.getClass.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao buscar aula", zap.Error(err))
+		http.Error(w, err.Error(), classErrorStatus(err))
+		return
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		logger.Error("Erro ao serializar aula", zap.Error(err))
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("Vary", "Authorization")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func listClassesInput(r *http.Request) (class.ListClassesInput, error) {
+	query := r.URL.Query()
+	input := class.ListClassesInput{
+		InstructorID: query.Get("instructor_id"),
+		Search:       query.Get("q"),
+		Sort:         query.Get("sort"),
+		Cursor:       query.Get("cursor"),
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &input.From}, {"to", &input.To}} {
+		if value := query.Get(param.name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return input, fmt.Errorf("parâmetro %s inválido: use o formato RFC3339", param.name)
+			}
+			*param.target = &t
+		}
+	}
+
+	if value := query.Get("status"); value != "" {
+		input.Statuses = strings.Split(value, ",")
+	}
+
+	if value := query.Get("has_spots"); value != "" {
+		hasSpots, err := strconv.ParseBool(value)
+		if err != nil {
+			return input, fmt.Errorf("parâmetro has_spots inválido")
+		}
+		input.HasSpots = hasSpots
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return input, fmt.Errorf("parâmetro limit inválido")
+		}
+		input.Limit = limit
+	}
+
+	if claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims); ok {
+		input.IncludeDrafts = claims.Role == entity.RoleAdmin || claims.Role == entity.RoleInstructor
+	}
+
+	return input, nil
+}
+
+func (h *ClassHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input class.UpdateClassInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.ClassID = chi.URLParam(r, "id")
+
+	class, err := h.updateClass.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao atualizar aula", zap.Error(err))
+		http.Error(w, err.Error(), classErrorStatus(err))
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(classes)
+	json.NewEncoder(w).Encode(class)
 }
 
+func (h *ClassHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	class, err := h.publishClass.Execute(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		logger.Error("Erro ao publicar aula", zap.Error(err))
+		http.Error(w, err.Error(), classErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(class)
+}
+
+func (h *ClassHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	var input class.CancelClassInput
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			logger.Error("Erro ao decodificar requisição", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	input.ClassID = chi.URLParam(r, "id")
+
+	result, err := h.cancelClass.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao cancelar aula", zap.Error(err))
+		http.Error(w, err.Error(), classErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func classErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, class.ErrInvalidClassID),
+		errors.Is(err, class.ErrInvalidSeriesID),
+		errors.Is(err, class.ErrInvalidScope),
+		errors.Is(err, class.ErrInvalidSchedule),
+		errors.Is(err, class.ErrScheduleChangesDay),
+		errors.Is(err, class.ErrClassInPast),
+		errors.Is(err, class.ErrInvalidInstructorID),
+		errors.Is(err, class.ErrInvalidStatus),
+		errors.Is(err, class.ErrInvalidSort),
+		errors.Is(err, class.ErrInvalidCursor),
+		errors.Is(err, class.ErrInvalidDateRange),
+		errors.Is(err, entity.ErrInvalidClassCapacity),
+		errors.Is(err, entity.ErrInvalidClassPrice):
+		return http.StatusBadRequest
+	case errors.Is(err, class.ErrOccurrenceNotFound),
+		errors.Is(err, repository.ErrClassSeriesNotFound),
+		errors.Is(err, repository.ErrClassNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, class.ErrClassNotEditable),
+		errors.Is(err, entity.ErrInvalidClassTransition),
+		errors.Is(err, repository.ErrCapacityBelow),
+		errors.Is(err, repository.ErrVersionConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/class"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type ClassSeriesHandler struct {
+	createSeries     *class.CreateClassSeriesUseCase
+	updateOccurrence *class.UpdateSeriesOccurrenceUseCase
+	cancelOccurrence *class.CancelSeriesOccurrenceUseCase
+}
+
+func NewClassSeriesHandler(
+	createSeries *class.CreateClassSeriesUseCase,
+	updateOccurrence *class.UpdateSeriesOccurrenceUseCase,
+	cancelOccurrence *class.CancelSeriesOccurrenceUseCase,
+) *ClassSeriesHandler {
+	return &ClassSeriesHandler{
+		createSeries:     createSeries,
+		updateOccurrence: updateOccurrence,
+		cancelOccurrence: cancelOccurrence,
+	}
+}
+
+func (h *ClassSeriesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input class.CreateClassSeriesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.createSeries.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao criar série de aulas", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *ClassSeriesHandler) UpdateOccurrence(w http.ResponseWriter, r *http.Request) {
+	var input class.UpdateSeriesOccurrenceInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.SeriesID = chi.URLParam(r, "id")
+
+	result, err := h.updateOccurrence.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao atualizar ocorrência da série", zap.Error(err))
+		http.Error(w, err.Error(), classErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *ClassSeriesHandler) CancelOccurrence(w http.ResponseWriter, r *http.Request) {
+	var input class.CancelSeriesOccurrenceInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.SeriesID = chi.URLParam(r, "id")
+
+	series, err := h.cancelOccurrence.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao cancelar ocorrência da série", zap.Error(err))
+		http.Error(w, err.Error(), classErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/coupon"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type CouponHandler struct {
+	createCoupon *coupon.CreateCouponUseCase
+	updateCoupon *coupon.UpdateCouponUseCase
+	listCoupons  *coupon.ListCouponsUseCase
+}
+
+func NewCouponHandler(
+	createCoupon *coupon.CreateCouponUseCase,
+	updateCoupon *coupon.UpdateCouponUseCase,
+	listCoupons *coupon.ListCouponsUseCase,
+) *CouponHandler {
+	return &CouponHandler{
+		createCoupon: createCoupon,
+		updateCoupon: updateCoupon,
+		listCoupons:  listCoupons,
+	}
+}
+
+func (h *CouponHandler) List(w http.ResponseWriter, r *http.Request) {
+	coupons, err := h.listCoupons.Execute(r.Context())
+	if err != nil {
+		logger.Error("Erro ao listar cupons", zap.Error(err))
+		http.Error(w, err.Error(), couponErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(coupons)
+}
+
+func (h *CouponHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input coupon.CreateCouponInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.createCoupon.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao criar cupom", zap.Error(err))
+		http.Error(w, err.Error(), couponErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *CouponHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input coupon.UpdateCouponInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.CouponID = chi.URLParam(r, "id")
+
+	result, err := h.updateCoupon.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao atualizar cupom", zap.Error(err))
+		http.Error(w, err.Error(), couponErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func couponErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, coupon.ErrInvalidCouponID),
+		errors.Is(err, coupon.ErrInvalidCoupon),
+		errors.Is(err, coupon.ErrInvalidRestriction):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrCouponNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrCouponCodeTaken):
+		return http.StatusConflict
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
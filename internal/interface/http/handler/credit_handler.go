@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/credit"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type CreditHandler struct {
+	createPack   *credit.CreateCreditPackUseCase
+	updatePack   *credit.UpdateCreditPackUseCase
+	listPacks    *credit.ListCreditPacksUseCase
+	purchasePack *credit.PurchaseCreditPackUseCase
+	getMine      *credit.GetMyCreditsUseCase
+}
+
+func NewCreditHandler(
+	createPack *credit.CreateCreditPackUseCase,
+	updatePack *credit.UpdateCreditPackUseCase,
+	listPacks *credit.ListCreditPacksUseCase,
+	purchasePack *credit.PurchaseCreditPackUseCase,
+	getMine *credit.GetMyCreditsUseCase,
+) *CreditHandler {
+	return &CreditHandler{
+		createPack:   createPack,
+		updatePack:   updatePack,
+		listPacks:    listPacks,
+		purchasePack: purchasePack,
+		getMine:      getMine,
+	}
+}
+
+func (h *CreditHandler) ListPacks(w http.ResponseWriter, r *http.Request) {
+	claims, _ := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	staff := claims != nil && (claims.Role == entity.RoleAdmin || claims.Role == entity.RoleInstructor)
+
+	packs, err := h.listPacks.Execute(r.Context(), staff)
+	if err != nil {
+		logger.Error("Erro ao listar pacotes de créditos", zap.Error(err))
+		http.Error(w, err.Error(), creditErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(packs)
+}
+
+func (h *CreditHandler) CreatePack(w http.ResponseWriter, r *http.Request) {
+	var input credit.CreateCreditPackInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pack, err := h.createPack.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao criar pacote de créditos", zap.Error(err))
+		http.Error(w, err.Error(), creditErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pack)
+}
+
+func (h *CreditHandler) UpdatePack(w http.ResponseWriter, r *http.Request) {
+	var input credit.UpdateCreditPackInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.PackID = chi.URLParam(r, "id")
+
+	pack, err := h.updatePack.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao atualizar pacote de créditos", zap.Error(err))
+		http.Error(w, err.Error(), creditErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pack)
+}
+
+func (h *CreditHandler) Purchase(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.purchasePack.Execute(r.Context(), credit.PurchaseCreditPackInput{
+		PackID: chi.URLParam(r, "id"),
+		UserID: claims.UserID,
+	})
+	if err != nil {
+		logger.Error("Erro ao comprar pacote de créditos", zap.Error(err))
+		http.Error(w, err.Error(), creditErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *CreditHandler) Mine(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.getMine.Execute(r.Context(), claims.UserID)
+	if err != nil {
+		logger.Error("Erro ao consultar créditos", zap.Error(err))
+		http.Error(w, err.Error(), creditErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func creditErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, credit.ErrInvalidPackID),
+		errors.Is(err, credit.ErrInvalidUserID),
+		errors.Is(err, credit.ErrInvalidPack):
+		return http.StatusBadRequest
+	case errors.Is(err, credit.ErrNotStudent):
+		return http.StatusForbidden
+	case errors.Is(err, credit.ErrUserNotFound),
+		errors.Is(err, repository.ErrCreditPackNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, credit.ErrPackInactive):
+		return http.StatusConflict
+	case errors.Is(err, credit.ErrCheckoutFailed):
+		return http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	paymentInfra "github.com/marcelobritu/isayoga-api/internal/infrastructure/payment"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/payment"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DevPaymentHandler simula o checkout do gateway fake. As rotas só são registradas
+// quando PAYMENT_PROVIDER=fake, que a configuração só aceita em desenvolvimento.
+type DevPaymentHandler struct {
+	fakeGateway    *paymentInfra.FakeGateway
+	processWebhook *payment.ProcessWebhookUseCase
+	config         *config.Config
+}
+
+func NewDevPaymentHandler(
+	fakeGateway *paymentInfra.FakeGateway,
+	processWebhook *payment.ProcessWebhookUseCase,
+	config *config.Config,
+) *DevPaymentHandler {
+	return &DevPaymentHandler{
+		fakeGateway:    fakeGateway,
+		processWebhook: processWebhook,
+		config:         config,
+	}
+}
+
+func (h *DevPaymentHandler) Enabled() bool {
+	return h.config.Payment.Provider == "fake" && h.config.Server.Env == "development"
+}
+
+func (h *DevPaymentHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	externalRef := chi.URLParam(r, "ref")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"external_reference": externalRef,
+		"approve":            "POST /dev/payments/" + externalRef + "/approve",
+		"reject":             "POST /dev/payments/" + externalRef + "/reject",
+	})
+}
+
+func (h *DevPaymentHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.simulate(w, r, entity.PaymentStatusApproved)
+}
+
+func (h *DevPaymentHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.simulate(w, r, entity.PaymentStatusRejected)
+}
+
+func (h *DevPaymentHandler) simulate(w http.ResponseWriter, r *http.Request, status string) {
+	externalRef := chi.URLParam(r, "ref")
+
+	paymentID, err := h.fakeGateway.Simulate(externalRef, status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	input := payment.WebhookInput{
+		Action: "payment.updated",
+		Type:   "payment",
+		Data:   payment.WebhookData{ID: paymentID},
+	}
+
+	if err := h.processWebhook.Execute(r.Context(), input); err != nil {
+		logger.Error("Erro ao processar pagamento simulado", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("Pagamento simulado",
+		zap.String("external_reference", externalRef),
+		zap.String("status", status),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"payment_id": paymentID,
+		"status":     status,
+	})
+}
+
+func (h *DevPaymentHandler) SubscriptionCheckout(w http.ResponseWriter, r *http.Request) {
+	externalRef := chi.URLParam(r, "ref")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"external_reference": externalRef,
+		"charge":             "POST /dev/subscriptions/" + externalRef + "/charge",
+	})
+}
+
+// ChargeSubscription autoriza a assinatura fake e gera uma cobrança mensal aprovada,
+// processando os dois webhooks que o gateway real enviaria.
+func (h *DevPaymentHandler) ChargeSubscription(w http.ResponseWriter, r *http.Request) {
+	externalRef := chi.URLParam(r, "ref")
+
+	subscriptionID, chargeID, err := h.fakeGateway.SimulateSubscriptionCharge(externalRef)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	inputs := []payment.WebhookInput{
+		{
+			Action: "updated",
+			Type:   "subscription_preapproval",
+			Data:   payment.WebhookData{ID: subscriptionID},
+		},
+		{
+			Action: "created",
+			Type:   "subscription_authorized_payment",
+			Data:   payment.WebhookData{ID: chargeID},
+		},
+	}
+
+	for _, input := range inputs {
+		if err := h.processWebhook.Execute(r.Context(), input); err != nil {
+			logger.Error("Erro ao processar cobrança de assinatura simulada", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	logger.Info("Cobrança de assinatura simulada",
+		zap.String("external_reference", externalRef),
+		zap.String("authorized_payment_id", chargeID),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"preapproval_id":        subscriptionID,
+		"authorized_payment_id": chargeID,
+	})
+}
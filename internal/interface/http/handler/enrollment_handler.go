@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/enrollment"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -13,19 +19,42 @@ import (
 type EnrollmentHandler struct {
 	enrollStudent    *enrollment.EnrollStudentUseCase
 	cancelEnrollment *enrollment.CancelEnrollmentUseCase
+	getEnrollment    *enrollment.GetEnrollmentUseCase
+	listMine         *enrollment.ListMyEnrollmentsUseCase
 }
 
 func NewEnrollmentHandler(
 	enrollStudent *enrollment.EnrollStudentUseCase,
 	cancelEnrollment *enrollment.CancelEnrollmentUseCase,
+	getEnrollment *enrollment.GetEnrollmentUseCase,
+	listMine *enrollment.ListMyEnrollmentsUseCase,
 ) *EnrollmentHandler {
 	return &EnrollmentHandler{
 		enrollStudent:    enrollStudent,
 		cancelEnrollment: cancelEnrollment,
+		getEnrollment:    getEnrollment,
+		listMine:         listMine,
 	}
 }
 
+// Enroll inscreve o próprio usuário autenticado; user_id do corpo é ignorado.
 func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	h.enroll(w, r, false)
+}
+
+// EnrollOnBehalf permite que administradores e instrutores inscrevam o aluno informado em
+// user_id. A inscrição registra quem a realizou.
+func (h *EnrollmentHandler) EnrollOnBehalf(w http.ResponseWriter, r *http.Request) {
+	h.enroll(w, r, true)
+}
+
+func (h *EnrollmentHandler) enroll(w http.ResponseWriter, r *http.Request, onBehalf bool) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
 	var input enrollment.EnrollStudentInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		logger.Error("Erro ao decodificar requisição", zap.Error(err))
@@ -33,10 +62,22 @@ func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	input.ActorID = claims.UserID
+	input.ActorRole = claims.Role
+	if !onBehalf {
+		input.UserID = claims.UserID
+	}
+
 	result, err := h.enrollStudent.Execute(r.Context(), input)
 	if err != nil {
-		logger.Error("Erro ao realizar inscrição", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := enrollErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			logger.Error("Erro ao realizar inscrição", zap.Error(err))
+			http.Error(w, "Erro ao realizar inscrição", status)
+			return
+		}
+		logger.Warn("Inscrição recusada", zap.Error(err))
+		http.Error(w, err.Error(), status)
 		return
 	}
 
@@ -45,14 +86,107 @@ func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
+func (h *EnrollmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.getEnrollment.Execute(r.Context(), enrollment.GetEnrollmentInput{
+		EnrollmentID: chi.URLParam(r, "id"),
+		UserID:       claims.UserID,
+		Role:         claims.Role,
+	})
+	if err != nil {
+		logger.Error("Erro ao buscar inscrição", zap.Error(err))
+		http.Error(w, err.Error(), enrollErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *EnrollmentHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.listMine.Execute(r.Context(), enrollment.ListMyEnrollmentsInput{
+		UserID: claims.UserID,
+		Filter: r.URL.Query().Get("filter"),
+	})
+	if err != nil {
+		logger.Error("Erro ao listar inscrições do usuário", zap.Error(err))
+		http.Error(w, err.Error(), enrollErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
 func (h *EnrollmentHandler) Cancel(w http.ResponseWriter, r *http.Request) {
-	enrollmentID := chi.URLParam(r, "id")
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
 
-	if err := h.cancelEnrollment.Execute(r.Context(), enrollmentID); err != nil {
+	result, err := h.cancelEnrollment.Execute(r.Context(), enrollment.CancelEnrollmentInput{
+		EnrollmentID: chi.URLParam(r, "id"),
+		UserID:       claims.UserID,
+		Role:         claims.Role,
+	})
+	if err != nil {
 		logger.Error("Erro ao cancelar inscrição", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), enrollErrorStatus(err))
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func enrollErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, enrollment.ErrInvalidUserID),
+		errors.Is(err, enrollment.ErrInvalidClassID),
+		errors.Is(err, enrollment.ErrInvalidFilter),
+		errors.Is(err, enrollment.ErrInvalidEnrollmentID),
+		errors.Is(err, enrollment.ErrCouponWithCredit),
+		errors.Is(err, enrollment.ErrInvalidPaymentMethod):
+		return http.StatusBadRequest
+	case errors.Is(err, enrollment.ErrNotStudent),
+		errors.Is(err, enrollment.ErrNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, enrollment.ErrUserNotFound),
+		errors.Is(err, repository.ErrClassNotFound),
+		errors.Is(err, repository.ErrEnrollmentNotFound),
+		errors.Is(err, repository.ErrCouponNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, enrollment.ErrAlreadyEnrolled),
+		errors.Is(err, enrollment.ErrClassNotOpen),
+		errors.Is(err, enrollment.ErrNotCancellable),
+		errors.Is(err, entity.ErrCancellationClosed),
+		errors.Is(err, repository.ErrClassFull),
+		errors.Is(err, enrollment.ErrEnrollmentContended):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrNoCredits):
+		return http.StatusPaymentRequired
+	case errors.Is(err, entity.ErrCouponInactive),
+		errors.Is(err, entity.ErrCouponNotApplicable),
+		errors.Is(err, entity.ErrCouponFirstClass),
+		errors.Is(err, repository.ErrCouponExhausted),
+		errors.Is(err, repository.ErrCouponUserLimit):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/membership"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type MembershipHandler struct {
+	createPlan *membership.CreateMembershipPlanUseCase
+	updatePlan *membership.UpdateMembershipPlanUseCase
+	listPlans  *membership.ListMembershipPlansUseCase
+	subscribe  *membership.SubscribeUseCase
+	getMine    *membership.GetMyMembershipUseCase
+	cancel     *membership.CancelMembershipUseCase
+}
+
+func NewMembershipHandler(
+	createPlan *membership.CreateMembershipPlanUseCase,
+	updatePlan *membership.UpdateMembershipPlanUseCase,
+	listPlans *membership.ListMembershipPlansUseCase,
+	subscribe *membership.SubscribeUseCase,
+	getMine *membership.GetMyMembershipUseCase,
+	cancel *membership.CancelMembershipUseCase,
+) *MembershipHandler {
+	return &MembershipHandler{
+		createPlan: createPlan,
+		updatePlan: updatePlan,
+		listPlans:  listPlans,
+		subscribe:  subscribe,
+		getMine:    getMine,
+		cancel:     cancel,
+	}
+}
+
+func (h *MembershipHandler) ListPlans(w http.ResponseWriter, r *http.Request) {
+	claims, _ := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	staff := claims != nil && (claims.Role == entity.RoleAdmin || claims.Role == entity.RoleInstructor)
+
+	plans, err := h.listPlans.Execute(r.Context(), staff)
+	if err != nil {
+		logger.Error("Erro ao listar planos de assinatura", zap.Error(err))
+		http.Error(w, err.Error(), membershipErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plans)
+}
+
+func (h *MembershipHandler) CreatePlan(w http.ResponseWriter, r *http.Request) {
+	var input membership.CreateMembershipPlanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.createPlan.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao criar plano de assinatura", zap.Error(err))
+		http.Error(w, err.Error(), membershipErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(plan)
+}
+
+func (h *MembershipHandler) UpdatePlan(w http.ResponseWriter, r *http.Request) {
+	var input membership.UpdateMembershipPlanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Error("Erro ao decodificar requisição", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.PlanID = chi.URLParam(r, "id")
+
+	plan, err := h.updatePlan.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao atualizar plano de assinatura", zap.Error(err))
+		http.Error(w, err.Error(), membershipErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
+
+func (h *MembershipHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.subscribe.Execute(r.Context(), membership.SubscribeInput{
+		PlanID: chi.URLParam(r, "id"),
+		UserID: claims.UserID,
+	})
+	if err != nil {
+		logger.Error("Erro ao assinar plano", zap.Error(err))
+		http.Error(w, err.Error(), membershipErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *MembershipHandler) Mine(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.getMine.Execute(r.Context(), claims.UserID)
+	if err != nil {
+		logger.Error("Erro ao consultar assinatura", zap.Error(err))
+		http.Error(w, err.Error(), membershipErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *MembershipHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.cancel.Execute(r.Context(), claims.UserID)
+	if err != nil {
+		logger.Error("Erro ao cancelar assinatura", zap.Error(err))
+		http.Error(w, err.Error(), membershipErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func membershipErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, membership.ErrInvalidPlanID),
+		errors.Is(err, membership.ErrInvalidUserID),
+		errors.Is(err, membership.ErrInvalidPlan):
+		return http.StatusBadRequest
+	case errors.Is(err, membership.ErrNotStudent):
+		return http.StatusForbidden
+	case errors.Is(err, membership.ErrUserNotFound),
+		errors.Is(err, membership.ErrNoMembership),
+		errors.Is(err, repository.ErrMembershipPlanNotFound),
+		errors.Is(err, repository.ErrMembershipNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, membership.ErrPlanInactive),
+		errors.Is(err, membership.ErrAlreadySubscribed),
+		errors.Is(err, membership.ErrMembershipNotOpen):
+		return http.StatusConflict
+	case errors.Is(err, membership.ErrSubscriptionFailed),
+		errors.Is(err, membership.ErrCancelFailed):
+		return http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
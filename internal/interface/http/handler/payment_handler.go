@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/receipt"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/payment"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type PaymentHandler struct {
+	refundPayment       *payment.RefundPaymentUseCase
+	listRefunds         *payment.ListRefundsUseCase
+	reconcilePayments   *payment.ReconcilePaymentsUseCase
+	listReconciliations *payment.ListReconciliationsUseCase
+	getReceipt          *payment.GetReceiptUseCase
+	receiptRenderer     *receipt.Renderer
+}
+
+func NewPaymentHandler(
+	refundPayment *payment.RefundPaymentUseCase,
+	listRefunds *payment.ListRefundsUseCase,
+	reconcilePayments *payment.ReconcilePaymentsUseCase,
+	listReconciliations *payment.ListReconciliationsUseCase,
+	getReceipt *payment.GetReceiptUseCase,
+	receiptRenderer *receipt.Renderer,
+) *PaymentHandler {
+	return &PaymentHandler{
+		refundPayment:       refundPayment,
+		listRefunds:         listRefunds,
+		reconcilePayments:   reconcilePayments,
+		listReconciliations: listReconciliations,
+		getReceipt:          getReceipt,
+		receiptRenderer:     receiptRenderer,
+	}
+}
+
+func (h *PaymentHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	var input payment.RefundPaymentInput
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			logger.Error("Erro ao decodificar requisição", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	input.PaymentID = chi.URLParam(r, "id")
+	input.ActorID = claims.UserID
+
+	result, err := h.refundPayment.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao estornar pagamento", zap.Error(err))
+		http.Error(w, err.Error(), paymentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *PaymentHandler) ListRefunds(w http.ResponseWriter, r *http.Request) {
+	result, err := h.listRefunds.Execute(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		logger.Error("Erro ao listar estornos", zap.Error(err))
+		http.Error(w, err.Error(), paymentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *PaymentHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	report, err := h.reconcilePayments.Run(r.Context(), claims.UserID)
+	if err != nil {
+		logger.Error("Erro ao conciliar pagamentos", zap.Error(err))
+		http.Error(w, err.Error(), paymentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *PaymentHandler) ListReconciliations(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.listReconciliations.Execute(r.Context())
+	if err != nil {
+		logger.Error("Erro ao listar conciliações de pagamentos", zap.Error(err))
+		http.Error(w, err.Error(), paymentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// Receipt devolve o recibo do pagamento em PDF ou, com ?format=html, em HTML.
+func (h *PaymentHandler) Receipt(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.getReceipt.Execute(r.Context(), payment.GetReceiptInput{
+		PaymentID: chi.URLParam(r, "id"),
+		UserID:    claims.UserID,
+		Role:      claims.Role,
+	})
+	if err != nil {
+		logger.Error("Erro ao obter recibo", zap.Error(err))
+		http.Error(w, err.Error(), paymentErrorStatus(err))
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType, extension := "application/pdf", "pdf"
+	if r.URL.Query().Get("format") == "html" {
+		contentType, extension = "text/html; charset=utf-8", "html"
+		err = h.receiptRenderer.HTML(&buf, result)
+	} else {
+		err = h.receiptRenderer.PDF(&buf, result)
+	}
+	if err != nil {
+		logger.Error("Erro ao gerar recibo", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="recibo-%s.%s"`, result.Code(), extension))
+	buf.WriteTo(w)
+}
+
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payment.ErrInvalidPaymentID),
+		errors.Is(err, payment.ErrInvalidUserID),
+		errors.Is(err, payment.ErrInvalidRefundAmount):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrPaymentNotFound),
+		errors.Is(err, repository.ErrReceiptNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, payment.ErrNotRefundable),
+		errors.Is(err, payment.ErrReceiptUnavailable):
+		return http.StatusConflict
+	case errors.Is(err, payment.ErrRefundFailed):
+		return http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
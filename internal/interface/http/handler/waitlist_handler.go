@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/waitlist"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type WaitlistHandler struct {
+	joinWaitlist  *waitlist.JoinWaitlistUseCase
+	leaveWaitlist *waitlist.LeaveWaitlistUseCase
+	getPosition   *waitlist.GetWaitlistPositionUseCase
+}
+
+func NewWaitlistHandler(
+	joinWaitlist *waitlist.JoinWaitlistUseCase,
+	leaveWaitlist *waitlist.LeaveWaitlistUseCase,
+	getPosition *waitlist.GetWaitlistPositionUseCase,
+) *WaitlistHandler {
+	return &WaitlistHandler{
+		joinWaitlist:  joinWaitlist,
+		leaveWaitlist: leaveWaitlist,
+		getPosition:   getPosition,
+	}
+}
+
+func (h *WaitlistHandler) Join(w http.ResponseWriter, r *http.Request) {
+	input, ok := waitlistInput(w, r)
+	if !ok {
+		return
+	}
+	if r.ContentLength > 0 {
+		var body waitlist.WaitlistInput
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			logger.Error("Erro ao decodificar requisição", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		input.UseCredit = body.UseCredit
+	}
+
+	result, err := h.joinWaitlist.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao entrar na lista de espera", zap.Error(err))
+		http.Error(w, err.Error(), waitlistErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *WaitlistHandler) Position(w http.ResponseWriter, r *http.Request) {
+	input, ok := waitlistInput(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.getPosition.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao consultar lista de espera", zap.Error(err))
+		http.Error(w, err.Error(), waitlistErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *WaitlistHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	input, ok := waitlistInput(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.leaveWaitlist.Execute(r.Context(), input); err != nil {
+		logger.Error("Erro ao sair da lista de espera", zap.Error(err))
+		http.Error(w, err.Error(), waitlistErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func waitlistInput(w http.ResponseWriter, r *http.Request) (waitlist.WaitlistInput, bool) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return waitlist.WaitlistInput{}, false
+	}
+
+	return waitlist.WaitlistInput{
+		UserID:  claims.UserID,
+		ClassID: chi.URLParam(r, "id"),
+	}, true
+}
+
+func waitlistErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, waitlist.ErrInvalidClassID),
+		errors.Is(err, waitlist.ErrInvalidUserID):
+		return http.StatusBadRequest
+	case errors.Is(err, waitlist.ErrNotStudent):
+		return http.StatusForbidden
+	case errors.Is(err, waitlist.ErrNotWaitlisted),
+		errors.Is(err, repository.ErrClassNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, waitlist.ErrClassHasSpots),
+		errors.Is(err, waitlist.ErrClassStarted),
+		errors.Is(err, waitlist.ErrClassNotOpen),
+		errors.Is(err, waitlist.ErrAlreadyEnrolled),
+		errors.Is(err, waitlist.ErrAlreadyWaitlisted):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
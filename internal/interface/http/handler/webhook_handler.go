@@ -1,43 +1,182 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/infrastructure/http/middleware"
+	paymentInfra "github.com/marcelobritu/isayoga-api/internal/infrastructure/payment"
 	"github.com/marcelobritu/isayoga-api/internal/usecase/payment"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// maxWebhookBody limita o corpo aceito; as notificações do Mercado Pago têm poucas centenas de bytes.
+const maxWebhookBody = 64 << 10
+
 type WebhookHandler struct {
-	processWebhook *payment.ProcessWebhookUseCase
+	receiveWebhook     *payment.ReceiveWebhookUseCase
+	listWebhookEvents  *payment.ListWebhookEventsUseCase
+	replayWebhookEvent *payment.ReplayWebhookEventUseCase
+	config             *config.Config
 }
 
-func NewWebhookHandler(processWebhook *payment.ProcessWebhookUseCase) *WebhookHandler {
+func NewWebhookHandler(
+	receiveWebhook *payment.ReceiveWebhookUseCase,
+	listWebhookEvents *payment.ListWebhookEventsUseCase,
+	replayWebhookEvent *payment.ReplayWebhookEventUseCase,
+	config *config.Config,
+) *WebhookHandler {
 	return &WebhookHandler{
-		processWebhook: processWebhook,
+		receiveWebhook:     receiveWebhook,
+		listWebhookEvents:  listWebhookEvents,
+		replayWebhookEvent: replayWebhookEvent,
+		config:             config,
 	}
 }
 
 func (h *WebhookHandler) MercadoPago(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		logger.Error("Erro ao ler webhook", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var input payment.WebhookInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+	if err := json.Unmarshal(body, &input); err != nil {
 		logger.Error("Erro ao decodificar webhook", zap.Error(err))
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	if !h.validSignature(r, input) {
+		http.Error(w, "Assinatura inválida", http.StatusUnauthorized)
+		return
+	}
+
 	logger.Info("Webhook recebido",
 		zap.String("type", input.Type),
 		zap.String("action", input.Action),
 	)
 
-	if err := h.processWebhook.Execute(r.Context(), input); err != nil {
-		logger.Error("Erro ao processar webhook", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	err = h.receiveWebhook.Execute(r.Context(), payment.ReceiveWebhookInput{
+		Payload:   body,
+		RequestID: r.Header.Get("x-request-id"),
+	})
+	if err != nil {
+		logger.Error("Erro ao registrar webhook", zap.Error(err))
+		http.Error(w, err.Error(), webhookErrorStatus(err))
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+func (h *WebhookHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	input := payment.ListWebhookEventsInput{
+		Status: r.URL.Query().Get("status"),
+	}
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			http.Error(w, "parâmetro limit inválido", http.StatusBadRequest)
+			return
+		}
+		input.Limit = limit
+	}
+
+	events, err := h.listWebhookEvents.Execute(r.Context(), input)
+	if err != nil {
+		logger.Error("Erro ao listar eventos de webhook", zap.Error(err))
+		http.Error(w, err.Error(), webhookErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+func (h *WebhookHandler) ReplayEvent(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.UserClaimsKey).(*pkgAuth.Claims)
+	if !ok {
+		http.Error(w, "Usuário não autenticado", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := h.replayWebhookEvent.Execute(r.Context(), payment.ReplayWebhookEventInput{
+		EventID: chi.URLParam(r, "id"),
+		ActorID: claims.UserID,
+	})
+	if err != nil {
+		logger.Error("Erro ao reprocessar evento de webhook", zap.Error(err))
+		http.Error(w, err.Error(), webhookErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(event)
+}
+
+func (h *WebhookHandler) validSignature(r *http.Request, input payment.WebhookInput) bool {
+	secret := h.config.MercadoPago.WebhookSecret
+	if secret == "" {
+		// Só o gateway fake, restrito ao ambiente de desenvolvimento, dispensa o segredo.
+		if h.config.Payment.Provider == "fake" {
+			logger.Warn("MERCADOPAGO_WEBHOOK_SECRET não configurado, assinatura do webhook não verificada")
+			return true
+		}
+		logger.Error("MERCADOPAGO_WEBHOOK_SECRET não configurado, webhook rejeitado")
+		return false
+	}
+
+	dataID, err := paymentInfra.SignedDataID(r.URL.Query().Get("data.id"), input.Data.ID)
+	if err == nil {
+		err = paymentInfra.ValidateWebhookSignature(
+			secret,
+			r.Header.Get("x-signature"),
+			r.Header.Get("x-request-id"),
+			dataID,
+			h.config.MercadoPago.WebhookTolerance,
+			time.Now(),
+		)
+	}
+	if err != nil {
+		logger.Warn("Webhook rejeitado",
+			zap.Error(err),
+			zap.String("request_id", r.Header.Get("x-request-id")),
+			zap.String("remote_addr", r.RemoteAddr),
+		)
+		return false
+	}
+
+	return true
+}
+
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, payment.ErrInvalidWebhookPayload),
+		errors.Is(err, payment.ErrInvalidWebhookEventID),
+		errors.Is(err, payment.ErrInvalidWebhookStatus),
+		errors.Is(err, payment.ErrInvalidUserID):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrWebhookEventNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, payment.ErrWebhookEventInProgress):
+		return http.StatusConflict
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
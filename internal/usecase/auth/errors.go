@@ -0,0 +1,9 @@
+package auth
+
+import "errors"
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token inválido ou expirado")
+	ErrInvalidSessionID    = errors.New("session_id inválido")
+	ErrInvalidUserID       = errors.New("user_id inválido")
+)
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ListSessionsUseCase struct {
+	sessionRepo repository.SessionRepository
+}
+
+func NewListSessionsUseCase(sessionRepo repository.SessionRepository) *ListSessionsUseCase {
+	return &ListSessionsUseCase{
+		sessionRepo: sessionRepo,
+	}
+}
+
+type ListSessionsInput struct {
+	UserID           string
+	CurrentSessionID string
+}
+
+type SessionOutput struct {
+	*entity.Session
+	Current bool `json:"current"`
+}
+
+// Execute lista as sessões ativas do usuário, da usada mais recentemente para a mais
+// antiga, marcando a sessão do token da requisição.
+func (uc *ListSessionsUseCase) Execute(ctx context.Context, input ListSessionsInput) ([]*SessionOutput, error) {
+	userID, err := primitive.ObjectIDFromHex(input.UserID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	sessions, err := uc.sessionRepo.FindActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*SessionOutput, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &SessionOutput{
+			Session: session,
+			Current: session.ID.Hex() == input.CurrentSessionID,
+		})
+	}
+	return result, nil
+}
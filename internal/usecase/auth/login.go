@@ -6,19 +6,20 @@ import (
 	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
-	"github.com/marcelobritu/isayoga-api/pkg/auth"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.uber.org/zap"
 )
 
 type LoginInput struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	UserAgent string `json:"-"`
+	IP        string `json:"-"`
 }
 
 type LoginOutput struct {
-	Token string `json:"token"`
-	User  struct {
+	Tokens
+	User struct {
 		ID    string `json:"id"`
 		Name  string `json:"name"`
 		Email string `json:"email"`
@@ -27,12 +28,14 @@ type LoginOutput struct {
 }
 
 type LoginUseCase struct {
-	userRepo repository.UserRepository
+	userRepo     repository.UserRepository
+	startSession *StartSessionUseCase
 }
 
-func NewLoginUseCase(userRepo repository.UserRepository) *LoginUseCase {
+func NewLoginUseCase(userRepo repository.UserRepository, startSession *StartSessionUseCase) *LoginUseCase {
 	return &LoginUseCase{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		startSession: startSession,
 	}
 }
 
@@ -55,7 +58,11 @@ func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginOu
 		return nil, fmt.Errorf("credenciais inválidas")
 	}
 
-	token, err := auth.GenerateToken(user)
+	tokens, err := uc.startSession.Execute(ctx, StartSessionInput{
+		User:      user,
+		UserAgent: input.UserAgent,
+		IP:        input.IP,
+	})
 	if err != nil {
 		logger.Error("Erro ao gerar token JWT", zap.Error(err), zap.String("user_id", user.ID.Hex()))
 		return nil, fmt.Errorf("erro ao gerar token de autenticação")
@@ -64,7 +71,7 @@ func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginOu
 	logger.Info("Usuário logado com sucesso", zap.String("user_id", user.ID.Hex()), zap.String("email", user.Email))
 
 	return &LoginOutput{
-		Token: token,
+		Tokens: *tokens,
 		User: struct {
 			ID    string `json:"id"`
 			Name  string `json:"name"`
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type LogoutUseCase struct {
+	sessionRepo      repository.SessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+}
+
+func NewLogoutUseCase(
+	sessionRepo repository.SessionRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+) *LogoutUseCase {
+	return &LogoutUseCase{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Execute encerra a sessão do refresh token informado. Tokens desconhecidos são ignorados,
+// de modo que repetir o logout não gera erro.
+func (uc *LogoutUseCase) Execute(ctx context.Context, input LogoutInput) error {
+	if input.RefreshToken == "" {
+		return ErrInvalidRefreshToken
+	}
+
+	token, err := uc.refreshTokenRepo.FindByHash(ctx, pkgAuth.HashRefreshToken(input.RefreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := uc.sessionRepo.Revoke(ctx, token.SessionID, entity.SessionRevokedLogout, time.Now()); err != nil {
+		return err
+	}
+
+	logger.Info("Sessão encerrada",
+		zap.String("user_id", token.UserID.Hex()),
+		zap.String("session_id", token.SessionID.Hex()),
+	)
+
+	return nil
+}
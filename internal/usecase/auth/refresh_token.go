@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RefreshTokenUseCase troca um refresh token válido por um novo par de tokens. Cada
+// refresh token vale uma única vez: apresentar de novo um token já trocado indica que ele
+// vazou, e a sessão inteira é revogada.
+type RefreshTokenUseCase struct {
+	sessionRepo      repository.SessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	userRepo         repository.UserRepository
+	config           *config.Config
+}
+
+func NewRefreshTokenUseCase(
+	sessionRepo repository.SessionRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	userRepo repository.UserRepository,
+	config *config.Config,
+) *RefreshTokenUseCase {
+	return &RefreshTokenUseCase{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		userRepo:         userRepo,
+		config:           config,
+	}
+}
+
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token"`
+	UserAgent    string `json:"-"`
+	IP           string `json:"-"`
+}
+
+func (uc *RefreshTokenUseCase) Execute(ctx context.Context, input RefreshTokenInput) (*Tokens, error) {
+	if input.RefreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	token, err := uc.refreshTokenRepo.FindByHash(ctx, pkgAuth.HashRefreshToken(input.RefreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RotatedAt != nil {
+		return nil, uc.revokeReused(ctx, token)
+	}
+
+	now := time.Now()
+	session, err := uc.sessionRepo.FindByID(ctx, token.SessionID)
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsActive(now) || !now.Before(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		logger.Warn("Refresh token de usuário inexistente", zap.String("user_id", token.UserID.Hex()), zap.Error(err))
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Duas trocas simultâneas do mesmo token também contam como reutilização.
+	rotated, err := uc.refreshTokenRepo.MarkRotated(ctx, token.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, uc.revokeReused(ctx, token)
+	}
+
+	session.Touch(input.UserAgent, input.IP, now.Add(uc.config.Auth.RefreshTokenTTL))
+	if err := uc.sessionRepo.Update(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return issueTokens(ctx, uc.refreshTokenRepo, session, user)
+}
+
+func (uc *RefreshTokenUseCase) revokeReused(ctx context.Context, token *entity.RefreshToken) error {
+	logger.Warn("Reutilização de refresh token detectada, sessão revogada",
+		zap.String("user_id", token.UserID.Hex()),
+		zap.String("session_id", token.SessionID.Hex()),
+	)
+	if err := uc.sessionRepo.Revoke(ctx, token.SessionID, entity.SessionRevokedReuse, time.Now()); err != nil {
+		return err
+	}
+	return ErrInvalidRefreshToken
+}
@@ -0,0 +1,298 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeSessionRepo struct {
+	repository.SessionRepository
+	sessions map[primitive.ObjectID]*entity.Session
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session *entity.Session) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *fakeSessionRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, repository.ErrSessionNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) Update(ctx context.Context, session *entity.Session) error {
+	return r.Create(ctx, session)
+}
+
+func (r *fakeSessionRepo) Revoke(ctx context.Context, id primitive.ObjectID, reason string, at time.Time) error {
+	session, ok := r.sessions[id]
+	if !ok {
+		return repository.ErrSessionNotFound
+	}
+	if session.RevokedAt == nil {
+		session.RevokedAt = &at
+		session.RevokedReason = reason
+	}
+	return nil
+}
+
+type fakeRefreshTokenRepo struct {
+	tokens map[string]*entity.RefreshToken
+	// lostRace simula outra troca concorrente do mesmo token vencendo a corrida.
+	lostRace bool
+}
+
+func (r *fakeRefreshTokenRepo) Create(ctx context.Context, token *entity.RefreshToken) error {
+	copied := *token
+	r.tokens[token.TokenHash] = &copied
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, repository.ErrRefreshTokenNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *fakeRefreshTokenRepo) MarkRotated(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	if r.lostRace {
+		return false, nil
+	}
+	for _, token := range r.tokens {
+		if token.ID == id {
+			if token.RotatedAt != nil {
+				return false, nil
+			}
+			token.RotatedAt = &at
+			return true, nil
+		}
+	}
+	return false, repository.ErrRefreshTokenNotFound
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[primitive.ObjectID]*entity.User
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return user, nil
+}
+
+type refreshFixture struct {
+	sessions *fakeSessionRepo
+	tokens   *fakeRefreshTokenRepo
+	users    *fakeUserRepo
+	user     *entity.User
+	start    *StartSessionUseCase
+	refresh  *RefreshTokenUseCase
+}
+
+func newRefreshFixture(t *testing.T) *refreshFixture {
+	t.Helper()
+
+	cfg := &config.Config{Auth: config.AuthConfig{RefreshTokenTTL: time.Hour}}
+	user := &entity.User{ID: primitive.NewObjectID(), Email: "aluna@example.com", Role: entity.RoleStudent}
+
+	f := &refreshFixture{
+		sessions: &fakeSessionRepo{sessions: map[primitive.ObjectID]*entity.Session{}},
+		tokens:   &fakeRefreshTokenRepo{tokens: map[string]*entity.RefreshToken{}},
+		users:    &fakeUserRepo{users: map[primitive.ObjectID]*entity.User{user.ID: user}},
+		user:     user,
+	}
+	f.start = NewStartSessionUseCase(f.sessions, f.tokens, cfg)
+	f.refresh = NewRefreshTokenUseCase(f.sessions, f.tokens, f.users, cfg)
+	return f
+}
+
+func (f *refreshFixture) login(t *testing.T) *Tokens {
+	t.Helper()
+	tokens, err := f.start.Execute(context.Background(), StartSessionInput{User: f.user})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	return tokens
+}
+
+func (f *refreshFixture) session(t *testing.T, refreshToken string) *entity.Session {
+	t.Helper()
+	token, ok := f.tokens.tokens[pkgAuth.HashRefreshToken(refreshToken)]
+	if !ok {
+		t.Fatalf("refresh token não gravado")
+	}
+	return f.sessions.sessions[token.SessionID]
+}
+
+func TestRefreshToken(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup prepara o estado e devolve o refresh token apresentado na troca.
+		setup         func(t *testing.T, f *refreshFixture) string
+		wantErr       error
+		wantRevokedBy string
+	}{
+		{
+			name: "token válido é trocado",
+			setup: func(t *testing.T, f *refreshFixture) string {
+				return f.login(t).RefreshToken
+			},
+		},
+		{
+			name: "token vazio",
+			setup: func(t *testing.T, f *refreshFixture) string {
+				f.login(t)
+				return ""
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "token desconhecido",
+			setup: func(t *testing.T, f *refreshFixture) string {
+				f.login(t)
+				return "desconhecido"
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "token já trocado revoga a sessão",
+			setup: func(t *testing.T, f *refreshFixture) string {
+				tokens := f.login(t)
+				if _, err := f.refresh.Execute(context.Background(), RefreshTokenInput{RefreshToken: tokens.RefreshToken}); err != nil {
+					t.Fatalf("primeira troca: %v", err)
+				}
+				return tokens.RefreshToken
+			},
+			wantErr:       ErrInvalidRefreshToken,
+			wantRevokedBy: entity.SessionRevokedReuse,
+		},
+		{
+			name: "troca concorrente perdida revoga a sessão",
+			setup: func(t *testing.T, f *refreshFixture) string {
+				tokens := f.login(t)
+				f.tokens.lostRace = true
+				return tokens.RefreshToken
+			},
+			wantErr:       ErrInvalidRefreshToken,
+			wantRevokedBy: entity.SessionRevokedReuse,
+		},
+		{
+			name: "sessão revogada no logout",
+			setup: func(t *testing.T, f *refreshFixture) string {
+				tokens := f.login(t)
+				session := f.session(t, tokens.RefreshToken)
+				f.sessions.Revoke(context.Background(), session.ID, entity.SessionRevokedLogout, time.Now())
+				return tokens.RefreshToken
+			},
+			wantErr:       ErrInvalidRefreshToken,
+			wantRevokedBy: entity.SessionRevokedLogout,
+		},
+		{
+			name: "token expirado",
+			setup: func(t *testing.T, f *refreshFixture) string {
+				tokens := f.login(t)
+				f.tokens.tokens[pkgAuth.HashRefreshToken(tokens.RefreshToken)].ExpiresAt = time.Now().Add(-time.Minute)
+				return tokens.RefreshToken
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "usuário removido",
+			setup: func(t *testing.T, f *refreshFixture) string {
+				tokens := f.login(t)
+				delete(f.users.users, f.user.ID)
+				return tokens.RefreshToken
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRefreshFixture(t)
+			presented := tt.setup(t, f)
+
+			var session *entity.Session
+			if stored, ok := f.tokens.tokens[pkgAuth.HashRefreshToken(presented)]; ok {
+				session = f.sessions.sessions[stored.SessionID]
+			}
+
+			tokens, err := f.refresh.Execute(context.Background(), RefreshTokenInput{RefreshToken: presented})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() erro = %v, esperado %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil {
+				if tokens.RefreshToken == "" || tokens.RefreshToken == presented {
+					t.Fatalf("esperava um novo refresh token, recebeu %q", tokens.RefreshToken)
+				}
+				if f.session(t, tokens.RefreshToken).ID != session.ID {
+					t.Fatalf("novo refresh token deveria pertencer à mesma sessão")
+				}
+				if f.tokens.tokens[pkgAuth.HashRefreshToken(presented)].RotatedAt == nil {
+					t.Fatalf("token apresentado deveria ficar marcado como trocado")
+				}
+			}
+
+			if session == nil {
+				return
+			}
+			if tt.wantRevokedBy == "" && session.RevokedAt != nil {
+				t.Fatalf("sessão revogada inesperadamente (%s)", session.RevokedReason)
+			}
+			if tt.wantRevokedBy != "" && session.RevokedReason != tt.wantRevokedBy {
+				t.Fatalf("motivo da revogação = %q, esperado %q", session.RevokedReason, tt.wantRevokedBy)
+			}
+		})
+	}
+}
+
+// TestRefreshTokenReuseInvalidatesChain cobre a sequência completa: após uma troca
+// legítima, o reuso do token antigo revoga a sessão e o token mais novo também para de
+// funcionar.
+func TestRefreshTokenReuseInvalidatesChain(t *testing.T) {
+	f := newRefreshFixture(t)
+	ctx := context.Background()
+
+	first := f.login(t)
+	second, err := f.refresh.Execute(ctx, RefreshTokenInput{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("primeira troca: %v", err)
+	}
+	third, err := f.refresh.Execute(ctx, RefreshTokenInput{RefreshToken: second.RefreshToken})
+	if err != nil {
+		t.Fatalf("segunda troca: %v", err)
+	}
+
+	if _, err := f.refresh.Execute(ctx, RefreshTokenInput{RefreshToken: first.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("reuso do primeiro token: erro = %v, esperado %v", err, ErrInvalidRefreshToken)
+	}
+
+	if _, err := f.refresh.Execute(ctx, RefreshTokenInput{RefreshToken: third.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("token mais novo após o reuso: erro = %v, esperado %v", err, ErrInvalidRefreshToken)
+	}
+
+	session := f.session(t, first.RefreshToken)
+	if session.IsActive(time.Now()) || session.RevokedReason != entity.SessionRevokedReuse {
+		t.Fatalf("sessão deveria estar revogada por reuso, motivo = %q", session.RevokedReason)
+	}
+}
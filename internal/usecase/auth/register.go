@@ -7,7 +7,6 @@ import (
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
-	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -17,11 +16,15 @@ type RegisterInput struct {
 	Email    string          `json:"email"`
 	Password string          `json:"password"`
 	Role     entity.UserRole `json:"role"`
+	CPF      string          `json:"cpf"`
+
+	UserAgent string `json:"-"`
+	IP        string `json:"-"`
 }
 
 type RegisterOutput struct {
-	Token string `json:"token"`
-	User  struct {
+	Tokens
+	User struct {
 		ID    string `json:"id"`
 		Name  string `json:"name"`
 		Email string `json:"email"`
@@ -30,12 +33,14 @@ type RegisterOutput struct {
 }
 
 type RegisterUseCase struct {
-	userRepo repository.UserRepository
+	userRepo     repository.UserRepository
+	startSession *StartSessionUseCase
 }
 
-func NewRegisterUseCase(userRepo repository.UserRepository) *RegisterUseCase {
+func NewRegisterUseCase(userRepo repository.UserRepository, startSession *StartSessionUseCase) *RegisterUseCase {
 	return &RegisterUseCase{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		startSession: startSession,
 	}
 }
 
@@ -56,6 +61,11 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, input RegisterInput) (*R
 		return nil, fmt.Errorf("role inválido: deve ser student, instructor ou admin")
 	}
 
+	cpf, valid := entity.NormalizeCPF(input.CPF)
+	if !valid {
+		return nil, fmt.Errorf("CPF inválido")
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -69,13 +79,18 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, input RegisterInput) (*R
 		logger.Error("Erro ao criar hash da senha", zap.Error(err))
 		return nil, fmt.Errorf("erro ao criar usuário")
 	}
+	user.CPF = cpf
 
 	if err := uc.userRepo.Create(ctx, user); err != nil {
 		logger.Error("Erro ao criar usuário no repositório", zap.Error(err))
 		return nil, fmt.Errorf("erro ao criar usuário: %w", err)
 	}
 
-	token, err := pkgAuth.GenerateToken(user)
+	tokens, err := uc.startSession.Execute(ctx, StartSessionInput{
+		User:      user,
+		UserAgent: input.UserAgent,
+		IP:        input.IP,
+	})
 	if err != nil {
 		logger.Error("Erro ao gerar token", zap.Error(err))
 		return nil, fmt.Errorf("erro ao gerar token de autenticação")
@@ -88,7 +103,7 @@ func (uc *RegisterUseCase) Execute(ctx context.Context, input RegisterInput) (*R
 	)
 
 	output := &RegisterOutput{
-		Token: token,
+		Tokens: *tokens,
 	}
 	output.User.ID = user.ID.Hex()
 	output.User.Name = user.Name
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type RevokeSessionUseCase struct {
+	sessionRepo repository.SessionRepository
+}
+
+func NewRevokeSessionUseCase(sessionRepo repository.SessionRepository) *RevokeSessionUseCase {
+	return &RevokeSessionUseCase{
+		sessionRepo: sessionRepo,
+	}
+}
+
+type RevokeSessionInput struct {
+	UserID    string
+	SessionID string
+}
+
+// Execute revoga uma sessão do próprio usuário; sessões de outros usuários são tratadas
+// como inexistentes.
+func (uc *RevokeSessionUseCase) Execute(ctx context.Context, input RevokeSessionInput) error {
+	userID, err := primitive.ObjectIDFromHex(input.UserID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+	sessionID, err := primitive.ObjectIDFromHex(input.SessionID)
+	if err != nil {
+		return ErrInvalidSessionID
+	}
+
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return repository.ErrSessionNotFound
+	}
+
+	if err := uc.sessionRepo.Revoke(ctx, session.ID, entity.SessionRevokedByUser, time.Now()); err != nil {
+		return err
+	}
+
+	logger.Info("Sessão revogada pelo usuário",
+		zap.String("user_id", input.UserID),
+		zap.String("session_id", input.SessionID),
+	)
+
+	return nil
+}
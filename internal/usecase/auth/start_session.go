@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	pkgAuth "github.com/marcelobritu/isayoga-api/pkg/auth"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+)
+
+// Tokens é o par entregue ao cliente no login, no cadastro e a cada renovação: o access
+// token de curta duração e o refresh token, que só pode ser usado uma vez.
+type Tokens struct {
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+// StartSessionUseCase abre uma sessão para o usuário autenticado e emite os tokens.
+type StartSessionUseCase struct {
+	sessionRepo      repository.SessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	config           *config.Config
+}
+
+func NewStartSessionUseCase(
+	sessionRepo repository.SessionRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	config *config.Config,
+) *StartSessionUseCase {
+	return &StartSessionUseCase{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		config:           config,
+	}
+}
+
+type StartSessionInput struct {
+	User      *entity.User
+	UserAgent string
+	IP        string
+}
+
+func (uc *StartSessionUseCase) Execute(ctx context.Context, input StartSessionInput) (*Tokens, error) {
+	session := entity.NewSession(input.User.ID, input.UserAgent, input.IP, time.Now().Add(uc.config.Auth.RefreshTokenTTL))
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return issueTokens(ctx, uc.refreshTokenRepo, session, input.User)
+}
+
+// issueTokens grava um novo refresh token da sessão e emite o access token correspondente.
+func issueTokens(ctx context.Context, refreshTokenRepo repository.RefreshTokenRepository, session *entity.Session, user *entity.User) (*Tokens, error) {
+	refreshToken, hash, err := pkgAuth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := refreshTokenRepo.Create(ctx, entity.NewRefreshToken(session, hash)); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := pkgAuth.GenerateToken(user, session.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		Token:        token,
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+	}, nil
+}
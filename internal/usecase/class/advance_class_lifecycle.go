@@ -0,0 +1,45 @@
+package class
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// AdvanceClassLifecycleUseCase move as aulas publicadas para in_progress quando começam
+// e para completed quando terminam.
+type AdvanceClassLifecycleUseCase struct {
+	classRepo repository.ClassRepository
+}
+
+func NewAdvanceClassLifecycleUseCase(classRepo repository.ClassRepository) *AdvanceClassLifecycleUseCase {
+	return &AdvanceClassLifecycleUseCase{
+		classRepo: classRepo,
+	}
+}
+
+func (uc *AdvanceClassLifecycleUseCase) Execute(ctx context.Context) error {
+	now := time.Now()
+
+	started, err := uc.classRepo.StartDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	completed, err := uc.classRepo.CompleteDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	if started > 0 || completed > 0 {
+		logger.Info("Status das aulas atualizado",
+			zap.Int64("started", started),
+			zap.Int64("completed", completed),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package class
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/payment"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// CancelClassUseCase cancela a aula e, em cascata, as inscrições pendentes e confirmadas,
+// a lista de espera e os pagamentos. Os estornos dos pagamentos aprovados são gravados na
+// mesma transação e enviados ao gateway em seguida; os que falharem ficam para o worker de
+// estornos. Os alunos são avisados.
+type CancelClassUseCase struct {
+	classRepo      repository.ClassRepository
+	enrollmentRepo repository.EnrollmentRepository
+	paymentRepo    repository.PaymentRepository
+	waitlistRepo   repository.WaitlistRepository
+	userRepo       repository.UserRepository
+	creditRepo     repository.CreditRepository
+	membershipRepo repository.MembershipRepository
+	couponRepo     repository.CouponRepository
+	refundPayment  *payment.RefundPaymentUseCase
+	processRefund  *payment.ProcessRefundOutboxUseCase
+	notifier       gateway.Notifier
+}
+
+func NewCancelClassUseCase(
+	classRepo repository.ClassRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	paymentRepo repository.PaymentRepository,
+	waitlistRepo repository.WaitlistRepository,
+	userRepo repository.UserRepository,
+	creditRepo repository.CreditRepository,
+	membershipRepo repository.MembershipRepository,
+	couponRepo repository.CouponRepository,
+	refundPayment *payment.RefundPaymentUseCase,
+	processRefund *payment.ProcessRefundOutboxUseCase,
+	notifier gateway.Notifier,
+) *CancelClassUseCase {
+	return &CancelClassUseCase{
+		classRepo:      classRepo,
+		enrollmentRepo: enrollmentRepo,
+		paymentRepo:    paymentRepo,
+		waitlistRepo:   waitlistRepo,
+		userRepo:       userRepo,
+		creditRepo:     creditRepo,
+		membershipRepo: membershipRepo,
+		couponRepo:     couponRepo,
+		refundPayment:  refundPayment,
+		processRefund:  processRefund,
+		notifier:       notifier,
+	}
+}
+
+type CancelClassInput struct {
+	ClassID string `json:"-"`
+	Reason  string `json:"reason"`
+}
+
+type CancelClassOutput struct {
+	Class                *entity.Class `json:"class"`
+	CancelledEnrollments int           `json:"cancelled_enrollments"`
+	Refunds              int           `json:"refunds"`
+	PendingRefunds       int           `json:"pending_refunds"`
+}
+
+func (uc *CancelClassUseCase) Execute(ctx context.Context, input CancelClassInput) (*CancelClassOutput, error) {
+	classID, err := primitive.ObjectIDFromHex(input.ClassID)
+	if err != nil {
+		return nil, ErrInvalidClassID
+	}
+
+	return uc.cancel(ctx, classID, input.Reason)
+}
+
+// cancel relê a aula dentro da transação, de modo que uma edição ou mudança de status
+// concorrente não seja sobrescrita nem sobrescreva o cancelamento.
+func (uc *CancelClassUseCase) cancel(ctx context.Context, classID primitive.ObjectID, reason string) (*CancelClassOutput, error) {
+	var (
+		class     *entity.Class
+		cancelled []*entity.Enrollment
+		refunds   []*entity.OutboxMessage
+	)
+
+	err := uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		cancelled, refunds = nil, nil
+
+		var err error
+		class, err = uc.classRepo.FindByID(sc, classID)
+		if err != nil {
+			return err
+		}
+		if err := class.Cancel(); err != nil {
+			return err
+		}
+
+		if err := uc.classRepo.UpdateDetails(sc, class); err != nil {
+			return err
+		}
+
+		enrollments, err := uc.enrollmentRepo.FindActiveByClass(sc, class.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, enrollment := range enrollments {
+			enrollment.Cancel()
+			if err := uc.enrollmentRepo.Update(sc, enrollment); err != nil {
+				return err
+			}
+			cancelled = append(cancelled, enrollment)
+
+			if enrollment.PaidWithMembership() {
+				if _, err := uc.membershipRepo.ReleaseClass(sc, *enrollment.MembershipID, enrollment.EnrolledAt); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if enrollment.PaidWithCredit() {
+				if err := uc.restoreCredit(sc, enrollment); err != nil {
+					return err
+				}
+				continue
+			}
+
+			paymentEntity, err := uc.paymentRepo.FindByEnrollmentID(sc, enrollment.ID)
+			if err != nil {
+				return err
+			}
+			if paymentEntity == nil {
+				continue
+			}
+
+			// O cupom usado em uma aula cancelada pelo estúdio volta a valer para o aluno.
+			if paymentEntity.CouponID != nil {
+				if _, err := uc.couponRepo.ReleaseByEnrollment(sc, enrollment.ID); err != nil {
+					return err
+				}
+			}
+
+			switch {
+			case paymentEntity.RefundableInCents() > 0:
+				_, message, err := uc.refundPayment.Schedule(sc, paymentEntity, 0, "aula cancelada", nil)
+				if err != nil {
+					return err
+				}
+				refunds = append(refunds, message)
+			case paymentEntity.IsAwaiting():
+				paymentEntity.MarkCancelled()
+				if err := uc.paymentRepo.Update(sc, paymentEntity); err != nil {
+					return err
+				}
+			}
+		}
+
+		_, err = uc.waitlistRepo.CancelByClass(sc, class.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := &CancelClassOutput{
+		Class:                class,
+		CancelledEnrollments: len(cancelled),
+	}
+
+	for _, message := range refunds {
+		if _, err := uc.processRefund.Process(ctx, message); err != nil {
+			logger.Warn("Estorno de aula cancelada não concluído, será repetido em segundo plano",
+				zap.String("class_id", class.ID.Hex()),
+				zap.String("refund_id", message.AggregateID.Hex()),
+				zap.Error(err),
+			)
+			output.PendingRefunds++
+			continue
+		}
+		output.Refunds++
+	}
+
+	message := fmt.Sprintf("A aula %s de %s foi cancelada.", class.Title, entity.FormatLocalTime(class.StartTime))
+	if reason != "" {
+		message = fmt.Sprintf("%s Motivo: %s.", message, reason)
+	}
+	notifyStudents(ctx, uc.userRepo, uc.notifier, cancelled, "Aula cancelada", message)
+
+	logger.Info("Aula cancelada",
+		zap.String("class_id", class.ID.Hex()),
+		zap.Int("cancelled_enrollments", output.CancelledEnrollments),
+		zap.Int("refunds", output.Refunds),
+		zap.Int("pending_refunds", output.PendingRefunds),
+	)
+
+	return output, nil
+}
+
+// restoreCredit devolve o crédito usado em uma inscrição da aula cancelada, desde que o
+// lote ainda esteja válido.
+func (uc *CancelClassUseCase) restoreCredit(ctx context.Context, enrollment *entity.Enrollment) error {
+	restored, err := uc.creditRepo.Restore(ctx, *enrollment.CreditLotID, time.Now())
+	if err != nil || !restored {
+		return err
+	}
+
+	entry := entity.NewCreditEntry(enrollment.UserID, *enrollment.CreditLotID, entity.CreditEntryRestore, 1, &enrollment.ID)
+	return uc.creditRepo.AddEntry(ctx, entry)
+}
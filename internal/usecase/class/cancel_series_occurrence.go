@@ -0,0 +1,69 @@
+package class
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CancelSeriesOccurrenceUseCase cancela uma data da série (feriados, por exemplo),
+// registrando-a como exceção para que não volte a ser gerada.
+type CancelSeriesOccurrenceUseCase struct {
+	seriesRepo  repository.ClassSeriesRepository
+	classRepo   repository.ClassRepository
+	cancelClass *CancelClassUseCase
+}
+
+func NewCancelSeriesOccurrenceUseCase(
+	seriesRepo repository.ClassSeriesRepository,
+	classRepo repository.ClassRepository,
+	cancelClass *CancelClassUseCase,
+) *CancelSeriesOccurrenceUseCase {
+	return &CancelSeriesOccurrenceUseCase{
+		seriesRepo:  seriesRepo,
+		classRepo:   classRepo,
+		cancelClass: cancelClass,
+	}
+}
+
+type CancelSeriesOccurrenceInput struct {
+	SeriesID        string    `json:"-"`
+	OccurrenceStart time.Time `json:"occurrence_start"`
+	Reason          string    `json:"reason"`
+}
+
+func (uc *CancelSeriesOccurrenceUseCase) Execute(ctx context.Context, input CancelSeriesOccurrenceInput) (*entity.ClassSeries, error) {
+	seriesID, err := primitive.ObjectIDFromHex(input.SeriesID)
+	if err != nil {
+		return nil, ErrInvalidSeriesID
+	}
+
+	series, err := uc.seriesRepo.FindByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !series.HasOccurrence(input.OccurrenceStart) {
+		return nil, ErrOccurrenceNotFound
+	}
+
+	class, err := uc.classRepo.FindBySeriesOccurrence(ctx, series.ID, input.OccurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+	if class != nil && !class.IsCancelled() {
+		if _, err := uc.cancelClass.cancel(ctx, class.ID, input.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	series.AddException(input.OccurrenceStart)
+	if err := uc.seriesRepo.AddException(ctx, series.ID, input.OccurrenceStart); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
@@ -28,6 +28,7 @@ type CreateClassInput struct {
 	EndTime        time.Time `json:"end_time"`
 	MaxCapacity    int       `json:"max_capacity"`
 	PriceInCents   int64     `json:"price_in_cents"`
+	Draft          bool      `json:"draft"`
 }
 
 func (uc *CreateClassUseCase) Execute(ctx context.Context, input CreateClassInput) (*entity.Class, error) {
@@ -47,6 +48,15 @@ func (uc *CreateClassUseCase) Execute(ctx context.Context, input CreateClassInpu
 		input.PriceInCents,
 	)
 
+	if err := class.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Rascunhos ficam fora da listagem pública até serem publicados.
+	if input.Draft {
+		class.Status = entity.ClassStatusDraft
+	}
+
 	if err := uc.classRepo.Create(ctx, class); err != nil {
 		return nil, err
 	}
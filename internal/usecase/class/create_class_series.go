@@ -0,0 +1,93 @@
+package class
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateClassSeriesUseCase struct {
+	seriesRepo  repository.ClassSeriesRepository
+	materialize *MaterializeClassSeriesUseCase
+}
+
+func NewCreateClassSeriesUseCase(
+	seriesRepo repository.ClassSeriesRepository,
+	materialize *MaterializeClassSeriesUseCase,
+) *CreateClassSeriesUseCase {
+	return &CreateClassSeriesUseCase{
+		seriesRepo:  seriesRepo,
+		materialize: materialize,
+	}
+}
+
+type CreateClassSeriesInput struct {
+	Title           string            `json:"title"`
+	Description     string            `json:"description"`
+	InstructorID    string            `json:"instructor_id"`
+	InstructorName  string            `json:"instructor_name"`
+	StartTime       time.Time         `json:"start_time"`
+	DurationMinutes int               `json:"duration_minutes"`
+	Timezone        string            `json:"timezone"`
+	MaxCapacity     int               `json:"max_capacity"`
+	PriceInCents    int64             `json:"price_in_cents"`
+	Recurrence      entity.Recurrence `json:"recurrence"`
+}
+
+type CreateClassSeriesOutput struct {
+	Series  *entity.ClassSeries `json:"series"`
+	Classes []*entity.Class     `json:"classes"`
+}
+
+func (uc *CreateClassSeriesUseCase) Execute(ctx context.Context, input CreateClassSeriesInput) (*CreateClassSeriesOutput, error) {
+	instructorID, err := primitive.ObjectIDFromHex(input.InstructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.DurationMinutes <= 0 {
+		return nil, fmt.Errorf("duração deve ser maior que zero")
+	}
+	if input.MaxCapacity <= 0 {
+		return nil, fmt.Errorf("capacidade deve ser maior que zero")
+	}
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			return nil, fmt.Errorf("timezone inválido: %s", input.Timezone)
+		}
+	}
+	if err := input.Recurrence.Validate(); err != nil {
+		return nil, err
+	}
+
+	series := entity.NewClassSeries(
+		input.Title,
+		input.Description,
+		instructorID,
+		input.InstructorName,
+		input.StartTime,
+		input.DurationMinutes,
+		input.Timezone,
+		input.MaxCapacity,
+		input.PriceInCents,
+		input.Recurrence,
+	)
+
+	if err := uc.seriesRepo.Create(ctx, series); err != nil {
+		return nil, err
+	}
+
+	classes, err := uc.materialize.Materialize(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateClassSeriesOutput{
+		Series:  series,
+		Classes: classes,
+	}, nil
+}
@@ -0,0 +1,20 @@
+package class
+
+import "errors"
+
+var (
+	ErrInvalidClassID     = errors.New("class_id inválido")
+	ErrInvalidSeriesID    = errors.New("series_id inválido")
+	ErrInvalidScope       = errors.New("escopo inválido: deve ser this ou following")
+	ErrOccurrenceNotFound = errors.New("ocorrência não pertence à série")
+	ErrInvalidSchedule    = errors.New("horário de término deve ser posterior ao início")
+	ErrScheduleChangesDay = errors.New("alterações em ocorrências seguintes devem manter o mesmo dia")
+	ErrClassNotEditable   = errors.New("aula não pode mais ser alterada")
+	ErrClassInPast        = errors.New("horário da aula deve estar no futuro")
+
+	ErrInvalidInstructorID = errors.New("instructor_id inválido")
+	ErrInvalidStatus       = errors.New("status inválido")
+	ErrInvalidSort         = errors.New("ordenação inválida: use start_time, price ou created_at, com - para decrescente")
+	ErrInvalidCursor       = errors.New("cursor inválido")
+	ErrInvalidDateRange    = errors.New("intervalo de datas inválido")
+)
@@ -0,0 +1,108 @@
+package class
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GetClassUseCase struct {
+	classRepo      repository.ClassRepository
+	waitlistRepo   repository.WaitlistRepository
+	enrollmentRepo repository.EnrollmentRepository
+	userRepo       repository.UserRepository
+}
+
+func NewGetClassUseCase(
+	classRepo repository.ClassRepository,
+	waitlistRepo repository.WaitlistRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	userRepo repository.UserRepository,
+) *GetClassUseCase {
+	return &GetClassUseCase{
+		classRepo:      classRepo,
+		waitlistRepo:   waitlistRepo,
+		enrollmentRepo: enrollmentRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// GetClassInput identifica a aula e, opcionalmente, o usuário autenticado.
+type GetClassInput struct {
+	ClassID string
+	UserID  string
+	Role    entity.UserRole
+}
+
+type InstructorSummary struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"`
+}
+
+type ClassDetailOutput struct {
+	Class          *entity.Class      `json:"class"`
+	RemainingSpots int                `json:"remaining_spots"`
+	WaitlistSize   int64              `json:"waitlist_size"`
+	Instructor     InstructorSummary  `json:"instructor"`
+	Enrolled       bool               `json:"enrolled"`
+	Enrollment     *entity.Enrollment `json:"enrollment,omitempty"`
+}
+
+func (uc *GetClassUseCase) Execute(ctx context.Context, input GetClassInput) (*ClassDetailOutput, error) {
+	classID, err := primitive.ObjectIDFromHex(input.ClassID)
+	if err != nil {
+		return nil, ErrInvalidClassID
+	}
+
+	class, err := uc.classRepo.FindByID(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+
+	staff := input.Role == entity.RoleAdmin || input.Role == entity.RoleInstructor
+	if class.Status == entity.ClassStatusDraft && !staff {
+		return nil, repository.ErrClassNotFound
+	}
+
+	waitlistSize, err := uc.waitlistRepo.CountWaiting(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := class.MaxCapacity - class.CurrentEnrolled
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	output := &ClassDetailOutput{
+		Class:          class,
+		RemainingSpots: remaining,
+		WaitlistSize:   waitlistSize,
+		Instructor: InstructorSummary{
+			ID:   class.InstructorID,
+			Name: class.InstructorName,
+		},
+	}
+
+	if instructor, err := uc.userRepo.FindByID(ctx, class.InstructorID); err == nil && instructor != nil {
+		output.Instructor.Name = instructor.Name
+	}
+
+	if input.UserID != "" {
+		userID, err := primitive.ObjectIDFromHex(input.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		enrollment, err := uc.enrollmentRepo.FindByUserAndClass(ctx, userID, classID)
+		if err != nil {
+			return nil, err
+		}
+		output.Enrolled = enrollment != nil
+		output.Enrollment = enrollment
+	}
+
+	return output, nil
+}
@@ -2,11 +2,27 @@ package class
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+var classSorts = map[string]string{
+	"start_time": repository.ClassSortStartTime,
+	"price":      repository.ClassSortPrice,
+	"created_at": repository.ClassSortCreatedAt,
+}
+
 type ListClassesUseCase struct {
 	classRepo repository.ClassRepository
 }
@@ -17,7 +33,162 @@ func NewListClassesUseCase(classRepo repository.ClassRepository) *ListClassesUse
 	}
 }
 
-func (uc *ListClassesUseCase) Execute(ctx context.Context) ([]*entity.Class, error) {
-	return uc.classRepo.FindAll(ctx)
+type ListClassesInput struct {
+	From          *time.Time
+	To            *time.Time
+	InstructorID  string
+	Statuses      []string
+	HasSpots      bool
+	Search        string
+	Sort          string
+	Limit         int
+	Cursor        string
+	IncludeDrafts bool
+}
+
+type ListClassesOutput struct {
+	Classes    []*entity.Class `json:"classes"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// listCursor é serializado em base64 e devolvido ao cliente como next_cursor.
+type listCursor struct {
+	Sort  string                  `json:"sort"`
+	After *repository.ClassCursor `json:"after"`
+}
+
+// Execute lista as aulas com filtros e paginação por cursor. Sem intervalo de datas,
+// apenas aulas futuras são retornadas; rascunhos só aparecem para a equipe.
+func (uc *ListClassesUseCase) Execute(ctx context.Context, input ListClassesInput) (*ListClassesOutput, error) {
+	sort := input.Sort
+	if sort == "" {
+		sort = "start_time"
+	}
+
+	sortField, ok := classSorts[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return nil, ErrInvalidSort
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	filter := repository.ClassFilter{
+		From:       input.From,
+		To:         input.To,
+		HasSpots:   input.HasSpots,
+		Search:     strings.TrimSpace(input.Search),
+		SortBy:     sortField,
+		Descending: strings.HasPrefix(sort, "-"),
+		Limit:      int64(limit + 1),
+	}
+
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, ErrInvalidDateRange
+	}
+	if filter.From == nil && filter.To == nil {
+		now := time.Now()
+		filter.From = &now
+	}
+
+	if input.InstructorID != "" {
+		instructorID, err := primitive.ObjectIDFromHex(input.InstructorID)
+		if err != nil {
+			return nil, ErrInvalidInstructorID
+		}
+		filter.InstructorID = &instructorID
+	}
+
+	statuses, err := listStatuses(input.Statuses, input.IncludeDrafts)
+	if err != nil {
+		return nil, err
+	}
+	filter.Statuses = statuses
+
+	if input.Cursor != "" {
+		cursor, err := decodeListCursor(input.Cursor)
+		if err != nil || cursor.Sort != sort {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = cursor.After
+	}
+
+	classes, err := uc.classRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ListClassesOutput{Classes: classes}
+	if len(classes) > limit {
+		output.Classes = classes[:limit]
+		output.NextCursor = encodeListCursor(listCursor{
+			Sort:  sort,
+			After: repository.NewClassCursor(classes[limit-1]),
+		})
+	}
+
+	return output, nil
+}
+
+func listStatuses(requested []string, includeDrafts bool) ([]string, error) {
+	visible := []string{
+		entity.ClassStatusPublished,
+		entity.ClassStatusActive,
+		entity.ClassStatusInProgress,
+		entity.ClassStatusCompleted,
+		entity.ClassStatusCancelled,
+	}
+	if includeDrafts {
+		visible = append(visible, entity.ClassStatusDraft)
+	}
+
+	if len(requested) == 0 {
+		return visible, nil
+	}
+
+	var statuses []string
+	for _, status := range requested {
+		allowed := false
+		for _, v := range visible {
+			if status == v {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, ErrInvalidStatus
+		}
+
+		statuses = append(statuses, status)
+		if status == entity.ClassStatusPublished {
+			statuses = append(statuses, entity.ClassStatusActive)
+		}
+	}
+	return statuses, nil
+}
+
+func encodeListCursor(cursor listCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
 }
 
+func decodeListCursor(value string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.After == nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
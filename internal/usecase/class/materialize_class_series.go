@@ -0,0 +1,123 @@
+package class
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MaterializeClassSeriesUseCase cria as aulas de cada série ativa dentro do horizonte
+// configurado. É idempotente: ocorrências já criadas não são duplicadas.
+type MaterializeClassSeriesUseCase struct {
+	seriesRepo repository.ClassSeriesRepository
+	classRepo  repository.ClassRepository
+	config     *config.Config
+}
+
+func NewMaterializeClassSeriesUseCase(
+	seriesRepo repository.ClassSeriesRepository,
+	classRepo repository.ClassRepository,
+	config *config.Config,
+) *MaterializeClassSeriesUseCase {
+	return &MaterializeClassSeriesUseCase{
+		seriesRepo: seriesRepo,
+		classRepo:  classRepo,
+		config:     config,
+	}
+}
+
+func (uc *MaterializeClassSeriesUseCase) Execute(ctx context.Context) error {
+	seriesList, err := uc.seriesRepo.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, series := range seriesList {
+		if _, err := uc.Materialize(ctx, series); err != nil {
+			logger.Error("Erro ao gerar aulas da série",
+				zap.String("series_id", series.ID.Hex()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (uc *MaterializeClassSeriesUseCase) Materialize(ctx context.Context, series *entity.ClassSeries) ([]*entity.Class, error) {
+	now := time.Now()
+	horizon := now.Add(uc.config.Class.SeriesHorizon)
+
+	created := []*entity.Class{}
+	for _, start := range series.OccurrencesUntil(horizon) {
+		if !start.After(now) {
+			continue
+		}
+
+		existing, err := uc.classRepo.FindBySeriesOccurrence(ctx, series.ID, start)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			continue
+		}
+
+		class := series.NewOccurrence(start)
+		err = uc.classRepo.Create(ctx, class)
+		if errors.Is(err, repository.ErrOccurrenceExists) {
+			// Gerada em paralelo por outra instância do worker ou por uma edição da série.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, class)
+	}
+
+	if horizon.After(series.MaterializedUntil) {
+		series.MaterializedUntil = horizon
+	}
+	if !series.HasOccurrencesAfter(horizon) {
+		series.End()
+	}
+	if err := uc.seriesRepo.UpdateMaterialization(ctx, series); err != nil {
+		return nil, err
+	}
+
+	if len(created) > 0 {
+		logger.Info("Aulas da série geradas",
+			zap.String("series_id", series.ID.Hex()),
+			zap.Int("count", len(created)),
+		)
+	}
+
+	return created, nil
+}
+
+// occurrenceClass devolve a aula da ocorrência, criando-a se ainda não foi gerada.
+func occurrenceClass(ctx context.Context, classRepo repository.ClassRepository, series *entity.ClassSeries, start time.Time) (*entity.Class, error) {
+	class, err := classRepo.FindBySeriesOccurrence(ctx, series.ID, start)
+	if err != nil || class != nil {
+		return class, err
+	}
+
+	if !series.HasOccurrence(start) {
+		return nil, ErrOccurrenceNotFound
+	}
+
+	class = series.NewOccurrence(start)
+	err = classRepo.Create(ctx, class)
+	if errors.Is(err, repository.ErrOccurrenceExists) {
+		return classRepo.FindBySeriesOccurrence(ctx, series.ID, start)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return class, nil
+}
@@ -0,0 +1,40 @@
+package class
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// notifyStudents avisa os alunos das inscrições informadas. Falhas são apenas registradas
+// para não desfazer uma operação já concluída.
+func notifyStudents(ctx context.Context, userRepo repository.UserRepository, notifier gateway.Notifier, enrollments []*entity.Enrollment, subject, message string) {
+	for _, enrollment := range enrollments {
+		user, err := userRepo.FindByID(ctx, enrollment.UserID)
+		if err != nil {
+			logger.Warn("Aluno não encontrado para notificação",
+				zap.String("user_id", enrollment.UserID.Hex()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		err = notifier.Notify(ctx, &gateway.Notification{
+			UserID:  user.ID,
+			Name:    user.Name,
+			Email:   user.Email,
+			Subject: subject,
+			Message: message,
+		})
+		if err != nil {
+			logger.Warn("Erro ao notificar aluno",
+				zap.String("user_id", user.ID.Hex()),
+				zap.Error(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package class
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PublishClassUseCase struct {
+	classRepo repository.ClassRepository
+}
+
+func NewPublishClassUseCase(classRepo repository.ClassRepository) *PublishClassUseCase {
+	return &PublishClassUseCase{
+		classRepo: classRepo,
+	}
+}
+
+func (uc *PublishClassUseCase) Execute(ctx context.Context, id string) (*entity.Class, error) {
+	classID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidClassID
+	}
+
+	class, err := uc.classRepo.FindByID(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !class.StartTime.After(time.Now()) {
+		return nil, ErrClassInPast
+	}
+
+	if err := class.Publish(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.classRepo.UpdateDetails(ctx, class); err != nil {
+		return nil, err
+	}
+
+	return class, nil
+}
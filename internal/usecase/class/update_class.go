@@ -0,0 +1,159 @@
+package class
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UpdateClassUseCase struct {
+	classRepo      repository.ClassRepository
+	enrollmentRepo repository.EnrollmentRepository
+	userRepo       repository.UserRepository
+	notifier       gateway.Notifier
+}
+
+func NewUpdateClassUseCase(
+	classRepo repository.ClassRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	userRepo repository.UserRepository,
+	notifier gateway.Notifier,
+) *UpdateClassUseCase {
+	return &UpdateClassUseCase{
+		classRepo:      classRepo,
+		enrollmentRepo: enrollmentRepo,
+		userRepo:       userRepo,
+		notifier:       notifier,
+	}
+}
+
+// ClassChanges contém apenas os campos que devem ser alterados.
+type ClassChanges struct {
+	Title          *string    `json:"title"`
+	Description    *string    `json:"description"`
+	InstructorID   *string    `json:"instructor_id"`
+	InstructorName *string    `json:"instructor_name"`
+	StartTime      *time.Time `json:"start_time"`
+	EndTime        *time.Time `json:"end_time"`
+	MaxCapacity    *int       `json:"max_capacity"`
+	PriceInCents   *int64     `json:"price_in_cents"`
+}
+
+func (c ClassChanges) instructorID() (*primitive.ObjectID, error) {
+	if c.InstructorID == nil {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(*c.InstructorID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+type UpdateClassInput struct {
+	ClassID string `json:"-"`
+	ClassChanges
+}
+
+func (uc *UpdateClassUseCase) Execute(ctx context.Context, input UpdateClassInput) (*entity.Class, error) {
+	classID, err := primitive.ObjectIDFromHex(input.ClassID)
+	if err != nil {
+		return nil, ErrInvalidClassID
+	}
+
+	class, err := uc.classRepo.FindByID(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.update(ctx, class, input.ClassChanges); err != nil {
+		return nil, err
+	}
+
+	return class, nil
+}
+
+// update aplica as alterações validando o novo horário e a capacidade contra as inscrições
+// atuais. Alunos inscritos são avisados quando o horário muda.
+func (uc *UpdateClassUseCase) update(ctx context.Context, class *entity.Class, changes ClassChanges) error {
+	if !class.IsEditable() {
+		return ErrClassNotEditable
+	}
+
+	instructorID, err := changes.instructorID()
+	if err != nil {
+		return err
+	}
+
+	previousStart, previousEnd := class.StartTime, class.EndTime
+	applyChanges(class, changes, instructorID)
+	if changes.StartTime != nil && changes.EndTime == nil {
+		class.EndTime = class.StartTime.Add(previousEnd.Sub(previousStart))
+	}
+
+	if !class.EndTime.After(class.StartTime) {
+		return ErrInvalidSchedule
+	}
+	if err := class.Validate(); err != nil {
+		return err
+	}
+
+	rescheduled := !class.StartTime.Equal(previousStart) || !class.EndTime.Equal(previousEnd)
+	if rescheduled && !class.StartTime.After(time.Now()) {
+		return ErrClassInPast
+	}
+
+	if class.MaxCapacity < class.CurrentEnrolled {
+		return repository.ErrCapacityBelow
+	}
+
+	if err := uc.classRepo.UpdateDetails(ctx, class); err != nil {
+		return err
+	}
+
+	if rescheduled && class.CurrentEnrolled > 0 {
+		enrollments, err := uc.enrollmentRepo.FindActiveByClass(ctx, class.ID)
+		if err != nil {
+			return err
+		}
+
+		notifyStudents(ctx, uc.userRepo, uc.notifier, enrollments,
+			"Horário da aula alterado",
+			fmt.Sprintf("A aula %s foi remarcada para %s.", class.Title, entity.FormatLocalTime(class.StartTime)),
+		)
+	}
+
+	return nil
+}
+
+func applyChanges(class *entity.Class, changes ClassChanges, instructorID *primitive.ObjectID) {
+	if changes.Title != nil {
+		class.Title = *changes.Title
+	}
+	if changes.Description != nil {
+		class.Description = *changes.Description
+	}
+	if instructorID != nil {
+		class.InstructorID = *instructorID
+	}
+	if changes.InstructorName != nil {
+		class.InstructorName = *changes.InstructorName
+	}
+	if changes.StartTime != nil {
+		class.StartTime = *changes.StartTime
+	}
+	if changes.EndTime != nil {
+		class.EndTime = *changes.EndTime
+	}
+	if changes.MaxCapacity != nil {
+		class.MaxCapacity = *changes.MaxCapacity
+	}
+	if changes.PriceInCents != nil {
+		class.PriceInCents = *changes.PriceInCents
+	}
+}
@@ -0,0 +1,193 @@
+package class
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	ScopeThis      = "this"
+	ScopeFollowing = "following"
+)
+
+type UpdateSeriesOccurrenceUseCase struct {
+	seriesRepo  repository.ClassSeriesRepository
+	classRepo   repository.ClassRepository
+	updateClass *UpdateClassUseCase
+}
+
+func NewUpdateSeriesOccurrenceUseCase(
+	seriesRepo repository.ClassSeriesRepository,
+	classRepo repository.ClassRepository,
+	updateClass *UpdateClassUseCase,
+) *UpdateSeriesOccurrenceUseCase {
+	return &UpdateSeriesOccurrenceUseCase{
+		seriesRepo:  seriesRepo,
+		classRepo:   classRepo,
+		updateClass: updateClass,
+	}
+}
+
+type UpdateSeriesOccurrenceInput struct {
+	SeriesID        string       `json:"-"`
+	OccurrenceStart time.Time    `json:"occurrence_start"`
+	Scope           string       `json:"scope"`
+	Changes         ClassChanges `json:"changes"`
+}
+
+type UpdateSeriesOccurrenceOutput struct {
+	Series  *entity.ClassSeries `json:"series"`
+	Classes []*entity.Class     `json:"classes"`
+}
+
+func (uc *UpdateSeriesOccurrenceUseCase) Execute(ctx context.Context, input UpdateSeriesOccurrenceInput) (*UpdateSeriesOccurrenceOutput, error) {
+	seriesID, err := primitive.ObjectIDFromHex(input.SeriesID)
+	if err != nil {
+		return nil, ErrInvalidSeriesID
+	}
+
+	instructorID, err := input.Changes.instructorID()
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := uc.seriesRepo.FindByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch input.Scope {
+	case ScopeThis:
+		return uc.updateThis(ctx, series, input)
+	case ScopeFollowing:
+		return uc.updateFollowing(ctx, series, input, instructorID)
+	default:
+		return nil, ErrInvalidScope
+	}
+}
+
+func (uc *UpdateSeriesOccurrenceUseCase) updateThis(ctx context.Context, series *entity.ClassSeries, input UpdateSeriesOccurrenceInput) (*UpdateSeriesOccurrenceOutput, error) {
+	class, err := occurrenceClass(ctx, uc.classRepo, series, input.OccurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.updateClass.update(ctx, class, input.Changes); err != nil {
+		return nil, err
+	}
+
+	return &UpdateSeriesOccurrenceOutput{
+		Series:  series,
+		Classes: []*entity.Class{class},
+	}, nil
+}
+
+// updateFollowing divide a série: a original termina antes da ocorrência e uma nova série,
+// já com as alterações, assume esta e as próximas aulas.
+func (uc *UpdateSeriesOccurrenceUseCase) updateFollowing(ctx context.Context, series *entity.ClassSeries, input UpdateSeriesOccurrenceInput, instructorID *primitive.ObjectID) (*UpdateSeriesOccurrenceOutput, error) {
+	occurrence := input.OccurrenceStart
+	if !series.HasOccurrence(occurrence) {
+		return nil, ErrOccurrenceNotFound
+	}
+
+	loc := series.Location()
+	newStart := occurrence
+	if input.Changes.StartTime != nil {
+		newStart = *input.Changes.StartTime
+		if !sameDay(newStart.In(loc), occurrence.In(loc)) {
+			return nil, ErrScheduleChangesDay
+		}
+	}
+	shift := newStart.Sub(occurrence)
+
+	duration := series.Duration()
+	if input.Changes.EndTime != nil {
+		duration = input.Changes.EndTime.Sub(newStart)
+	}
+	if duration <= 0 {
+		return nil, ErrInvalidSchedule
+	}
+
+	following := series.Split(occurrence)
+	following.StartTime = newStart
+	following.DurationMinutes = int(duration / time.Minute)
+	if input.Changes.Title != nil {
+		following.Title = *input.Changes.Title
+	}
+	if input.Changes.Description != nil {
+		following.Description = *input.Changes.Description
+	}
+	if instructorID != nil {
+		following.InstructorID = *instructorID
+	}
+	if input.Changes.InstructorName != nil {
+		following.InstructorName = *input.Changes.InstructorName
+	}
+	if input.Changes.MaxCapacity != nil {
+		following.MaxCapacity = *input.Changes.MaxCapacity
+	}
+	if input.Changes.PriceInCents != nil {
+		following.PriceInCents = *input.Changes.PriceInCents
+	}
+
+	if !series.HasOccurrencesAfter(time.Now()) {
+		series.End()
+	}
+
+	var classes []*entity.Class
+	err := uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		if err := uc.seriesRepo.UpdateRecurrenceEnd(sc, series); err != nil {
+			return err
+		}
+
+		// As aulas são lidas na transação para que uma nova tentativa parta do estado gravado.
+		var err error
+		classes, err = uc.classRepo.FindBySeries(sc, series.ID, occurrence)
+		if err != nil {
+			return err
+		}
+		if err := uc.seriesRepo.Create(sc, following); err != nil {
+			return err
+		}
+
+		for _, class := range classes {
+			start := class.StartTime.Add(shift)
+			if class.OccurrenceStart != nil {
+				moved := class.OccurrenceStart.Add(shift)
+				class.OccurrenceStart = &moved
+			}
+			class.SeriesID = &following.ID
+			applyChanges(class, input.Changes, instructorID)
+			class.StartTime = start
+			class.EndTime = start.Add(duration)
+			if err := class.Validate(); err != nil {
+				return err
+			}
+
+			if err := uc.classRepo.UpdateDetails(sc, class); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateSeriesOccurrenceOutput{
+		Series:  following,
+		Classes: classes,
+	}, nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,72 @@
+package class
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/event"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WatchClassAvailabilityUseCase struct {
+	classRepo repository.ClassRepository
+	broker    event.AvailabilityBroker
+}
+
+func NewWatchClassAvailabilityUseCase(
+	classRepo repository.ClassRepository,
+	broker event.AvailabilityBroker,
+) *WatchClassAvailabilityUseCase {
+	return &WatchClassAvailabilityUseCase{
+		classRepo: classRepo,
+		broker:    broker,
+	}
+}
+
+type WatchClassAvailabilityInput struct {
+	ClassID     string
+	LastEventID uint64
+}
+
+// AvailabilitySubscription entrega a ocupação atual (Initial), quando o cliente ainda não
+// a conhece, seguida das próximas mudanças em Events. Close deve ser chamado ao final.
+type AvailabilitySubscription struct {
+	Initial *event.ClassAvailability
+	Events  <-chan event.ClassAvailability
+	Close   func()
+}
+
+func (uc *WatchClassAvailabilityUseCase) Execute(ctx context.Context, input WatchClassAvailabilityInput) (*AvailabilitySubscription, error) {
+	classID, err := primitive.ObjectIDFromHex(input.ClassID)
+	if err != nil {
+		return nil, ErrInvalidClassID
+	}
+
+	// Assina antes de ler a aula para não perder mudanças entre a leitura e a assinatura.
+	events, unsubscribe := uc.broker.Subscribe(classID)
+	lastID := uc.broker.LastID()
+
+	class, err := uc.classRepo.FindByID(ctx, classID)
+	if err != nil {
+		unsubscribe()
+		return nil, err
+	}
+	if class.Status == entity.ClassStatusDraft {
+		unsubscribe()
+		return nil, repository.ErrClassNotFound
+	}
+
+	subscription := &AvailabilitySubscription{
+		Events: events,
+		Close:  unsubscribe,
+	}
+
+	if input.LastEventID == 0 || input.LastEventID < lastID {
+		initial := event.NewClassAvailability(class)
+		initial.ID = lastID
+		subscription.Initial = &initial
+	}
+
+	return subscription, nil
+}
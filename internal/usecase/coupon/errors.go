@@ -0,0 +1,9 @@
+package coupon
+
+import "errors"
+
+var (
+	ErrInvalidCouponID    = errors.New("coupon_id inválido")
+	ErrInvalidCoupon      = errors.New("cupom inválido: verifique código, tipo, valor, limites e período de validade")
+	ErrInvalidRestriction = errors.New("class_ids e instructor_ids devem conter ids válidos")
+)
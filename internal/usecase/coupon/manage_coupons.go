@@ -0,0 +1,175 @@
+package coupon
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateCouponUseCase struct {
+	couponRepo repository.CouponRepository
+}
+
+func NewCreateCouponUseCase(couponRepo repository.CouponRepository) *CreateCouponUseCase {
+	return &CreateCouponUseCase{
+		couponRepo: couponRepo,
+	}
+}
+
+// CreateCouponInput descreve um cupom. Value é o percentual em cupons percent e o valor em
+// centavos em cupons fixed; é ignorado em cupons first_class_free.
+type CreateCouponInput struct {
+	Code           string     `json:"code"`
+	Type           string     `json:"type"`
+	Value          int64      `json:"value"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	MaxPerUser     int        `json:"max_per_user"`
+	ClassIDs       []string   `json:"class_ids"`
+	InstructorIDs  []string   `json:"instructor_ids"`
+}
+
+func (uc *CreateCouponUseCase) Execute(ctx context.Context, input CreateCouponInput) (*entity.Coupon, error) {
+	classIDs, err := parseIDs(input.ClassIDs)
+	if err != nil {
+		return nil, err
+	}
+	instructorIDs, err := parseIDs(input.InstructorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon := entity.NewCoupon(input.Code, input.Type, input.Value)
+	coupon.ValidFrom = input.ValidFrom
+	coupon.ValidUntil = input.ValidUntil
+	coupon.MaxRedemptions = input.MaxRedemptions
+	if input.MaxPerUser > 0 || !coupon.IsFirstClassOnly() {
+		coupon.MaxPerUser = input.MaxPerUser
+	}
+	coupon.ClassIDs = classIDs
+	coupon.InstructorIDs = instructorIDs
+
+	if !coupon.IsValid() {
+		return nil, ErrInvalidCoupon
+	}
+
+	if err := uc.couponRepo.Create(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+type UpdateCouponUseCase struct {
+	couponRepo repository.CouponRepository
+}
+
+func NewUpdateCouponUseCase(couponRepo repository.CouponRepository) *UpdateCouponUseCase {
+	return &UpdateCouponUseCase{
+		couponRepo: couponRepo,
+	}
+}
+
+// UpdateCouponInput altera apenas os campos informados. Listas vazias removem a restrição
+// de aulas ou instrutores. Resgates já feitos não são afetados.
+type UpdateCouponInput struct {
+	CouponID       string     `json:"-"`
+	Code           *string    `json:"code"`
+	Value          *int64     `json:"value"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	MaxRedemptions *int       `json:"max_redemptions"`
+	MaxPerUser     *int       `json:"max_per_user"`
+	ClassIDs       *[]string  `json:"class_ids"`
+	InstructorIDs  *[]string  `json:"instructor_ids"`
+	Active         *bool      `json:"active"`
+}
+
+func (uc *UpdateCouponUseCase) Execute(ctx context.Context, input UpdateCouponInput) (*entity.Coupon, error) {
+	id, err := primitive.ObjectIDFromHex(input.CouponID)
+	if err != nil {
+		return nil, ErrInvalidCouponID
+	}
+
+	coupon, err := uc.couponRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Code != nil {
+		coupon.Code = entity.NormalizeCouponCode(*input.Code)
+	}
+	if input.Value != nil {
+		coupon.Value = *input.Value
+	}
+	if input.ValidFrom != nil {
+		coupon.ValidFrom = input.ValidFrom
+	}
+	if input.ValidUntil != nil {
+		coupon.ValidUntil = input.ValidUntil
+	}
+	if input.MaxRedemptions != nil {
+		coupon.MaxRedemptions = *input.MaxRedemptions
+	}
+	if input.MaxPerUser != nil {
+		coupon.MaxPerUser = *input.MaxPerUser
+	}
+	if input.ClassIDs != nil {
+		if coupon.ClassIDs, err = parseIDs(*input.ClassIDs); err != nil {
+			return nil, err
+		}
+	}
+	if input.InstructorIDs != nil {
+		if coupon.InstructorIDs, err = parseIDs(*input.InstructorIDs); err != nil {
+			return nil, err
+		}
+	}
+	if input.Active != nil {
+		coupon.Active = *input.Active
+	}
+
+	if !coupon.IsValid() {
+		return nil, ErrInvalidCoupon
+	}
+
+	coupon.UpdatedAt = time.Now()
+	if err := uc.couponRepo.Update(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+type ListCouponsUseCase struct {
+	couponRepo repository.CouponRepository
+}
+
+func NewListCouponsUseCase(couponRepo repository.CouponRepository) *ListCouponsUseCase {
+	return &ListCouponsUseCase{
+		couponRepo: couponRepo,
+	}
+}
+
+func (uc *ListCouponsUseCase) Execute(ctx context.Context) ([]*entity.Coupon, error) {
+	return uc.couponRepo.FindAll(ctx)
+}
+
+func parseIDs(hexIDs []string) ([]primitive.ObjectID, error) {
+	if len(hexIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(hexIDs))
+	for _, hexID := range hexIDs {
+		id, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil {
+			return nil, ErrInvalidRestriction
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,23 @@
+package credit
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+)
+
+// ConsumeCredit paga a inscrição com um crédito do lote que vence primeiro, confirma a
+// inscrição e registra o débito no extrato. Retorna repository.ErrNoCredits se o aluno não
+// tiver créditos válidos. Deve ser executado na transação que cria a inscrição.
+func ConsumeCredit(ctx context.Context, creditRepo repository.CreditRepository, enrollment *entity.Enrollment, now time.Time) error {
+	lot, err := creditRepo.Consume(ctx, enrollment.UserID, now)
+	if err != nil {
+		return err
+	}
+	enrollment.ConfirmWithCredit(lot.ID)
+
+	entry := entity.NewCreditEntry(enrollment.UserID, lot.ID, entity.CreditEntryConsume, -1, &enrollment.ID)
+	return creditRepo.AddEntry(ctx, entry)
+}
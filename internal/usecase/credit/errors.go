@@ -0,0 +1,13 @@
+package credit
+
+import "errors"
+
+var (
+	ErrInvalidPackID  = errors.New("pack_id inválido")
+	ErrInvalidUserID  = errors.New("user_id inválido")
+	ErrInvalidPack    = errors.New("pacote inválido: nome, quantidade de créditos e preço são obrigatórios")
+	ErrPackInactive   = errors.New("pacote de créditos indisponível")
+	ErrUserNotFound   = errors.New("usuário não encontrado")
+	ErrNotStudent     = errors.New("apenas estudantes podem comprar pacotes de créditos")
+	ErrCheckoutFailed = errors.New("não foi possível criar o checkout do pacote, tente novamente")
+)
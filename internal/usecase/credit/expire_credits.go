@@ -0,0 +1,54 @@
+package credit
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const expireBatchSize = 100
+
+// ExpireCreditsUseCase encerra os lotes vencidos e lança no extrato os créditos perdidos.
+type ExpireCreditsUseCase struct {
+	creditRepo repository.CreditRepository
+}
+
+func NewExpireCreditsUseCase(creditRepo repository.CreditRepository) *ExpireCreditsUseCase {
+	return &ExpireCreditsUseCase{
+		creditRepo: creditRepo,
+	}
+}
+
+func (uc *ExpireCreditsUseCase) Execute(ctx context.Context) error {
+	now := time.Now()
+
+	for i := 0; i < expireBatchSize; i++ {
+		lot, err := uc.creditRepo.ExpireNext(ctx, now)
+		if err != nil {
+			return err
+		}
+		if lot == nil {
+			return nil
+		}
+		if lot.Remaining == 0 {
+			continue
+		}
+
+		entry := entity.NewCreditEntry(lot.UserID, lot.ID, entity.CreditEntryExpire, -lot.Remaining, nil)
+		if err := uc.creditRepo.AddEntry(ctx, entry); err != nil {
+			return err
+		}
+
+		logger.Info("Créditos expirados",
+			zap.String("lot_id", lot.ID.Hex()),
+			zap.String("user_id", lot.UserID.Hex()),
+			zap.Int("credits", lot.Remaining),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package credit
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const creditEntriesLimit = 50
+
+type GetMyCreditsUseCase struct {
+	creditRepo repository.CreditRepository
+}
+
+func NewGetMyCreditsUseCase(creditRepo repository.CreditRepository) *GetMyCreditsUseCase {
+	return &GetMyCreditsUseCase{
+		creditRepo: creditRepo,
+	}
+}
+
+// MyCreditsOutput traz o saldo disponível, os lotes ainda válidos e os lançamentos mais
+// recentes do extrato.
+type MyCreditsOutput struct {
+	Balance int                   `json:"balance"`
+	Lots    []*entity.CreditLot   `json:"lots"`
+	Entries []*entity.CreditEntry `json:"entries"`
+}
+
+func (uc *GetMyCreditsUseCase) Execute(ctx context.Context, userID string) (*MyCreditsOutput, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	lots, err := uc.creditRepo.FindLotsByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.creditRepo.FindEntriesByUser(ctx, id, creditEntriesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	output := &MyCreditsOutput{
+		Lots:    []*entity.CreditLot{},
+		Entries: entries,
+	}
+	for _, lot := range lots {
+		if lot.Status != entity.CreditLotStatusActive || !lot.ExpiresAt.After(now) {
+			continue
+		}
+		output.Balance += lot.Remaining
+		output.Lots = append(output.Lots, lot)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,115 @@
+package credit
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateCreditPackUseCase struct {
+	packRepo repository.CreditPackRepository
+}
+
+func NewCreateCreditPackUseCase(packRepo repository.CreditPackRepository) *CreateCreditPackUseCase {
+	return &CreateCreditPackUseCase{
+		packRepo: packRepo,
+	}
+}
+
+type CreateCreditPackInput struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Credits      int    `json:"credits"`
+	PriceInCents int64  `json:"price_in_cents"`
+}
+
+func (uc *CreateCreditPackUseCase) Execute(ctx context.Context, input CreateCreditPackInput) (*entity.CreditPack, error) {
+	if input.Name == "" || input.Credits <= 0 || input.PriceInCents <= 0 {
+		return nil, ErrInvalidPack
+	}
+
+	pack := entity.NewCreditPack(input.Name, input.Description, input.Credits, input.PriceInCents)
+	if err := uc.packRepo.Create(ctx, pack); err != nil {
+		return nil, err
+	}
+
+	return pack, nil
+}
+
+type UpdateCreditPackUseCase struct {
+	packRepo repository.CreditPackRepository
+}
+
+func NewUpdateCreditPackUseCase(packRepo repository.CreditPackRepository) *UpdateCreditPackUseCase {
+	return &UpdateCreditPackUseCase{
+		packRepo: packRepo,
+	}
+}
+
+// UpdateCreditPackInput altera apenas os campos informados. Compras já feitas mantêm a
+// quantidade de créditos e o preço da época.
+type UpdateCreditPackInput struct {
+	PackID       string  `json:"-"`
+	Name         *string `json:"name"`
+	Description  *string `json:"description"`
+	Credits      *int    `json:"credits"`
+	PriceInCents *int64  `json:"price_in_cents"`
+	Active       *bool   `json:"active"`
+}
+
+func (uc *UpdateCreditPackUseCase) Execute(ctx context.Context, input UpdateCreditPackInput) (*entity.CreditPack, error) {
+	id, err := primitive.ObjectIDFromHex(input.PackID)
+	if err != nil {
+		return nil, ErrInvalidPackID
+	}
+
+	pack, err := uc.packRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		pack.Name = *input.Name
+	}
+	if input.Description != nil {
+		pack.Description = *input.Description
+	}
+	if input.Credits != nil {
+		pack.Credits = *input.Credits
+	}
+	if input.PriceInCents != nil {
+		pack.PriceInCents = *input.PriceInCents
+	}
+	if input.Active != nil {
+		pack.Active = *input.Active
+	}
+
+	if pack.Name == "" || pack.Credits <= 0 || pack.PriceInCents <= 0 {
+		return nil, ErrInvalidPack
+	}
+
+	pack.UpdatedAt = time.Now()
+	if err := uc.packRepo.Update(ctx, pack); err != nil {
+		return nil, err
+	}
+
+	return pack, nil
+}
+
+type ListCreditPacksUseCase struct {
+	packRepo repository.CreditPackRepository
+}
+
+func NewListCreditPacksUseCase(packRepo repository.CreditPackRepository) *ListCreditPacksUseCase {
+	return &ListCreditPacksUseCase{
+		packRepo: packRepo,
+	}
+}
+
+// Execute lista os pacotes à venda; includeInactive é usado pela equipe do estúdio.
+func (uc *ListCreditPacksUseCase) Execute(ctx context.Context, includeInactive bool) ([]*entity.CreditPack, error) {
+	return uc.packRepo.FindAll(ctx, !includeInactive)
+}
@@ -0,0 +1,145 @@
+package credit
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// PurchaseCreditPackUseCase registra a compra de um pacote e cria o checkout no gateway.
+// Como não há vaga reservada, o checkout é criado na própria requisição; os créditos são
+// liberados pelo webhook quando o pagamento é aprovado.
+type PurchaseCreditPackUseCase struct {
+	classRepo      repository.ClassRepository
+	packRepo       repository.CreditPackRepository
+	purchaseRepo   repository.CreditPurchaseRepository
+	paymentRepo    repository.PaymentRepository
+	userRepo       repository.UserRepository
+	paymentGateway gateway.PaymentGateway
+	config         *config.Config
+}
+
+func NewPurchaseCreditPackUseCase(
+	classRepo repository.ClassRepository,
+	packRepo repository.CreditPackRepository,
+	purchaseRepo repository.CreditPurchaseRepository,
+	paymentRepo repository.PaymentRepository,
+	userRepo repository.UserRepository,
+	paymentGateway gateway.PaymentGateway,
+	config *config.Config,
+) *PurchaseCreditPackUseCase {
+	return &PurchaseCreditPackUseCase{
+		classRepo:      classRepo,
+		packRepo:       packRepo,
+		purchaseRepo:   purchaseRepo,
+		paymentRepo:    paymentRepo,
+		userRepo:       userRepo,
+		paymentGateway: paymentGateway,
+		config:         config,
+	}
+}
+
+type PurchaseCreditPackInput struct {
+	PackID string
+	UserID string
+}
+
+type PurchaseCreditPackOutput struct {
+	Purchase   *entity.CreditPurchase `json:"purchase"`
+	Payment    *entity.Payment        `json:"payment"`
+	PaymentURL string                 `json:"payment_url"`
+}
+
+func (uc *PurchaseCreditPackUseCase) Execute(ctx context.Context, input PurchaseCreditPackInput) (*PurchaseCreditPackOutput, error) {
+	packID, err := primitive.ObjectIDFromHex(input.PackID)
+	if err != nil {
+		return nil, ErrInvalidPackID
+	}
+
+	userID, err := primitive.ObjectIDFromHex(input.UserID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsStudent() {
+		return nil, ErrNotStudent
+	}
+
+	pack, err := uc.packRepo.FindByID(ctx, packID)
+	if err != nil {
+		return nil, err
+	}
+	if !pack.Active {
+		return nil, ErrPackInactive
+	}
+
+	purchase := entity.NewCreditPurchase(userID, pack)
+	paymentEntity := entity.NewCreditPurchasePayment(purchase)
+
+	err = uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		if err := uc.purchaseRepo.Create(sc, purchase); err != nil {
+			return err
+		}
+		return uc.paymentRepo.Create(sc, paymentEntity)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	checkout, err := uc.paymentGateway.CreateCheckout(ctx, &gateway.CheckoutRequest{
+		Title:         pack.Name,
+		Description:   pack.Description,
+		AmountInCents: paymentEntity.AmountInCents,
+		ExternalRef:   paymentEntity.ExternalRef(),
+		NotifyURL:     uc.config.MercadoPago.NotifyURL,
+		BackURL:       uc.config.MercadoPago.BackURL,
+	})
+	if err != nil {
+		logger.Error("Erro ao criar checkout do pacote de créditos",
+			zap.String("purchase_id", purchase.ID.Hex()),
+			zap.Error(err),
+		)
+		uc.abandon(ctx, purchase, paymentEntity)
+		return nil, ErrCheckoutFailed
+	}
+
+	paymentEntity.SetPreference(checkout.ID, checkout.CheckoutURL)
+	if err := uc.paymentRepo.Update(ctx, paymentEntity); err != nil {
+		return nil, err
+	}
+
+	return &PurchaseCreditPackOutput{
+		Purchase:   purchase,
+		Payment:    paymentEntity,
+		PaymentURL: checkout.CheckoutURL,
+	}, nil
+}
+
+func (uc *PurchaseCreditPackUseCase) abandon(ctx context.Context, purchase *entity.CreditPurchase, paymentEntity *entity.Payment) {
+	purchase.MarkFailed()
+	paymentEntity.MarkCancelled()
+
+	err := uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		if err := uc.purchaseRepo.Update(sc, purchase); err != nil {
+			return err
+		}
+		return uc.paymentRepo.Update(sc, paymentEntity)
+	})
+	if err != nil {
+		logger.Error("Erro ao cancelar compra de créditos sem checkout",
+			zap.String("purchase_id", purchase.ID.Hex()),
+			zap.Error(err),
+		)
+	}
+}
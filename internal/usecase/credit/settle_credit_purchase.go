@@ -0,0 +1,91 @@
+package credit
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// SettleCreditPurchaseUseCase aplica à compra de créditos o status do pagamento recebido
+// pelo webhook: libera o lote quando aprovado e revoga o saldo restante quando o pagamento
+// é estornado ou contestado. Deve rodar na mesma transação que atualiza o pagamento.
+type SettleCreditPurchaseUseCase struct {
+	purchaseRepo repository.CreditPurchaseRepository
+	creditRepo   repository.CreditRepository
+	config       *config.Config
+}
+
+func NewSettleCreditPurchaseUseCase(
+	purchaseRepo repository.CreditPurchaseRepository,
+	creditRepo repository.CreditRepository,
+	config *config.Config,
+) *SettleCreditPurchaseUseCase {
+	return &SettleCreditPurchaseUseCase{
+		purchaseRepo: purchaseRepo,
+		creditRepo:   creditRepo,
+		config:       config,
+	}
+}
+
+func (uc *SettleCreditPurchaseUseCase) Execute(ctx context.Context, paymentEntity *entity.Payment) error {
+	purchase, err := uc.purchaseRepo.FindByID(ctx, *paymentEntity.CreditPurchaseID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case paymentEntity.IsApproved() && purchase.IsPending():
+		lot := entity.NewPurchasedCreditLot(purchase, time.Now().Add(uc.config.Credit.Validity))
+		if err := uc.creditRepo.CreateLot(ctx, lot); err != nil {
+			return err
+		}
+		entry := entity.NewCreditEntry(lot.UserID, lot.ID, entity.CreditEntryPurchase, lot.Total, nil)
+		if err := uc.creditRepo.AddEntry(ctx, entry); err != nil {
+			return err
+		}
+
+		purchase.MarkPaid()
+		logger.Info("Créditos liberados",
+			zap.String("purchase_id", purchase.ID.Hex()),
+			zap.String("user_id", purchase.UserID.Hex()),
+			zap.Int("credits", lot.Total),
+		)
+
+	case paymentEntity.IsFailed() && purchase.IsPending():
+		purchase.MarkFailed()
+
+	case paymentEntity.IsReversed() && purchase.IsPaid():
+		lot, err := uc.creditRepo.FindLotByPurchaseID(ctx, purchase.ID)
+		if err != nil {
+			return err
+		}
+		if lot != nil {
+			revoked, err := uc.creditRepo.Revoke(ctx, lot.ID)
+			if err != nil {
+				return err
+			}
+			if revoked > 0 {
+				entry := entity.NewCreditEntry(lot.UserID, lot.ID, entity.CreditEntryRevoke, -revoked, nil)
+				if err := uc.creditRepo.AddEntry(ctx, entry); err != nil {
+					return err
+				}
+			}
+		}
+
+		purchase.MarkRefunded()
+		logger.Info("Créditos revogados por estorno do pacote",
+			zap.String("purchase_id", purchase.ID.Hex()),
+			zap.String("status", paymentEntity.Status),
+		)
+
+	default:
+		return nil
+	}
+
+	return uc.purchaseRepo.Update(ctx, purchase)
+}
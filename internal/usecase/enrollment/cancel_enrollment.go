@@ -2,51 +2,241 @@ package enrollment
 
 import (
 	"context"
-	"fmt"
+	"time"
 
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/payment"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/waitlist"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
 )
 
+const (
+	RefundStatusNone     = "none"
+	RefundStatusRefunded = "refunded"
+	RefundStatusPending  = "pending"
+)
+
+// CancelEnrollmentUseCase cancela uma inscrição confirmada aplicando a política de
+// cancelamento: o reembolso é calculado a partir da antecedência em relação ao início da
+// aula e gravado como estorno pendente na mesma transação que libera a vaga; o envio ao
+// gateway é tentado logo depois e, se falhar, repetido pelo worker de estornos.
 type CancelEnrollmentUseCase struct {
 	enrollmentRepo repository.EnrollmentRepository
 	classRepo      repository.ClassRepository
+	paymentRepo    repository.PaymentRepository
+	creditRepo     repository.CreditRepository
+	membershipRepo repository.MembershipRepository
+	refundPayment  *payment.RefundPaymentUseCase
+	processRefund  *payment.ProcessRefundOutboxUseCase
+	releaseSeat    *waitlist.ReleaseSeatUseCase
+	policy         entity.CancellationPolicy
+	creditValidity time.Duration
 }
 
 func NewCancelEnrollmentUseCase(
 	enrollmentRepo repository.EnrollmentRepository,
 	classRepo repository.ClassRepository,
+	paymentRepo repository.PaymentRepository,
+	creditRepo repository.CreditRepository,
+	membershipRepo repository.MembershipRepository,
+	refundPayment *payment.RefundPaymentUseCase,
+	processRefund *payment.ProcessRefundOutboxUseCase,
+	releaseSeat *waitlist.ReleaseSeatUseCase,
+	config *config.Config,
 ) *CancelEnrollmentUseCase {
 	return &CancelEnrollmentUseCase{
 		enrollmentRepo: enrollmentRepo,
 		classRepo:      classRepo,
+		paymentRepo:    paymentRepo,
+		creditRepo:     creditRepo,
+		membershipRepo: membershipRepo,
+		refundPayment:  refundPayment,
+		processRefund:  processRefund,
+		releaseSeat:    releaseSeat,
+		policy: entity.CancellationPolicy{
+			FullRefundWindow:  config.Enrollment.CancellationRefundWindow,
+			LateRefundPercent: config.Enrollment.LateCancelRefundPercent,
+			LateCredit:        config.Enrollment.LateCancelAction == "credit",
+		},
+		creditValidity: config.Credit.Validity,
 	}
 }
 
-func (uc *CancelEnrollmentUseCase) Execute(ctx context.Context, enrollmentID string) error {
-	id, err := primitive.ObjectIDFromHex(enrollmentID)
+// CancelEnrollmentInput identifica a inscrição e o usuário autenticado. Estudantes só
+// cancelam as próprias inscrições; administradores e instrutores cancelam qualquer uma.
+type CancelEnrollmentInput struct {
+	EnrollmentID string
+	UserID       string
+	Role         entity.UserRole
+}
+
+type CancelEnrollmentOutput struct {
+	Enrollment     *entity.Enrollment `json:"enrollment"`
+	Late           bool               `json:"late"`
+	RefundInCents  int64              `json:"refund_in_cents"`
+	RefundStatus   string             `json:"refund_status"`
+	CreditRestored bool               `json:"credit_restored"`
+	ClassRestored  bool               `json:"membership_class_restored"`
+}
+
+func (uc *CancelEnrollmentUseCase) Execute(ctx context.Context, input CancelEnrollmentInput) (*CancelEnrollmentOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.EnrollmentID)
 	if err != nil {
-		return fmt.Errorf("enrollment_id inválido")
+		return nil, ErrInvalidEnrollmentID
 	}
 
-	enrollment, err := uc.enrollmentRepo.FindByID(ctx, id)
+	actorID, err := primitive.ObjectIDFromHex(input.UserID)
 	if err != nil {
-		return err
+		return nil, ErrInvalidUserID
 	}
+	staff := input.Role == entity.RoleAdmin || input.Role == entity.RoleInstructor
+
+	var (
+		enrollment    *entity.Enrollment
+		paymentEntity *entity.Payment
+		decision      *entity.CancellationDecision
+		refundMessage *entity.OutboxMessage
+		credited      bool
+		released      bool
+	)
+
+	err = uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		credited, released, refundMessage = false, false, nil
+
+		enrollment, err = uc.enrollmentRepo.FindByID(sc, id)
+		if err != nil {
+			return err
+		}
+
+		if !staff && enrollment.UserID != actorID {
+			return repository.ErrEnrollmentNotFound
+		}
+
+		if !enrollment.IsConfirmed() {
+			return ErrNotCancellable
+		}
+
+		class, err := uc.classRepo.FindByID(sc, enrollment.ClassID)
+		if err != nil {
+			return err
+		}
+
+		paymentEntity, err = uc.paymentRepo.FindByEnrollmentID(sc, enrollment.ID)
+		if err != nil {
+			return err
+		}
+
+		var paid int64
+		if paymentEntity != nil {
+			paid = paymentEntity.RefundableInCents()
+		}
+
+		now := time.Now()
+		decision, err = uc.policy.Evaluate(class.StartTime, now, paid)
+		if err != nil {
+			return err
+		}
+		// Cancelamentos feitos pelo estúdio não penalizam o aluno.
+		if staff {
+			decision = &entity.CancellationDecision{RefundInCents: paid}
+		}
 
-	if enrollment.Status != "confirmed" {
-		return fmt.Errorf("apenas inscrições confirmadas podem ser canceladas")
+		switch {
+		case enrollment.PaidWithMembership() && (staff || !decision.Late):
+			// A aula só volta ao saldo se a inscrição foi feita no período vigente.
+			released, err = uc.membershipRepo.ReleaseClass(sc, *enrollment.MembershipID, enrollment.EnrolledAt)
+			if err != nil {
+				return err
+			}
+		case enrollment.PaidWithCredit() && (staff || !decision.Late):
+			credited, err = uc.restoreCredit(sc, enrollment, now)
+			if err != nil {
+				return err
+			}
+		case decision.Credit && paid > 0:
+			if err := uc.grantCredit(sc, enrollment, now); err != nil {
+				return err
+			}
+			credited = true
+		}
+
+		if decision.RefundInCents > 0 {
+			_, refundMessage, err = uc.refundPayment.Schedule(sc, paymentEntity, decision.RefundInCents, "cancelamento da inscrição", &actorID)
+			if err != nil {
+				return err
+			}
+		}
+
+		enrollment.CancelBy(actorID)
+		if err := uc.enrollmentRepo.Update(sc, enrollment); err != nil {
+			return err
+		}
+
+		return uc.releaseSeat.Execute(sc, enrollment.ClassID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	enrollment.Cancel()
-	if err := uc.enrollmentRepo.Update(ctx, enrollment); err != nil {
-		return err
+	output := &CancelEnrollmentOutput{
+		Enrollment:     enrollment,
+		Late:           decision.Late,
+		RefundInCents:  decision.RefundInCents,
+		RefundStatus:   RefundStatusNone,
+		CreditRestored: credited,
+		ClassRestored:  released,
 	}
 
-	if err := uc.classRepo.DecrementEnrollment(ctx, enrollment.ClassID); err != nil {
-		return err
+	if refundMessage != nil {
+		output.RefundStatus = RefundStatusRefunded
+		if _, err := uc.processRefund.Process(ctx, refundMessage); err != nil {
+			logger.Warn("Estorno da inscrição cancelada não concluído, será repetido em segundo plano",
+				zap.String("enrollment_id", enrollment.ID.Hex()),
+				zap.String("payment_id", paymentEntity.ID.Hex()),
+				zap.Int64("refund_in_cents", decision.RefundInCents),
+				zap.Error(err),
+			)
+			output.RefundStatus = RefundStatusPending
+		}
 	}
 
-	return nil
+	logger.Info("Inscrição cancelada",
+		zap.String("enrollment_id", enrollment.ID.Hex()),
+		zap.String("cancelled_by", actorID.Hex()),
+		zap.Bool("late", decision.Late),
+		zap.Int64("refund_in_cents", decision.RefundInCents),
+		zap.String("refund_status", output.RefundStatus),
+		zap.Bool("credit_restored", credited),
+		zap.Bool("membership_class_restored", released),
+	)
+
+	return output, nil
 }
 
+// restoreCredit devolve ao lote o crédito usado na inscrição. Lotes já vencidos não são
+// reabertos.
+func (uc *CancelEnrollmentUseCase) restoreCredit(ctx context.Context, enrollment *entity.Enrollment, now time.Time) (bool, error) {
+	restored, err := uc.creditRepo.Restore(ctx, *enrollment.CreditLotID, now)
+	if err != nil || !restored {
+		return false, err
+	}
+
+	entry := entity.NewCreditEntry(enrollment.UserID, *enrollment.CreditLotID, entity.CreditEntryRestore, 1, &enrollment.ID)
+	return true, uc.creditRepo.AddEntry(ctx, entry)
+}
+
+// grantCredit compensa um cancelamento tardio pago em dinheiro com um crédito de aula.
+func (uc *CancelEnrollmentUseCase) grantCredit(ctx context.Context, enrollment *entity.Enrollment, now time.Time) error {
+	lot := entity.NewCreditLot(enrollment.UserID, 1, now.Add(uc.creditValidity))
+	if err := uc.creditRepo.CreateLot(ctx, lot); err != nil {
+		return err
+	}
+
+	entry := entity.NewCreditEntry(enrollment.UserID, lot.ID, entity.CreditEntryGrant, 1, &enrollment.ID)
+	return uc.creditRepo.AddEntry(ctx, entry)
+}
@@ -2,23 +2,38 @@ package enrollment
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"math/rand/v2"
+	"time"
 
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
-	"github.com/marcelobritu/isayoga-api/internal/infrastructure/payment"
+	creditUC "github.com/marcelobritu/isayoga-api/internal/usecase/credit"
+	membershipUC "github.com/marcelobritu/isayoga-api/internal/usecase/membership"
 	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	maxEnrollAttempts    = 5
+	enrollRetryBaseDelay = 20 * time.Millisecond
+	enrollRetryMaxDelay  = 500 * time.Millisecond
 )
 
 type EnrollStudentUseCase struct {
-	classRepo      repository.ClassRepository
-	enrollmentRepo repository.EnrollmentRepository
-	paymentRepo    repository.PaymentRepository
-	userRepo       repository.UserRepository
-	mercadoPago    *payment.MercadoPagoClient
-	config         *config.Config
+	classRepo       repository.ClassRepository
+	enrollmentRepo  repository.EnrollmentRepository
+	paymentRepo     repository.PaymentRepository
+	userRepo        repository.UserRepository
+	outboxRepo      repository.OutboxRepository
+	creditRepo      repository.CreditRepository
+	membershipRepo  repository.MembershipRepository
+	couponRepo      repository.CouponRepository
+	processCheckout *ProcessCheckoutOutboxUseCase
+	config          *config.Config
 }
 
 func NewEnrollStudentUseCase(
@@ -26,47 +41,86 @@ func NewEnrollStudentUseCase(
 	enrollmentRepo repository.EnrollmentRepository,
 	paymentRepo repository.PaymentRepository,
 	userRepo repository.UserRepository,
-	mercadoPago *payment.MercadoPagoClient,
+	outboxRepo repository.OutboxRepository,
+	creditRepo repository.CreditRepository,
+	membershipRepo repository.MembershipRepository,
+	couponRepo repository.CouponRepository,
+	processCheckout *ProcessCheckoutOutboxUseCase,
 	config *config.Config,
 ) *EnrollStudentUseCase {
 	return &EnrollStudentUseCase{
-		classRepo:      classRepo,
-		enrollmentRepo: enrollmentRepo,
-		paymentRepo:    paymentRepo,
-		userRepo:       userRepo,
-		mercadoPago:    mercadoPago,
-		config:         config,
+		classRepo:       classRepo,
+		enrollmentRepo:  enrollmentRepo,
+		paymentRepo:     paymentRepo,
+		userRepo:        userRepo,
+		outboxRepo:      outboxRepo,
+		creditRepo:      creditRepo,
+		membershipRepo:  membershipRepo,
+		couponRepo:      couponRepo,
+		processCheckout: processCheckout,
+		config:          config,
 	}
 }
 
+// EnrollStudentInput identifica o aluno e quem está fazendo a inscrição (ActorID, obtido
+// do token). Quando são diferentes, trata-se de uma inscrição em nome do aluno, permitida
+// apenas a administradores e instrutores. Alunos com assinatura ativa e aulas disponíveis
+// no período não pagam pela aula; com UseCredit a aula é paga com um crédito de pacote.
+// Nos dois casos a inscrição já nasce confirmada. CouponCode aplica um cupom de desconto
+// ao preço da aula; um cupom que zera o preço também confirma a inscrição sem checkout.
+// PaymentMethod escolhe entre o Checkout Pro (checkout, padrão) e o Pix (pix), que devolve
+// o QR code na resposta.
 type EnrollStudentInput struct {
-	UserID  string `json:"user_id"`
-	ClassID string `json:"class_id"`
+	UserID        string          `json:"user_id"`
+	ClassID       string          `json:"class_id"`
+	UseCredit     bool            `json:"use_credit"`
+	CouponCode    string          `json:"coupon_code"`
+	PaymentMethod string          `json:"payment_method"`
+	ActorID       string          `json:"-"`
+	ActorRole     entity.UserRole `json:"-"`
 }
 
 type EnrollStudentOutput struct {
-	Enrollment *entity.Enrollment `json:"enrollment"`
-	Payment    *entity.Payment    `json:"payment"`
-	PaymentURL string             `json:"payment_url"`
+	Enrollment      *entity.Enrollment `json:"enrollment"`
+	Payment         *entity.Payment    `json:"payment"`
+	PaymentURL      string             `json:"payment_url"`
+	Pix             *entity.PixCharge  `json:"pix,omitempty"`
+	CheckoutPending bool               `json:"checkout_pending"`
+	ExpiresAt       *time.Time         `json:"expires_at,omitempty"`
 }
 
 func (uc *EnrollStudentUseCase) Execute(ctx context.Context, input EnrollStudentInput) (*EnrollStudentOutput, error) {
 	userID, err := primitive.ObjectIDFromHex(input.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("user_id inválido")
+		return nil, ErrInvalidUserID
 	}
 
 	classID, err := primitive.ObjectIDFromHex(input.ClassID)
 	if err != nil {
-		return nil, fmt.Errorf("class_id inválido")
+		return nil, ErrInvalidClassID
+	}
+
+	actorID, err := primitive.ObjectIDFromHex(input.ActorID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	usePix := input.PaymentMethod == entity.PaymentChannelPix
+	if !usePix && input.PaymentMethod != "" && input.PaymentMethod != entity.PaymentChannelCheckout {
+		return nil, ErrInvalidPaymentMethod
+	}
+
+	onBehalf := actorID != userID
+	if onBehalf && input.ActorRole != entity.RoleAdmin && input.ActorRole != entity.RoleInstructor {
+		return nil, ErrNotAllowed
 	}
 
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("usuário não encontrado")
+		return nil, ErrUserNotFound
 	}
 	if !user.IsStudent() {
-		return nil, fmt.Errorf("apenas estudantes podem se inscrever em aulas")
+		return nil, ErrNotStudent
 	}
 
 	existing, err := uc.enrollmentRepo.FindByUserAndClass(ctx, userID, classID)
@@ -74,63 +128,252 @@ func (uc *EnrollStudentUseCase) Execute(ctx context.Context, input EnrollStudent
 		return nil, err
 	}
 	if existing != nil {
-		return nil, fmt.Errorf("usuário já está inscrito nesta aula")
+		return nil, ErrAlreadyEnrolled
 	}
 
-	var result *EnrollStudentOutput
+	coupon, err := uc.findCoupon(ctx, input, userID)
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		class, err := uc.classRepo.FindByID(ctx, classID)
-		if err != nil {
-			return nil, err
-		}
+	var (
+		enrollment    *entity.Enrollment
+		paymentEntity *entity.Payment
+		message       *entity.OutboxMessage
+	)
 
-		if !class.HasAvailableSpots() {
-			return nil, fmt.Errorf("aula sem vagas disponíveis")
+	err = uc.reserveSeat(ctx, classID, func(sc mongo.SessionContext, class *entity.Class) error {
+		enrollment = entity.NewEnrollment(userID, classID)
+		if onBehalf {
+			enrollment.OnBehalfOf(actorID)
 		}
 
-		enrollment := entity.NewEnrollment(userID, classID)
-		paymentEntity := entity.NewPayment(enrollment.ID, class.PriceInCents)
+		// Sem aulas disponíveis no período (ou se a aula é depois do fim do período), o
+		// aluno assinante paga a aula avulsa normalmente.
+		if !input.UseCredit {
+			covered, err := membershipUC.CoverClass(sc, uc.membershipRepo, enrollment, class, time.Now())
+			if err != nil {
+				return err
+			}
+			if covered {
+				return uc.enrollmentRepo.Create(sc, enrollment)
+			}
+		}
 
-		err = uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
-			if err := uc.classRepo.IncrementEnrollmentWithVersion(sc, classID, class.Version); err != nil {
+		if input.UseCredit {
+			if err := creditUC.ConsumeCredit(sc, uc.creditRepo, enrollment, time.Now()); err != nil {
 				return err
 			}
+			return uc.enrollmentRepo.Create(sc, enrollment)
+		}
 
-			if err := uc.enrollmentRepo.Create(sc, enrollment); err != nil {
+		paymentEntity = entity.NewPayment(enrollment.ID, class.PriceInCents)
+
+		if coupon != nil {
+			if err := coupon.Check(class, time.Now()); err != nil {
 				return err
 			}
 
-			preferenceResp, err := uc.mercadoPago.CreatePreference(sc, &payment.PreferenceRequest{
-				Title:       class.Title,
-				Description: class.Description,
-				Quantity:    1,
-				UnitPrice:   float64(class.PriceInCents) / 100.0,
-				ExternalRef: enrollment.ID.Hex(),
-				NotifyURL:   uc.config.MercadoPago.NotifyURL,
-				BackURL:     uc.config.MercadoPago.BackURL,
-			})
-			if err != nil {
-				return fmt.Errorf("erro ao criar preferência de pagamento: %w", err)
+			discount := coupon.Discount(class.PriceInCents)
+			redemption := entity.NewCouponRedemption(coupon, userID, enrollment.ID, discount)
+			if err := uc.couponRepo.Redeem(sc, redemption, coupon.MaxPerUser); err != nil {
+				return err
 			}
+			paymentEntity.ApplyCoupon(coupon, discount)
 
-			paymentEntity.SetPreference(preferenceResp.ID, preferenceResp.InitPointURL)
+			if paymentEntity.AmountInCents == 0 {
+				paymentEntity.MarkFree()
+				enrollment.Confirm("")
 
-			if err := uc.paymentRepo.Create(sc, paymentEntity); err != nil {
-				return err
+				if err := uc.enrollmentRepo.Create(sc, enrollment); err != nil {
+					return err
+				}
+				return uc.paymentRepo.Create(sc, paymentEntity)
 			}
+		}
+
+		// A reserva de uma inscrição por Pix dura o mesmo que o QR code; se faltar menos que o
+		// mínimo do Mercado Pago ao criar a cobrança, ela é prorrogada (ver processPix).
+		if usePix {
+			paymentEntity.UsePix()
+			enrollment.HoldUntil(time.Now().Add(uc.config.Enrollment.PixExpiration))
+		} else {
+			enrollment.HoldUntil(time.Now().Add(uc.config.Enrollment.HoldTTL))
+		}
+		message = entity.NewOutboxMessage(entity.OutboxTypeCreateCheckout, enrollment.ID)
+		message.Lease(time.Now().Add(checkoutLease))
+
+		if err := uc.enrollmentRepo.Create(sc, enrollment); err != nil {
+			return err
+		}
+
+		if err := uc.paymentRepo.Create(sc, paymentEntity); err != nil {
+			return err
+		}
+
+		return uc.outboxRepo.Create(sc, message)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if onBehalf {
+		logger.Info("Inscrição realizada em nome do aluno",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("user_id", userID.Hex()),
+			zap.String("acted_by", actorID.Hex()),
+		)
+	}
+
+	if enrollment.PaidWithMembership() {
+		logger.Info("Inscrição confirmada pela assinatura",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("membership_id", enrollment.MembershipID.Hex()),
+		)
+		return &EnrollStudentOutput{Enrollment: enrollment}, nil
+	}
+
+	if input.UseCredit {
+		logger.Info("Inscrição confirmada com crédito",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("credit_lot_id", enrollment.CreditLotID.Hex()),
+		)
+		return &EnrollStudentOutput{Enrollment: enrollment}, nil
+	}
+
+	if paymentEntity.IsApproved() {
+		logger.Info("Inscrição confirmada sem custo com cupom",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("coupon_code", paymentEntity.CouponCode),
+		)
+		return &EnrollStudentOutput{Enrollment: enrollment, Payment: paymentEntity}, nil
+	}
+
+	return uc.checkout(ctx, enrollment, paymentEntity, message), nil
+}
 
-			result = &EnrollStudentOutput{
-				Enrollment: enrollment,
-				Payment:    paymentEntity,
-				PaymentURL: preferenceResp.InitPointURL,
+// findCoupon carrega o cupom informado na inscrição. As regras que dependem da aula e os
+// limites de uso são verificados dentro da transação da reserva.
+func (uc *EnrollStudentUseCase) findCoupon(ctx context.Context, input EnrollStudentInput, userID primitive.ObjectID) (*entity.Coupon, error) {
+	code := entity.NormalizeCouponCode(input.CouponCode)
+	if code == "" {
+		return nil, nil
+	}
+	if input.UseCredit {
+		return nil, ErrCouponWithCredit
+	}
+
+	coupon, err := uc.couponRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if coupon.IsFirstClassOnly() {
+		enrolledBefore, err := uc.enrollmentRepo.HasConfirmedByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if enrolledBefore {
+			return nil, entity.ErrCouponFirstClass
+		}
+	}
+
+	return coupon, nil
+}
+
+// reserveSeat ocupa uma vaga da aula e executa persist na mesma transação. Conflitos de
+// versão da aula são repetidos com backoff; persist é chamado de novo a cada tentativa.
+func (uc *EnrollStudentUseCase) reserveSeat(ctx context.Context, classID primitive.ObjectID, persist func(sc mongo.SessionContext, class *entity.Class) error) error {
+	for attempt := 0; ; attempt++ {
+		class, err := uc.classRepo.FindByID(ctx, classID)
+		if err != nil {
+			return err
+		}
+
+		if !class.IsOpenForEnrollment(time.Now()) {
+			return ErrClassNotOpen
+		}
+
+		if !class.HasAvailableSpots() {
+			return repository.ErrClassFull
+		}
+
+		err = uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+			if err := uc.classRepo.IncrementEnrollmentWithVersion(sc, classID, class.Version); err != nil {
+				return err
 			}
 
-			return nil
+			return persist(sc, class)
 		})
 
 		if err == nil {
-			return result, nil
+			return nil
 		}
+
+		if !errors.Is(err, repository.ErrVersionConflict) {
+			return err
+		}
+
+		if attempt+1 >= maxEnrollAttempts {
+			logger.Warn("Limite de tentativas de inscrição atingido",
+				zap.String("class_id", classID.Hex()),
+				zap.Int("attempts", attempt+1),
+			)
+			return ErrEnrollmentContended
+		}
+
+		if err := sleepWithJitter(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepWithJitter aguarda um backoff exponencial com jitter completo antes de nova tentativa.
+func sleepWithJitter(ctx context.Context, attempt int) error {
+	backoff := enrollRetryBaseDelay << attempt
+	if backoff > enrollRetryMaxDelay {
+		backoff = enrollRetryMaxDelay
+	}
+
+	timer := time.NewTimer(rand.N(backoff) + time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// checkout tenta criar o checkout logo após a reserva para que a resposta já traga a URL
+// de pagamento ou o QR code Pix. Em caso de falha, o worker de outbox retoma a mensagem
+// quando termina o lease de checkoutLease.
+func (uc *EnrollStudentUseCase) checkout(ctx context.Context, enrollment *entity.Enrollment, paymentEntity *entity.Payment, message *entity.OutboxMessage) *EnrollStudentOutput {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	updated, err := uc.processCheckout.Process(ctx, message)
+	if err != nil {
+		logger.Warn("Checkout não criado imediatamente, será processado em segundo plano",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.Error(err),
+		)
+	}
+	if updated != nil {
+		paymentEntity = updated
+	}
+	// A criação do Pix pode prorrogar a reserva até a validade do QR code.
+	if paymentEntity.Pix != nil && paymentEntity.Pix.ExpiresAt.After(*enrollment.ExpiresAt) {
+		enrollment.HoldUntil(paymentEntity.Pix.ExpiresAt)
+	}
+
+	return &EnrollStudentOutput{
+		Enrollment:      enrollment,
+		Payment:         paymentEntity,
+		PaymentURL:      paymentEntity.InitPointURL,
+		Pix:             paymentEntity.Pix,
+		CheckoutPending: !paymentEntity.CheckoutCreated(),
+		ExpiresAt:       enrollment.ExpiresAt,
 	}
 }
@@ -0,0 +1,19 @@
+package enrollment
+
+import "errors"
+
+var (
+	ErrInvalidUserID        = errors.New("user_id inválido")
+	ErrInvalidClassID       = errors.New("class_id inválido")
+	ErrUserNotFound         = errors.New("usuário não encontrado")
+	ErrNotStudent           = errors.New("apenas estudantes podem se inscrever em aulas")
+	ErrAlreadyEnrolled      = errors.New("usuário já está inscrito nesta aula")
+	ErrClassNotOpen         = errors.New("aula não está aberta para inscrições")
+	ErrInvalidFilter        = errors.New("filtro inválido: use upcoming, past ou cancelled")
+	ErrInvalidEnrollmentID  = errors.New("enrollment_id inválido")
+	ErrNotAllowed           = errors.New("apenas administradores e instrutores podem agir em nome de outro usuário")
+	ErrNotCancellable       = errors.New("apenas inscrições confirmadas podem ser canceladas")
+	ErrEnrollmentContended  = errors.New("muitas inscrições simultâneas nesta aula, tente novamente")
+	ErrCouponWithCredit     = errors.New("cupons não podem ser usados em inscrições pagas com crédito")
+	ErrInvalidPaymentMethod = errors.New("payment_method inválido: use checkout ou pix")
+)
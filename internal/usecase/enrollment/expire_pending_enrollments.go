@@ -0,0 +1,94 @@
+package enrollment
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/waitlist"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const expireBatchSize = 100
+
+// ExpirePendingEnrollmentsUseCase libera as vagas de inscrições cujo pagamento não
+// foi concluído dentro do prazo de reserva, repassando-as à lista de espera.
+type ExpirePendingEnrollmentsUseCase struct {
+	classRepo      repository.ClassRepository
+	enrollmentRepo repository.EnrollmentRepository
+	paymentRepo    repository.PaymentRepository
+	couponRepo     repository.CouponRepository
+	releaseSeat    *waitlist.ReleaseSeatUseCase
+}
+
+func NewExpirePendingEnrollmentsUseCase(
+	classRepo repository.ClassRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	paymentRepo repository.PaymentRepository,
+	couponRepo repository.CouponRepository,
+	releaseSeat *waitlist.ReleaseSeatUseCase,
+) *ExpirePendingEnrollmentsUseCase {
+	return &ExpirePendingEnrollmentsUseCase{
+		classRepo:      classRepo,
+		enrollmentRepo: enrollmentRepo,
+		paymentRepo:    paymentRepo,
+		couponRepo:     couponRepo,
+		releaseSeat:    releaseSeat,
+	}
+}
+
+func (uc *ExpirePendingEnrollmentsUseCase) Execute(ctx context.Context) error {
+	now := time.Now()
+
+	enrollments, err := uc.enrollmentRepo.FindExpiredPending(ctx, now, expireBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, enrollment := range enrollments {
+		err := uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+			expired, err := uc.enrollmentRepo.ExpireIfPending(sc, enrollment.ID, now)
+			if err != nil || !expired {
+				return err
+			}
+
+			paymentEntity, err := uc.paymentRepo.FindByEnrollmentID(sc, enrollment.ID)
+			if err != nil {
+				return err
+			}
+			if paymentEntity != nil {
+				paymentEntity.MarkExpired()
+				if err := uc.paymentRepo.Update(sc, paymentEntity); err != nil {
+					return err
+				}
+				if paymentEntity.CouponID != nil {
+					if _, err := uc.couponRepo.ReleaseByEnrollment(sc, enrollment.ID); err != nil {
+						return err
+					}
+				}
+			}
+
+			if err := uc.releaseSeat.CloseOffer(sc, enrollment.ID, false); err != nil {
+				return err
+			}
+
+			return uc.releaseSeat.Execute(sc, enrollment.ClassID)
+		})
+		if err != nil {
+			logger.Error("Erro ao expirar inscrição pendente",
+				zap.String("enrollment_id", enrollment.ID.Hex()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		logger.Info("Inscrição pendente expirada e vaga liberada",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("class_id", enrollment.ClassID.Hex()),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package enrollment
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GetEnrollmentUseCase struct {
+	enrollmentRepo repository.EnrollmentRepository
+	paymentRepo    repository.PaymentRepository
+}
+
+func NewGetEnrollmentUseCase(
+	enrollmentRepo repository.EnrollmentRepository,
+	paymentRepo repository.PaymentRepository,
+) *GetEnrollmentUseCase {
+	return &GetEnrollmentUseCase{
+		enrollmentRepo: enrollmentRepo,
+		paymentRepo:    paymentRepo,
+	}
+}
+
+type GetEnrollmentInput struct {
+	EnrollmentID string
+	UserID       string
+	Role         entity.UserRole
+}
+
+func (uc *GetEnrollmentUseCase) Execute(ctx context.Context, input GetEnrollmentInput) (*EnrollStudentOutput, error) {
+	id, err := primitive.ObjectIDFromHex(input.EnrollmentID)
+	if err != nil {
+		return nil, ErrInvalidEnrollmentID
+	}
+
+	enrollment, err := uc.enrollmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Role == entity.RoleStudent && enrollment.UserID.Hex() != input.UserID {
+		return nil, repository.ErrEnrollmentNotFound
+	}
+
+	paymentEntity, err := uc.paymentRepo.FindByEnrollmentID(ctx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &EnrollStudentOutput{
+		Enrollment: enrollment,
+		Payment:    paymentEntity,
+	}
+	if enrollment.IsPending() {
+		output.ExpiresAt = enrollment.ExpiresAt
+	}
+	if paymentEntity != nil {
+		output.PaymentURL = paymentEntity.InitPointURL
+		output.CheckoutPending = enrollment.IsPending() && !paymentEntity.CheckoutCreated()
+		if enrollment.IsPending() {
+			output.Pix = paymentEntity.Pix
+		}
+	}
+
+	return output, nil
+}
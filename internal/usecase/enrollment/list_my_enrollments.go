@@ -0,0 +1,163 @@
+package enrollment
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FilterUpcoming  = "upcoming"
+	FilterPast      = "past"
+	FilterCancelled = "cancelled"
+)
+
+type ListMyEnrollmentsUseCase struct {
+	enrollmentRepo repository.EnrollmentRepository
+	classRepo      repository.ClassRepository
+	paymentRepo    repository.PaymentRepository
+}
+
+func NewListMyEnrollmentsUseCase(
+	enrollmentRepo repository.EnrollmentRepository,
+	classRepo repository.ClassRepository,
+	paymentRepo repository.PaymentRepository,
+) *ListMyEnrollmentsUseCase {
+	return &ListMyEnrollmentsUseCase{
+		enrollmentRepo: enrollmentRepo,
+		classRepo:      classRepo,
+		paymentRepo:    paymentRepo,
+	}
+}
+
+type ListMyEnrollmentsInput struct {
+	UserID string
+	Filter string
+}
+
+type EnrolledClass struct {
+	ID             primitive.ObjectID `json:"id"`
+	Title          string             `json:"title"`
+	InstructorID   primitive.ObjectID `json:"instructor_id"`
+	InstructorName string             `json:"instructor_name"`
+	StartTime      time.Time          `json:"start_time"`
+	EndTime        time.Time          `json:"end_time"`
+	Status         string             `json:"status"`
+}
+
+type MyEnrollment struct {
+	Enrollment    *entity.Enrollment `json:"enrollment"`
+	Class         *EnrolledClass     `json:"class"`
+	PaymentID     string             `json:"payment_id,omitempty"`
+	PaymentStatus string             `json:"payment_status,omitempty"`
+	PaymentURL    string             `json:"payment_url,omitempty"`
+	PixQRCode     string             `json:"pix_qr_code,omitempty"`
+}
+
+// Execute lista as inscrições do usuário com os dados da aula e do pagamento.
+// upcoming: pendentes e confirmadas de aulas que ainda não terminaram, da mais próxima
+// para a mais distante; past: confirmadas de aulas encerradas; cancelled: canceladas.
+func (uc *ListMyEnrollmentsUseCase) Execute(ctx context.Context, input ListMyEnrollmentsInput) ([]*MyEnrollment, error) {
+	userID, err := primitive.ObjectIDFromHex(input.UserID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	switch input.Filter {
+	case "", FilterUpcoming, FilterPast, FilterCancelled:
+	default:
+		return nil, ErrInvalidFilter
+	}
+
+	enrollments, err := uc.enrollmentRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(enrollments) == 0 {
+		return []*MyEnrollment{}, nil
+	}
+
+	classIDs := make([]primitive.ObjectID, 0, len(enrollments))
+	enrollmentIDs := make([]primitive.ObjectID, 0, len(enrollments))
+	for _, e := range enrollments {
+		classIDs = append(classIDs, e.ClassID)
+		enrollmentIDs = append(enrollmentIDs, e.ID)
+	}
+
+	classes, err := uc.classRepo.FindByIDs(ctx, classIDs)
+	if err != nil {
+		return nil, err
+	}
+	classByID := make(map[primitive.ObjectID]*entity.Class, len(classes))
+	for _, c := range classes {
+		classByID[c.ID] = c
+	}
+
+	payments, err := uc.paymentRepo.FindByEnrollmentIDs(ctx, enrollmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	paymentByEnrollment := make(map[primitive.ObjectID]*entity.Payment, len(payments))
+	for _, p := range payments {
+		paymentByEnrollment[p.EnrollmentID] = p
+	}
+
+	now := time.Now()
+	result := []*MyEnrollment{}
+	for _, e := range enrollments {
+		class, ok := classByID[e.ClassID]
+		if !ok || !matchesFilter(input.Filter, e, class, now) {
+			continue
+		}
+
+		item := &MyEnrollment{
+			Enrollment: e,
+			Class: &EnrolledClass{
+				ID:             class.ID,
+				Title:          class.Title,
+				InstructorID:   class.InstructorID,
+				InstructorName: class.InstructorName,
+				StartTime:      class.StartTime,
+				EndTime:        class.EndTime,
+				Status:         class.Status,
+			},
+		}
+		if p, ok := paymentByEnrollment[e.ID]; ok {
+			item.PaymentID = p.ID.Hex()
+			item.PaymentStatus = p.Status
+			if e.IsPending() {
+				item.PaymentURL = p.InitPointURL
+				if p.Pix != nil {
+					item.PixQRCode = p.Pix.QRCode
+				}
+			}
+		}
+		result = append(result, item)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if input.Filter == FilterUpcoming {
+			return result[i].Class.StartTime.Before(result[j].Class.StartTime)
+		}
+		return result[i].Class.StartTime.After(result[j].Class.StartTime)
+	})
+
+	return result, nil
+}
+
+func matchesFilter(filter string, e *entity.Enrollment, class *entity.Class, now time.Time) bool {
+	switch filter {
+	case FilterUpcoming:
+		return (e.IsPending() || e.IsConfirmed()) && class.EndTime.After(now)
+	case FilterPast:
+		return e.IsConfirmed() && !class.EndTime.After(now)
+	case FilterCancelled:
+		return e.IsCancelled()
+	default:
+		return true
+	}
+}
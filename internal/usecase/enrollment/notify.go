@@ -0,0 +1,79 @@
+package enrollment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// notifyWaitlistOffer avisa o aluno promovido da lista de espera de que a vaga está
+// reservada, com o link de pagamento recém-criado. Inscrições feitas diretamente não têm
+// oferta e não são notificadas; falhas no envio só são registradas.
+func (uc *ProcessCheckoutOutboxUseCase) notifyWaitlistOffer(ctx context.Context, enrollment *entity.Enrollment, paymentEntity *entity.Payment, class *entity.Class) {
+	entry, err := uc.waitlistRepo.FindByEnrollmentID(ctx, enrollment.ID)
+	if err != nil {
+		logger.Warn("Erro ao buscar oferta da lista de espera",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.Error(err),
+		)
+		return
+	}
+	if entry == nil || !entry.IsOffered() {
+		return
+	}
+
+	paymentURL := paymentEntity.InitPointURL
+	if paymentEntity.Pix != nil {
+		paymentURL = paymentEntity.Pix.TicketURL
+	}
+
+	message := fmt.Sprintf("Abriu uma vaga na aula %s de %s e ela está reservada para você.", class.Title, entity.FormatLocalTime(class.StartTime))
+	if enrollment.ExpiresAt != nil {
+		message = fmt.Sprintf("%s Conclua o pagamento até %s para garantir a inscrição: %s", message, entity.FormatLocalTime(*enrollment.ExpiresAt), paymentURL)
+	} else {
+		message = fmt.Sprintf("%s Conclua o pagamento para garantir a inscrição: %s", message, paymentURL)
+	}
+
+	uc.notifyStudent(ctx, enrollment, "Vaga disponível na lista de espera", message)
+}
+
+// notifyWaitlistConfirmed avisa o aluno promovido da lista de espera de que a vaga já está
+// confirmada, paga pela assinatura ou por um crédito de pacote.
+func (uc *ProcessCheckoutOutboxUseCase) notifyWaitlistConfirmed(ctx context.Context, enrollment *entity.Enrollment, class *entity.Class) {
+	paidWith := "pela sua assinatura"
+	if enrollment.PaidWithCredit() {
+		paidWith = "com um crédito do seu pacote"
+	}
+
+	message := fmt.Sprintf("Abriu uma vaga na aula %s de %s e sua inscrição foi confirmada %s.", class.Title, entity.FormatLocalTime(class.StartTime), paidWith)
+	uc.notifyStudent(ctx, enrollment, "Inscrição confirmada pela lista de espera", message)
+}
+
+func (uc *ProcessCheckoutOutboxUseCase) notifyStudent(ctx context.Context, enrollment *entity.Enrollment, subject, message string) {
+	user, err := uc.userRepo.FindByID(ctx, enrollment.UserID)
+	if err != nil {
+		logger.Warn("Aluno não encontrado para notificação",
+			zap.String("user_id", enrollment.UserID.Hex()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	err = uc.notifier.Notify(ctx, &gateway.Notification{
+		UserID:  user.ID,
+		Name:    user.Name,
+		Email:   user.Email,
+		Subject: subject,
+		Message: message,
+	})
+	if err != nil {
+		logger.Warn("Erro ao notificar aluno",
+			zap.String("user_id", user.ID.Hex()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,293 @@
+package enrollment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/waitlist"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	checkoutLease     = 30 * time.Second
+	checkoutBatchSize = 20
+
+	// O Mercado Pago recusa Pix com validade inferior a 30 minutos; a margem cobre o
+	// tempo entre o cálculo da expiração e a chegada da requisição ao gateway.
+	pixMinExpiration    = 30 * time.Minute
+	pixExpirationMargin = 2 * time.Minute
+)
+
+// ProcessCheckoutOutboxUseCase cria o checkout no gateway para inscrições já reservadas:
+// a preferência do Checkout Pro ou, quando o aluno escolheu Pix, o pagamento Pix com o QR
+// code. Alunos promovidos da lista de espera recebem o link de pagamento assim que ele é
+// criado; os que tiveram a vaga confirmada pela assinatura ou por um crédito não têm
+// pagamento e só são avisados. Quando as tentativas se esgotam, a inscrição é rejeitada e
+// a vaga liberada.
+type ProcessCheckoutOutboxUseCase struct {
+	outboxRepo     repository.OutboxRepository
+	classRepo      repository.ClassRepository
+	enrollmentRepo repository.EnrollmentRepository
+	paymentRepo    repository.PaymentRepository
+	couponRepo     repository.CouponRepository
+	userRepo       repository.UserRepository
+	waitlistRepo   repository.WaitlistRepository
+	paymentGateway gateway.PaymentGateway
+	notifier       gateway.Notifier
+	releaseSeat    *waitlist.ReleaseSeatUseCase
+	config         *config.Config
+}
+
+func NewProcessCheckoutOutboxUseCase(
+	outboxRepo repository.OutboxRepository,
+	classRepo repository.ClassRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	paymentRepo repository.PaymentRepository,
+	couponRepo repository.CouponRepository,
+	userRepo repository.UserRepository,
+	waitlistRepo repository.WaitlistRepository,
+	paymentGateway gateway.PaymentGateway,
+	notifier gateway.Notifier,
+	releaseSeat *waitlist.ReleaseSeatUseCase,
+	config *config.Config,
+) *ProcessCheckoutOutboxUseCase {
+	return &ProcessCheckoutOutboxUseCase{
+		outboxRepo:     outboxRepo,
+		classRepo:      classRepo,
+		enrollmentRepo: enrollmentRepo,
+		paymentRepo:    paymentRepo,
+		couponRepo:     couponRepo,
+		userRepo:       userRepo,
+		waitlistRepo:   waitlistRepo,
+		paymentGateway: paymentGateway,
+		notifier:       notifier,
+		releaseSeat:    releaseSeat,
+		config:         config,
+	}
+}
+
+// Execute processa um lote de mensagens pendentes; é o job do worker de outbox.
+func (uc *ProcessCheckoutOutboxUseCase) Execute(ctx context.Context) error {
+	for i := 0; i < checkoutBatchSize; i++ {
+		message, err := uc.outboxRepo.ClaimNext(ctx, entity.OutboxTypeCreateCheckout, time.Now(), checkoutLease)
+		if err != nil {
+			return err
+		}
+		if message == nil {
+			return nil
+		}
+
+		if _, err := uc.Process(ctx, message); err != nil {
+			logger.Warn("Falha ao processar checkout pendente",
+				zap.String("message_id", message.ID.Hex()),
+				zap.String("enrollment_id", message.AggregateID.Hex()),
+				zap.Int("attempts", message.Attempts),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// Process executa uma tentativa para a mensagem já reservada e devolve o pagamento atualizado.
+func (uc *ProcessCheckoutOutboxUseCase) Process(ctx context.Context, message *entity.OutboxMessage) (*entity.Payment, error) {
+	paymentEntity, err := uc.paymentRepo.FindByEnrollmentID(ctx, message.AggregateID)
+	if err != nil {
+		return nil, err
+	}
+	if paymentEntity == nil {
+		return nil, uc.processCovered(ctx, message)
+	}
+
+	if paymentEntity.CheckoutCreated() {
+		message.MarkProcessed()
+		return paymentEntity, uc.outboxRepo.Update(ctx, message)
+	}
+
+	enrollment, err := uc.enrollmentRepo.FindByID(ctx, message.AggregateID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrollment.IsPending() {
+		message.MarkProcessed()
+		return paymentEntity, uc.outboxRepo.Update(ctx, message)
+	}
+
+	class, err := uc.classRepo.FindByID(ctx, enrollment.ClassID)
+	if err != nil {
+		return nil, err
+	}
+
+	if paymentEntity.IsPix() {
+		return uc.processPix(ctx, message, enrollment, paymentEntity, class)
+	}
+
+	checkout, err := uc.paymentGateway.CreateCheckout(ctx, &gateway.CheckoutRequest{
+		Title:         class.Title,
+		Description:   class.Description,
+		AmountInCents: paymentEntity.AmountInCents,
+		ExternalRef:   enrollment.ID.Hex(),
+		NotifyURL:     uc.config.MercadoPago.NotifyURL,
+		BackURL:       uc.config.MercadoPago.BackURL,
+		ExpiresAt:     enrollment.ExpiresAt,
+	})
+	if err != nil {
+		return paymentEntity, uc.handleFailure(ctx, message, enrollment, paymentEntity, fmt.Errorf("erro ao criar preferência de pagamento: %w", err))
+	}
+
+	paymentEntity.SetPreference(checkout.ID, checkout.CheckoutURL)
+	if err := uc.paymentRepo.Update(ctx, paymentEntity); err != nil {
+		return nil, err
+	}
+
+	message.MarkProcessed()
+	if err := uc.outboxRepo.Update(ctx, message); err != nil {
+		return nil, err
+	}
+
+	uc.notifyWaitlistOffer(ctx, enrollment, paymentEntity, class)
+	return paymentEntity, nil
+}
+
+// processCovered trata a mensagem de uma inscrição sem pagamento: a vaga da lista de espera
+// confirmada pela assinatura ou por um crédito, que só precisa do aviso ao aluno.
+func (uc *ProcessCheckoutOutboxUseCase) processCovered(ctx context.Context, message *entity.OutboxMessage) error {
+	enrollment, err := uc.enrollmentRepo.FindByID(ctx, message.AggregateID)
+	if err != nil {
+		return err
+	}
+	if !enrollment.PaidWithMembership() && !enrollment.PaidWithCredit() {
+		message.MarkFailed(fmt.Errorf("pagamento não encontrado"))
+		return uc.outboxRepo.Update(ctx, message)
+	}
+
+	message.MarkProcessed()
+	if err := uc.outboxRepo.Update(ctx, message); err != nil {
+		return err
+	}
+
+	if enrollment.IsConfirmed() {
+		class, err := uc.classRepo.FindByID(ctx, enrollment.ClassID)
+		if err != nil {
+			return err
+		}
+		uc.notifyWaitlistConfirmed(ctx, enrollment, class)
+	}
+	return nil
+}
+
+// processPix cria o pagamento Pix com a mesma expiração da reserva da vaga, de modo que o
+// QR code deixa de valer quando a inscrição expira. Quando a reserva restante é menor que o
+// mínimo aceito pelo Mercado Pago (por exemplo, em novas tentativas do outbox), ela é
+// prorrogada até a validade do QR code antes de chamar o gateway.
+func (uc *ProcessCheckoutOutboxUseCase) processPix(ctx context.Context, message *entity.OutboxMessage, enrollment *entity.Enrollment, paymentEntity *entity.Payment, class *entity.Class) (*entity.Payment, error) {
+	user, err := uc.userRepo.FindByID(ctx, enrollment.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(pixMinExpiration + pixExpirationMargin)
+	if enrollment.ExpiresAt != nil && enrollment.ExpiresAt.After(expiresAt) {
+		expiresAt = *enrollment.ExpiresAt
+	}
+	if enrollment.ExpiresAt == nil || expiresAt.After(*enrollment.ExpiresAt) {
+		extended, err := uc.enrollmentRepo.ExtendHoldIfPending(ctx, enrollment.ID, expiresAt, now)
+		if err != nil {
+			return nil, err
+		}
+		if !extended {
+			message.MarkProcessed()
+			return paymentEntity, uc.outboxRepo.Update(ctx, message)
+		}
+		enrollment.HoldUntil(expiresAt)
+	}
+
+	pix, err := uc.paymentGateway.CreatePixPayment(ctx, &gateway.PixRequest{
+		Description:   class.Title,
+		AmountInCents: paymentEntity.AmountInCents,
+		ExternalRef:   enrollment.ID.Hex(),
+		NotifyURL:     uc.config.MercadoPago.NotifyURL,
+		PayerEmail:    user.Email,
+		PayerName:     user.Name,
+		ExpiresAt:     expiresAt,
+	})
+	if err != nil {
+		return paymentEntity, uc.handleFailure(ctx, message, enrollment, paymentEntity, fmt.Errorf("erro ao criar pagamento Pix: %w", err))
+	}
+
+	paymentEntity.SetPix(pix.ID, &entity.PixCharge{
+		QRCode:       pix.QRCode,
+		QRCodeBase64: pix.QRCodeBase64,
+		TicketURL:    pix.TicketURL,
+		ExpiresAt:    pix.ExpiresAt,
+	})
+	if err := uc.paymentRepo.Update(ctx, paymentEntity); err != nil {
+		return nil, err
+	}
+
+	message.MarkProcessed()
+	if err := uc.outboxRepo.Update(ctx, message); err != nil {
+		return nil, err
+	}
+
+	uc.notifyWaitlistOffer(ctx, enrollment, paymentEntity, class)
+	return paymentEntity, nil
+}
+
+func (uc *ProcessCheckoutOutboxUseCase) handleFailure(ctx context.Context, message *entity.OutboxMessage, enrollment *entity.Enrollment, paymentEntity *entity.Payment, cause error) error {
+	if message.Attempts < uc.config.Worker.OutboxMaxAttempts {
+		backoff := time.Duration(message.Attempts*message.Attempts) * uc.config.Worker.OutboxInterval
+		message.RetryAt(cause, time.Now().Add(backoff))
+		if err := uc.outboxRepo.Update(ctx, message); err != nil {
+			return err
+		}
+		return cause
+	}
+
+	err := uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		enrollment.Reject()
+		if err := uc.enrollmentRepo.Update(sc, enrollment); err != nil {
+			return err
+		}
+
+		paymentEntity.MarkCancelled()
+		if err := uc.paymentRepo.Update(sc, paymentEntity); err != nil {
+			return err
+		}
+		if paymentEntity.CouponID != nil {
+			if _, err := uc.couponRepo.ReleaseByEnrollment(sc, enrollment.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.releaseSeat.CloseOffer(sc, enrollment.ID, false); err != nil {
+			return err
+		}
+
+		if err := uc.releaseSeat.Execute(sc, enrollment.ClassID); err != nil {
+			return err
+		}
+
+		message.MarkFailed(cause)
+		return uc.outboxRepo.Update(sc, message)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Error("Checkout não criado após esgotar tentativas, vaga liberada",
+		zap.String("enrollment_id", enrollment.ID.Hex()),
+		zap.Int("attempts", message.Attempts),
+		zap.Error(cause),
+	)
+
+	return cause
+}
@@ -0,0 +1,70 @@
+package membership
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// CancelMembershipUseCase encerra as cobranças recorrentes da assinatura do aluno. O mês já
+// pago continua valendo até o fim do período.
+type CancelMembershipUseCase struct {
+	membershipRepo      repository.MembershipRepository
+	subscriptionGateway gateway.SubscriptionGateway
+}
+
+func NewCancelMembershipUseCase(
+	membershipRepo repository.MembershipRepository,
+	subscriptionGateway gateway.SubscriptionGateway,
+) *CancelMembershipUseCase {
+	return &CancelMembershipUseCase{
+		membershipRepo:      membershipRepo,
+		subscriptionGateway: subscriptionGateway,
+	}
+}
+
+func (uc *CancelMembershipUseCase) Execute(ctx context.Context, userID string) (*entity.Membership, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	membership, err := uc.membershipRepo.FindLatestByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil {
+		return nil, ErrNoMembership
+	}
+	if !membership.IsOpen() {
+		return nil, ErrMembershipNotOpen
+	}
+
+	if membership.PreapprovalID != "" {
+		if err := uc.subscriptionGateway.CancelSubscription(ctx, membership.PreapprovalID); err != nil {
+			logger.Error("Erro ao cancelar assinatura no gateway",
+				zap.String("membership_id", membership.ID.Hex()),
+				zap.String("preapproval_id", membership.PreapprovalID),
+				zap.Error(err),
+			)
+			return nil, ErrCancelFailed
+		}
+	}
+
+	membership.UpdateStatus(entity.MembershipStatusCancelled)
+	if err := uc.membershipRepo.Update(ctx, membership); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Assinatura cancelada",
+		zap.String("membership_id", membership.ID.Hex()),
+		zap.String("user_id", membership.UserID.Hex()),
+	)
+
+	return membership, nil
+}
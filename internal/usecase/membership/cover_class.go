@@ -0,0 +1,28 @@
+package membership
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+)
+
+// CoverClass usa uma aula do período da assinatura ativa do aluno e confirma a inscrição.
+// Devolve false quando o aluno não tem assinatura ativa, já usou as aulas do período ou a
+// aula é depois do fim do período; nesses casos a aula é paga avulsa. Deve ser executado
+// na transação que cria a inscrição.
+func CoverClass(ctx context.Context, membershipRepo repository.MembershipRepository, enrollment *entity.Enrollment, class *entity.Class, now time.Time) (bool, error) {
+	membership, err := membershipRepo.FindActiveByUser(ctx, enrollment.UserID, now)
+	if err != nil || membership == nil {
+		return false, err
+	}
+
+	covered, err := membershipRepo.ConsumeClass(ctx, membership.ID, now, class.StartTime)
+	if err != nil || !covered {
+		return false, err
+	}
+
+	enrollment.ConfirmWithMembership(membership.ID)
+	return true, nil
+}
@@ -0,0 +1,17 @@
+package membership
+
+import "errors"
+
+var (
+	ErrInvalidPlanID      = errors.New("plan_id inválido")
+	ErrInvalidUserID      = errors.New("user_id inválido")
+	ErrInvalidPlan        = errors.New("plano inválido: nome e preço são obrigatórios e o limite de aulas não pode ser negativo")
+	ErrPlanInactive       = errors.New("plano de assinatura indisponível")
+	ErrUserNotFound       = errors.New("usuário não encontrado")
+	ErrNotStudent         = errors.New("apenas estudantes podem assinar planos")
+	ErrAlreadySubscribed  = errors.New("o aluno já possui uma assinatura em andamento")
+	ErrNoMembership       = errors.New("o aluno não possui assinatura")
+	ErrMembershipNotOpen  = errors.New("a assinatura já foi cancelada")
+	ErrSubscriptionFailed = errors.New("não foi possível criar a assinatura no gateway, tente novamente")
+	ErrCancelFailed       = errors.New("não foi possível cancelar a assinatura no gateway, tente novamente")
+)
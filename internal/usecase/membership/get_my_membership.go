@@ -0,0 +1,67 @@
+package membership
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GetMyMembershipUseCase struct {
+	membershipRepo repository.MembershipRepository
+}
+
+func NewGetMyMembershipUseCase(membershipRepo repository.MembershipRepository) *GetMyMembershipUseCase {
+	return &GetMyMembershipUseCase{
+		membershipRepo: membershipRepo,
+	}
+}
+
+// MyMembershipOutput traz a assinatura mais recente do aluno e o saldo do período vigente.
+// ClassesLeft é nulo em planos ilimitados.
+type MyMembershipOutput struct {
+	Membership  *entity.Membership `json:"membership"`
+	Active      bool               `json:"active"`
+	ClassesLeft *int               `json:"classes_left"`
+}
+
+func (uc *GetMyMembershipUseCase) Execute(ctx context.Context, userID string) (*MyMembershipOutput, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	now := time.Now()
+
+	// Um período pago de uma assinatura anterior ainda vigente tem prioridade sobre uma
+	// nova assinatura aguardando autorização.
+	membership, err := uc.membershipRepo.FindActiveByUser(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil {
+		membership, err = uc.membershipRepo.FindLatestByUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if membership == nil {
+		return nil, ErrNoMembership
+	}
+
+	output := &MyMembershipOutput{
+		Membership: membership,
+		Active:     membership.IsActive(now),
+	}
+	if !membership.Unlimited() {
+		left := membership.ClassesPerMonth - membership.ClassesUsed
+		if !output.Active || left < 0 {
+			left = 0
+		}
+		output.ClassesLeft = &left
+	}
+
+	return output, nil
+}
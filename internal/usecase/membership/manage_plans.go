@@ -0,0 +1,117 @@
+package membership
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateMembershipPlanUseCase struct {
+	planRepo repository.MembershipPlanRepository
+}
+
+func NewCreateMembershipPlanUseCase(planRepo repository.MembershipPlanRepository) *CreateMembershipPlanUseCase {
+	return &CreateMembershipPlanUseCase{
+		planRepo: planRepo,
+	}
+}
+
+// CreateMembershipPlanInput descreve um plano mensal; ClassesPerMonth igual a zero
+// oferece aulas ilimitadas.
+type CreateMembershipPlanInput struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	PriceInCents    int64  `json:"price_in_cents"`
+	ClassesPerMonth int    `json:"classes_per_month"`
+}
+
+func (uc *CreateMembershipPlanUseCase) Execute(ctx context.Context, input CreateMembershipPlanInput) (*entity.MembershipPlan, error) {
+	if input.Name == "" || input.PriceInCents <= 0 || input.ClassesPerMonth < 0 {
+		return nil, ErrInvalidPlan
+	}
+
+	plan := entity.NewMembershipPlan(input.Name, input.Description, input.PriceInCents, input.ClassesPerMonth)
+	if err := uc.planRepo.Create(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+type UpdateMembershipPlanUseCase struct {
+	planRepo repository.MembershipPlanRepository
+}
+
+func NewUpdateMembershipPlanUseCase(planRepo repository.MembershipPlanRepository) *UpdateMembershipPlanUseCase {
+	return &UpdateMembershipPlanUseCase{
+		planRepo: planRepo,
+	}
+}
+
+// UpdateMembershipPlanInput altera apenas os campos informados. Assinaturas existentes
+// mantêm o preço e o limite de aulas da época em que foram feitas.
+type UpdateMembershipPlanInput struct {
+	PlanID          string  `json:"-"`
+	Name            *string `json:"name"`
+	Description     *string `json:"description"`
+	PriceInCents    *int64  `json:"price_in_cents"`
+	ClassesPerMonth *int    `json:"classes_per_month"`
+	Active          *bool   `json:"active"`
+}
+
+func (uc *UpdateMembershipPlanUseCase) Execute(ctx context.Context, input UpdateMembershipPlanInput) (*entity.MembershipPlan, error) {
+	id, err := primitive.ObjectIDFromHex(input.PlanID)
+	if err != nil {
+		return nil, ErrInvalidPlanID
+	}
+
+	plan, err := uc.planRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		plan.Name = *input.Name
+	}
+	if input.Description != nil {
+		plan.Description = *input.Description
+	}
+	if input.PriceInCents != nil {
+		plan.PriceInCents = *input.PriceInCents
+	}
+	if input.ClassesPerMonth != nil {
+		plan.ClassesPerMonth = *input.ClassesPerMonth
+	}
+	if input.Active != nil {
+		plan.Active = *input.Active
+	}
+
+	if plan.Name == "" || plan.PriceInCents <= 0 || plan.ClassesPerMonth < 0 {
+		return nil, ErrInvalidPlan
+	}
+
+	plan.UpdatedAt = time.Now()
+	if err := uc.planRepo.Update(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+type ListMembershipPlansUseCase struct {
+	planRepo repository.MembershipPlanRepository
+}
+
+func NewListMembershipPlansUseCase(planRepo repository.MembershipPlanRepository) *ListMembershipPlansUseCase {
+	return &ListMembershipPlansUseCase{
+		planRepo: planRepo,
+	}
+}
+
+// Execute lista os planos à venda; includeInactive é usado pela equipe do estúdio.
+func (uc *ListMembershipPlansUseCase) Execute(ctx context.Context, includeInactive bool) ([]*entity.MembershipPlan, error) {
+	return uc.planRepo.FindAll(ctx, !includeInactive)
+}
@@ -0,0 +1,130 @@
+package membership
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// SubscribeUseCase cria a assinatura de um aluno a um plano e a preapproval no gateway.
+// A assinatura fica pendente até o aluno autorizar a cobrança recorrente no link
+// retornado; o período só começa quando a primeira cobrança é aprovada.
+type SubscribeUseCase struct {
+	planRepo            repository.MembershipPlanRepository
+	membershipRepo      repository.MembershipRepository
+	userRepo            repository.UserRepository
+	subscriptionGateway gateway.SubscriptionGateway
+	config              *config.Config
+}
+
+func NewSubscribeUseCase(
+	planRepo repository.MembershipPlanRepository,
+	membershipRepo repository.MembershipRepository,
+	userRepo repository.UserRepository,
+	subscriptionGateway gateway.SubscriptionGateway,
+	config *config.Config,
+) *SubscribeUseCase {
+	return &SubscribeUseCase{
+		planRepo:            planRepo,
+		membershipRepo:      membershipRepo,
+		userRepo:            userRepo,
+		subscriptionGateway: subscriptionGateway,
+		config:              config,
+	}
+}
+
+type SubscribeInput struct {
+	PlanID string
+	UserID string
+}
+
+type SubscribeOutput struct {
+	Membership  *entity.Membership `json:"membership"`
+	CheckoutURL string             `json:"checkout_url"`
+}
+
+func (uc *SubscribeUseCase) Execute(ctx context.Context, input SubscribeInput) (*SubscribeOutput, error) {
+	planID, err := primitive.ObjectIDFromHex(input.PlanID)
+	if err != nil {
+		return nil, ErrInvalidPlanID
+	}
+
+	userID, err := primitive.ObjectIDFromHex(input.UserID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsStudent() {
+		return nil, ErrNotStudent
+	}
+
+	plan, err := uc.planRepo.FindByID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.Active {
+		return nil, ErrPlanInactive
+	}
+
+	latest, err := uc.membershipRepo.FindLatestByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.IsOpen() {
+		return nil, ErrAlreadySubscribed
+	}
+
+	membership := entity.NewMembership(userID, plan)
+	if err := uc.membershipRepo.Create(ctx, membership); err != nil {
+		return nil, err
+	}
+
+	subscription, err := uc.subscriptionGateway.CreateSubscription(ctx, &gateway.SubscriptionRequest{
+		Reason:        plan.Name,
+		PayerEmail:    user.Email,
+		AmountInCents: membership.PriceInCents,
+		ExternalRef:   membership.ID.Hex(),
+		BackURL:       uc.config.MercadoPago.BackURL,
+	})
+	if err != nil {
+		logger.Error("Erro ao criar assinatura no gateway",
+			zap.String("membership_id", membership.ID.Hex()),
+			zap.Error(err),
+		)
+		membership.UpdateStatus(entity.MembershipStatusCancelled)
+		if updateErr := uc.membershipRepo.Update(ctx, membership); updateErr != nil {
+			logger.Error("Erro ao cancelar assinatura sem preapproval",
+				zap.String("membership_id", membership.ID.Hex()),
+				zap.Error(updateErr),
+			)
+		}
+		return nil, ErrSubscriptionFailed
+	}
+
+	membership.SetPreapproval(subscription.ID, subscription.CheckoutURL)
+	if err := uc.membershipRepo.Update(ctx, membership); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Assinatura criada",
+		zap.String("membership_id", membership.ID.Hex()),
+		zap.String("user_id", userID.Hex()),
+		zap.String("plan_id", plan.ID.Hex()),
+		zap.String("preapproval_id", subscription.ID),
+	)
+
+	return &SubscribeOutput{
+		Membership:  membership,
+		CheckoutURL: subscription.CheckoutURL,
+	}, nil
+}
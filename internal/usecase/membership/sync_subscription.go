@@ -0,0 +1,182 @@
+package membership
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// SyncSubscriptionUseCase aplica às assinaturas as notificações de preapproval e de
+// cobrança mensal recebidas pelo webhook. Cada cobrança aprovada é registrada como
+// pagamento e inicia um novo período.
+type SyncSubscriptionUseCase struct {
+	membershipRepo      repository.MembershipRepository
+	paymentRepo         repository.PaymentRepository
+	classRepo           repository.ClassRepository
+	subscriptionGateway gateway.SubscriptionGateway
+}
+
+func NewSyncSubscriptionUseCase(
+	membershipRepo repository.MembershipRepository,
+	paymentRepo repository.PaymentRepository,
+	classRepo repository.ClassRepository,
+	subscriptionGateway gateway.SubscriptionGateway,
+) *SyncSubscriptionUseCase {
+	return &SyncSubscriptionUseCase{
+		membershipRepo:      membershipRepo,
+		paymentRepo:         paymentRepo,
+		classRepo:           classRepo,
+		subscriptionGateway: subscriptionGateway,
+	}
+}
+
+// Preapproval atualiza o status da assinatura (autorizada, pausada ou cancelada).
+func (uc *SyncSubscriptionUseCase) Preapproval(ctx context.Context, preapprovalID string) error {
+	info, err := uc.subscriptionGateway.GetSubscription(ctx, preapprovalID)
+	if err != nil {
+		return fmt.Errorf("erro ao consultar assinatura no gateway: %w", err)
+	}
+
+	membership, err := uc.findMembership(ctx, info.ID, info.ExternalRef)
+	if err != nil {
+		return err
+	}
+	if membership == nil {
+		logger.Warn("Webhook de assinatura ignorado: assinatura não encontrada",
+			zap.String("preapproval_id", info.ID),
+			zap.String("external_reference", info.ExternalRef),
+		)
+		return nil
+	}
+
+	if membership.Status == info.Status && membership.PreapprovalID == info.ID {
+		return nil
+	}
+
+	membership.PreapprovalID = info.ID
+	membership.UpdateStatus(info.Status)
+	if err := uc.membershipRepo.Update(ctx, membership); err != nil {
+		return err
+	}
+
+	logger.Info("Status da assinatura atualizado",
+		zap.String("membership_id", membership.ID.Hex()),
+		zap.String("preapproval_id", info.ID),
+		zap.String("status", info.Status),
+	)
+
+	return nil
+}
+
+// AuthorizedPayment registra uma cobrança mensal da assinatura. Quando aprovada, a
+// cobrança inicia um novo período e zera as aulas usadas.
+func (uc *SyncSubscriptionUseCase) AuthorizedPayment(ctx context.Context, authorizedPaymentID string) error {
+	info, err := uc.subscriptionGateway.GetAuthorizedPayment(ctx, authorizedPaymentID)
+	if err != nil {
+		return fmt.Errorf("erro ao consultar cobrança da assinatura no gateway: %w", err)
+	}
+
+	// Cobranças agendadas ainda não geraram pagamento.
+	if info.PaymentID == "" {
+		return nil
+	}
+
+	membership, err := uc.findMembership(ctx, info.SubscriptionID, info.ExternalRef)
+	if err != nil {
+		return err
+	}
+	if membership == nil {
+		logger.Warn("Cobrança de assinatura ignorada: assinatura não encontrada",
+			zap.String("authorized_payment_id", info.ID),
+			zap.String("preapproval_id", info.SubscriptionID),
+		)
+		return nil
+	}
+
+	paidAt := info.DebitDate
+	if now := time.Now(); paidAt.IsZero() || paidAt.After(now) {
+		paidAt = now
+	}
+
+	return uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		current, err := uc.membershipRepo.FindByID(sc, membership.ID)
+		if err != nil {
+			return err
+		}
+
+		paymentEntity, err := uc.paymentRepo.FindByMercadoPagoID(sc, info.PaymentID)
+		switch {
+		case errors.Is(err, repository.ErrPaymentNotFound):
+			paymentEntity = entity.NewMembershipPayment(current, info.PaymentID, info.PaymentStatus, info.AmountInCents)
+			paymentEntity.PaymentMethod = info.PaymentMethod
+			if err := uc.paymentRepo.Create(sc, paymentEntity); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case paymentEntity.IsAwaiting():
+			// Depois de concluído, o pagamento é atualizado pelo webhook de pagamento,
+			// que também sincroniza os estornos.
+			paymentEntity.UpdateFromMercadoPago(info.PaymentID, info.PaymentStatus, info.PaymentMethod)
+			if err := uc.paymentRepo.Update(sc, paymentEntity); err != nil {
+				return err
+			}
+		}
+
+		if info.PaymentStatus != entity.PaymentStatusApproved || !current.StartsNewPeriod(info.PaymentID, paidAt) {
+			return nil
+		}
+
+		if info.AmountInCents != current.PriceInCents {
+			logger.Warn("Valor da cobrança diverge do preço da assinatura",
+				zap.String("membership_id", current.ID.Hex()),
+				zap.Int64("expected_in_cents", current.PriceInCents),
+				zap.Int64("paid_in_cents", info.AmountInCents),
+			)
+		}
+
+		current.StartPeriod(info.PaymentID, paidAt)
+		if current.Status == entity.MembershipStatusPending {
+			current.UpdateStatus(entity.MembershipStatusAuthorized)
+		}
+		if err := uc.membershipRepo.Update(sc, current); err != nil {
+			return err
+		}
+
+		logger.Info("Período da assinatura renovado",
+			zap.String("membership_id", current.ID.Hex()),
+			zap.String("payment_id", info.PaymentID),
+			zap.Time("period_end", *current.PeriodEnd),
+		)
+		return nil
+	})
+}
+
+// findMembership localiza a assinatura pela preapproval ou, se o id ainda não foi gravado,
+// pela referência externa.
+func (uc *SyncSubscriptionUseCase) findMembership(ctx context.Context, preapprovalID, externalRef string) (*entity.Membership, error) {
+	membership, err := uc.membershipRepo.FindByPreapprovalID(ctx, preapprovalID)
+	if err == nil || !errors.Is(err, repository.ErrMembershipNotFound) {
+		return membership, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(externalRef)
+	if err != nil {
+		return nil, nil
+	}
+
+	membership, err = uc.membershipRepo.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrMembershipNotFound) {
+		return nil, nil
+	}
+	return membership, err
+}
@@ -0,0 +1,17 @@
+package payment
+
+import "errors"
+
+var (
+	ErrInvalidPaymentID    = errors.New("payment_id inválido")
+	ErrInvalidUserID       = errors.New("user_id inválido")
+	ErrNotRefundable       = errors.New("pagamento não possui saldo a estornar")
+	ErrInvalidRefundAmount = errors.New("valor do estorno inválido: deve ser positivo e não exceder o saldo do pagamento")
+	ErrRefundFailed        = errors.New("estorno recusado pelo gateway de pagamento")
+	ErrReceiptUnavailable  = errors.New("recibo disponível apenas para pagamentos aprovados")
+
+	ErrInvalidWebhookPayload  = errors.New("corpo do webhook inválido")
+	ErrInvalidWebhookEventID  = errors.New("event_id inválido")
+	ErrInvalidWebhookStatus   = errors.New("status inválido: use pending, processed ou failed")
+	ErrWebhookEventInProgress = errors.New("evento de webhook em processamento, tente novamente em instantes")
+)
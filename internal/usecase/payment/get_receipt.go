@@ -0,0 +1,178 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// GetReceiptUseCase devolve o recibo de um pagamento aprovado, emitindo-o no primeiro
+// pedido. Só o aluno dono do pagamento e administradores têm acesso.
+type GetReceiptUseCase struct {
+	paymentRepo        repository.PaymentRepository
+	receiptRepo        repository.ReceiptRepository
+	userRepo           repository.UserRepository
+	enrollmentRepo     repository.EnrollmentRepository
+	classRepo          repository.ClassRepository
+	creditPackRepo     repository.CreditPackRepository
+	creditPurchaseRepo repository.CreditPurchaseRepository
+	membershipRepo     repository.MembershipRepository
+	membershipPlanRepo repository.MembershipPlanRepository
+	config             *config.Config
+}
+
+func NewGetReceiptUseCase(
+	paymentRepo repository.PaymentRepository,
+	receiptRepo repository.ReceiptRepository,
+	userRepo repository.UserRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	classRepo repository.ClassRepository,
+	creditPackRepo repository.CreditPackRepository,
+	creditPurchaseRepo repository.CreditPurchaseRepository,
+	membershipRepo repository.MembershipRepository,
+	membershipPlanRepo repository.MembershipPlanRepository,
+	config *config.Config,
+) *GetReceiptUseCase {
+	return &GetReceiptUseCase{
+		paymentRepo:        paymentRepo,
+		receiptRepo:        receiptRepo,
+		userRepo:           userRepo,
+		enrollmentRepo:     enrollmentRepo,
+		classRepo:          classRepo,
+		creditPackRepo:     creditPackRepo,
+		creditPurchaseRepo: creditPurchaseRepo,
+		membershipRepo:     membershipRepo,
+		membershipPlanRepo: membershipPlanRepo,
+		config:             config,
+	}
+}
+
+type GetReceiptInput struct {
+	PaymentID string
+	UserID    string
+	Role      entity.UserRole
+}
+
+func (uc *GetReceiptUseCase) Execute(ctx context.Context, input GetReceiptInput) (*entity.Receipt, error) {
+	id, err := primitive.ObjectIDFromHex(input.PaymentID)
+	if err != nil {
+		return nil, ErrInvalidPaymentID
+	}
+
+	paymentEntity, err := uc.paymentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, item, err := uc.describe(ctx, paymentEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Para outros alunos o pagamento simplesmente não existe.
+	if input.Role != entity.RoleAdmin && ownerID.Hex() != input.UserID {
+		return nil, repository.ErrPaymentNotFound
+	}
+
+	receipt, err := uc.receiptRepo.FindByPaymentID(ctx, id)
+	if err == nil {
+		return receipt, nil
+	}
+	if !errors.Is(err, repository.ErrReceiptNotFound) {
+		return nil, err
+	}
+
+	if !paymentEntity.IsPaid() {
+		return nil, ErrReceiptUnavailable
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt = entity.NewReceipt(paymentEntity, user, entity.ReceiptStudio{
+		Name:     uc.config.Studio.Name,
+		Document: uc.config.Studio.Document,
+		Address:  uc.config.Studio.Address,
+		Email:    uc.config.Studio.Email,
+	}, item)
+
+	return uc.issue(ctx, receipt)
+}
+
+// issue numera e grava o recibo na mesma transação. Se outra requisição emitiu o recibo do
+// mesmo pagamento ao mesmo tempo, a transação é desfeita (sem consumir o número) e o
+// recibo já gravado é devolvido.
+func (uc *GetReceiptUseCase) issue(ctx context.Context, receipt *entity.Receipt) (*entity.Receipt, error) {
+	err := uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		number, err := uc.receiptRepo.NextNumber(sc)
+		if err != nil {
+			return err
+		}
+		receipt.Number = number
+		return uc.receiptRepo.Create(sc, receipt)
+	})
+	if errors.Is(err, repository.ErrReceiptExists) {
+		return uc.receiptRepo.FindByPaymentID(ctx, receipt.PaymentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Recibo emitido",
+		zap.String("payment_id", receipt.PaymentID.Hex()),
+		zap.Int64("number", receipt.Number),
+	)
+
+	return receipt, nil
+}
+
+// describe identifica o aluno dono do pagamento e o item pago.
+func (uc *GetReceiptUseCase) describe(ctx context.Context, paymentEntity *entity.Payment) (primitive.ObjectID, entity.ReceiptItem, error) {
+	switch {
+	case paymentEntity.CreditPurchaseID != nil:
+		purchase, err := uc.creditPurchaseRepo.FindByID(ctx, *paymentEntity.CreditPurchaseID)
+		if err != nil {
+			return primitive.NilObjectID, entity.ReceiptItem{}, err
+		}
+		item := entity.ReceiptItem{Description: fmt.Sprintf("Pacote de %d créditos de aula", purchase.Credits)}
+		if pack, err := uc.creditPackRepo.FindByID(ctx, purchase.PackID); err == nil {
+			item.Details = pack.Name
+		}
+		return purchase.UserID, item, nil
+
+	case paymentEntity.MembershipID != nil:
+		membership, err := uc.membershipRepo.FindByID(ctx, *paymentEntity.MembershipID)
+		if err != nil {
+			return primitive.NilObjectID, entity.ReceiptItem{}, err
+		}
+		item := entity.ReceiptItem{Description: "Mensalidade de assinatura"}
+		if plan, err := uc.membershipPlanRepo.FindByID(ctx, membership.PlanID); err == nil {
+			item.Description = "Mensalidade do plano " + plan.Name
+		}
+		return membership.UserID, item, nil
+
+	default:
+		enrollment, err := uc.enrollmentRepo.FindByID(ctx, paymentEntity.EnrollmentID)
+		if err != nil {
+			return primitive.NilObjectID, entity.ReceiptItem{}, err
+		}
+		class, err := uc.classRepo.FindByID(ctx, enrollment.ClassID)
+		if err != nil {
+			return primitive.NilObjectID, entity.ReceiptItem{}, err
+		}
+		return enrollment.UserID, entity.ReceiptItem{
+			Description: "Aula: " + class.Title,
+			Details:     fmt.Sprintf("%s, com %s", entity.FormatLocalTime(class.StartTime), class.InstructorName),
+		}, nil
+	}
+}
@@ -0,0 +1,25 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+)
+
+const reconciliationListLimit = 20
+
+type ListReconciliationsUseCase struct {
+	reconciliationRepo repository.ReconciliationRepository
+}
+
+func NewListReconciliationsUseCase(reconciliationRepo repository.ReconciliationRepository) *ListReconciliationsUseCase {
+	return &ListReconciliationsUseCase{
+		reconciliationRepo: reconciliationRepo,
+	}
+}
+
+// Execute retorna os relatórios de conciliação mais recentes.
+func (uc *ListReconciliationsUseCase) Execute(ctx context.Context) ([]*entity.ReconciliationReport, error) {
+	return uc.reconciliationRepo.FindRecent(ctx, reconciliationListLimit)
+}
@@ -0,0 +1,48 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ListRefundsUseCase struct {
+	paymentRepo repository.PaymentRepository
+	refundRepo  repository.RefundRepository
+}
+
+func NewListRefundsUseCase(paymentRepo repository.PaymentRepository, refundRepo repository.RefundRepository) *ListRefundsUseCase {
+	return &ListRefundsUseCase{
+		paymentRepo: paymentRepo,
+		refundRepo:  refundRepo,
+	}
+}
+
+type ListRefundsOutput struct {
+	Payment *entity.Payment  `json:"payment"`
+	Refunds []*entity.Refund `json:"refunds"`
+}
+
+func (uc *ListRefundsUseCase) Execute(ctx context.Context, paymentID string) (*ListRefundsOutput, error) {
+	id, err := primitive.ObjectIDFromHex(paymentID)
+	if err != nil {
+		return nil, ErrInvalidPaymentID
+	}
+
+	paymentEntity, err := uc.paymentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := uc.refundRepo.FindByPaymentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if refunds == nil {
+		refunds = []*entity.Refund{}
+	}
+
+	return &ListRefundsOutput{Payment: paymentEntity, Refunds: refunds}, nil
+}
@@ -0,0 +1,47 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+)
+
+const (
+	webhookEventListLimit    = 50
+	webhookEventListMaxLimit = 200
+)
+
+type ListWebhookEventsUseCase struct {
+	webhookEventRepo repository.WebhookEventRepository
+}
+
+func NewListWebhookEventsUseCase(webhookEventRepo repository.WebhookEventRepository) *ListWebhookEventsUseCase {
+	return &ListWebhookEventsUseCase{
+		webhookEventRepo: webhookEventRepo,
+	}
+}
+
+type ListWebhookEventsInput struct {
+	Status string
+	Limit  int
+}
+
+// Execute retorna os eventos mais recentes, opcionalmente filtrados pelo status.
+func (uc *ListWebhookEventsUseCase) Execute(ctx context.Context, input ListWebhookEventsInput) ([]*entity.WebhookEvent, error) {
+	switch input.Status {
+	case "", entity.WebhookEventStatusPending, entity.WebhookEventStatusProcessed, entity.WebhookEventStatusFailed:
+	default:
+		return nil, ErrInvalidWebhookStatus
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = webhookEventListLimit
+	}
+	if limit > webhookEventListMaxLimit {
+		limit = webhookEventListMaxLimit
+	}
+
+	return uc.webhookEventRepo.FindRecent(ctx, input.Status, int64(limit))
+}
@@ -0,0 +1,134 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	refundLease     = 30 * time.Second
+	refundBatchSize = 20
+)
+
+// ProcessRefundOutboxUseCase envia ao gateway os estornos agendados com Schedule. Falhas
+// voltam para a fila com intervalo crescente; quando as tentativas se esgotam, o estorno
+// fica como failed para a equipe tratar manualmente.
+type ProcessRefundOutboxUseCase struct {
+	outboxRepo    repository.OutboxRepository
+	refundRepo    repository.RefundRepository
+	paymentRepo   repository.PaymentRepository
+	refundPayment *RefundPaymentUseCase
+	config        *config.Config
+}
+
+func NewProcessRefundOutboxUseCase(
+	outboxRepo repository.OutboxRepository,
+	refundRepo repository.RefundRepository,
+	paymentRepo repository.PaymentRepository,
+	refundPayment *RefundPaymentUseCase,
+	config *config.Config,
+) *ProcessRefundOutboxUseCase {
+	return &ProcessRefundOutboxUseCase{
+		outboxRepo:    outboxRepo,
+		refundRepo:    refundRepo,
+		paymentRepo:   paymentRepo,
+		refundPayment: refundPayment,
+		config:        config,
+	}
+}
+
+// Execute processa um lote de estornos pendentes; é o job do worker de estornos.
+func (uc *ProcessRefundOutboxUseCase) Execute(ctx context.Context) error {
+	for i := 0; i < refundBatchSize; i++ {
+		message, err := uc.outboxRepo.ClaimNext(ctx, entity.OutboxTypeRefund, time.Now(), refundLease)
+		if err != nil {
+			return err
+		}
+		if message == nil {
+			return nil
+		}
+
+		if _, err := uc.Process(ctx, message); err != nil {
+			logger.Warn("Falha ao processar estorno pendente",
+				zap.String("message_id", message.ID.Hex()),
+				zap.String("refund_id", message.AggregateID.Hex()),
+				zap.Int("attempts", message.Attempts),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// Process executa uma tentativa para a mensagem já reservada e devolve o estorno atualizado.
+func (uc *ProcessRefundOutboxUseCase) Process(ctx context.Context, message *entity.OutboxMessage) (*entity.Refund, error) {
+	refund, err := uc.refundRepo.FindByID(ctx, message.AggregateID)
+	if err != nil {
+		return nil, err
+	}
+	if refund == nil {
+		message.MarkFailed(fmt.Errorf("estorno não encontrado"))
+		return nil, uc.outboxRepo.Update(ctx, message)
+	}
+
+	// Estornos já concluídos, inclusive os registrados pelo webhook após uma resposta
+	// perdida do gateway, não são reenviados.
+	if refund.Status != entity.RefundStatusPending || refund.MercadoPagoID != "" {
+		message.MarkProcessed()
+		return refund, uc.outboxRepo.Update(ctx, message)
+	}
+
+	paymentEntity, err := uc.paymentRepo.FindByID(ctx, refund.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.refundPayment.send(ctx, paymentEntity, refund); err != nil {
+		return refund, uc.handleFailure(ctx, message, refund, err)
+	}
+
+	message.MarkProcessed()
+	if err := uc.outboxRepo.Update(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func (uc *ProcessRefundOutboxUseCase) handleFailure(ctx context.Context, message *entity.OutboxMessage, refund *entity.Refund, cause error) error {
+	if message.Attempts < uc.config.Worker.OutboxMaxAttempts {
+		backoff := time.Duration(message.Attempts*message.Attempts) * uc.config.Worker.OutboxInterval
+		message.RetryAt(cause, time.Now().Add(backoff))
+		if err := uc.outboxRepo.Update(ctx, message); err != nil {
+			return err
+		}
+		return cause
+	}
+
+	refund.Fail(cause.Error())
+	if err := uc.refundRepo.Update(ctx, refund); err != nil {
+		return err
+	}
+
+	message.MarkFailed(cause)
+	if err := uc.outboxRepo.Update(ctx, message); err != nil {
+		return err
+	}
+
+	logger.Error("Estorno descartado após esgotar as tentativas",
+		zap.String("refund_id", refund.ID.Hex()),
+		zap.String("payment_id", refund.PaymentID.Hex()),
+		zap.Int64("amount_in_cents", refund.AmountInCents),
+		zap.Int("attempts", message.Attempts),
+		zap.Error(cause),
+	)
+
+	return cause
+}
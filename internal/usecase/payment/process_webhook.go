@@ -2,75 +2,307 @@ package payment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/credit"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/membership"
+	"github.com/marcelobritu/isayoga-api/internal/usecase/waitlist"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
 
 type ProcessWebhookUseCase struct {
 	paymentRepo    repository.PaymentRepository
 	enrollmentRepo repository.EnrollmentRepository
+	classRepo      repository.ClassRepository
+	paymentGateway gateway.PaymentGateway
+	releaseSeat    *waitlist.ReleaseSeatUseCase
+	refunds        *RefundPaymentUseCase
+	settleCredits  *credit.SettleCreditPurchaseUseCase
+	membershipRepo repository.MembershipRepository
+	couponRepo     repository.CouponRepository
+	subscriptions  *membership.SyncSubscriptionUseCase
 }
 
 func NewProcessWebhookUseCase(
 	paymentRepo repository.PaymentRepository,
 	enrollmentRepo repository.EnrollmentRepository,
+	classRepo repository.ClassRepository,
+	paymentGateway gateway.PaymentGateway,
+	releaseSeat *waitlist.ReleaseSeatUseCase,
+	refunds *RefundPaymentUseCase,
+	settleCredits *credit.SettleCreditPurchaseUseCase,
+	membershipRepo repository.MembershipRepository,
+	couponRepo repository.CouponRepository,
+	subscriptions *membership.SyncSubscriptionUseCase,
 ) *ProcessWebhookUseCase {
 	return &ProcessWebhookUseCase{
 		paymentRepo:    paymentRepo,
 		enrollmentRepo: enrollmentRepo,
+		classRepo:      classRepo,
+		paymentGateway: paymentGateway,
+		releaseSeat:    releaseSeat,
+		refunds:        refunds,
+		settleCredits:  settleCredits,
+		membershipRepo: membershipRepo,
+		couponRepo:     couponRepo,
+		subscriptions:  subscriptions,
 	}
 }
 
 type WebhookInput struct {
-	Action   string `json:"action"`
-	Type     string `json:"type"`
-	DataID   string `json:"data.id"`
-	LiveMode bool   `json:"live_mode"`
+	Action   string      `json:"action"`
+	Type     string      `json:"type"`
+	Data     WebhookData `json:"data"`
+	LiveMode bool        `json:"live_mode"`
+}
+
+type WebhookData struct {
+	ID string `json:"id"`
 }
 
 func (uc *ProcessWebhookUseCase) Execute(ctx context.Context, input WebhookInput) error {
-	if input.Type != "payment" {
+	switch input.Type {
+	case "payment":
+	case "subscription_preapproval":
+		logger.Info("Processando webhook de assinatura",
+			zap.String("action", input.Action),
+			zap.String("preapproval_id", input.Data.ID),
+		)
+		return uc.subscriptions.Preapproval(ctx, input.Data.ID)
+	case "subscription_authorized_payment":
+		logger.Info("Processando webhook de cobrança de assinatura",
+			zap.String("action", input.Action),
+			zap.String("authorized_payment_id", input.Data.ID),
+		)
+		return uc.subscriptions.AuthorizedPayment(ctx, input.Data.ID)
+	default:
 		logger.Info("Webhook ignorado: tipo não suportado", zap.String("type", input.Type))
 		return nil
 	}
 
 	logger.Info("Processando webhook de pagamento",
 		zap.String("action", input.Action),
-		zap.String("payment_id", input.DataID),
+		zap.String("payment_id", input.Data.ID),
 	)
 
-	payment, err := uc.paymentRepo.FindByMercadoPagoID(ctx, input.DataID)
+	mpPayment, err := uc.paymentGateway.GetPayment(ctx, input.Data.ID)
 	if err != nil {
-		return fmt.Errorf("pagamento não encontrado: %w", err)
+		return fmt.Errorf("erro ao consultar pagamento no gateway: %w", err)
+	}
+
+	// Cobranças de assinatura são registradas pelo webhook da cobrança mensal; aqui só são
+	// atualizadas depois disso, inclusive os estornos.
+	paymentEntity, err := uc.paymentRepo.FindByMercadoPagoID(ctx, mpPayment.ID)
+	if err != nil && !errors.Is(err, repository.ErrPaymentNotFound) {
+		return err
+	}
+	if paymentEntity != nil && paymentEntity.MembershipID != nil {
+		return uc.updateMembershipPayment(ctx, paymentEntity, mpPayment)
 	}
 
-	enrollment, err := uc.enrollmentRepo.FindByID(ctx, payment.EnrollmentID)
+	externalRef, err := primitive.ObjectIDFromHex(mpPayment.ExternalRef)
+	if err != nil {
+		logger.Warn("Webhook ignorado: referência externa inválida",
+			zap.String("payment_id", mpPayment.ID),
+			zap.String("external_reference", mpPayment.ExternalRef),
+		)
+		return nil
+	}
+
+	paymentEntity, err = uc.findPayment(ctx, externalRef)
+	if errors.Is(err, repository.ErrPaymentNotFound) {
+		if _, findErr := uc.membershipRepo.FindByID(ctx, externalRef); findErr == nil {
+			logger.Info("Pagamento de assinatura aguardando o webhook da cobrança mensal",
+				zap.String("payment_id", mpPayment.ID),
+				zap.String("membership_id", externalRef.Hex()),
+			)
+			return nil
+		}
+	}
 	if err != nil {
 		return err
 	}
 
-	if input.Action == "payment.created" || input.Action == "payment.updated" {
-		status := "approved"
+	if mpPayment.Status == entity.PaymentStatusApproved && mpPayment.AmountInCents != paymentEntity.AmountInCents {
+		logger.Error("Valor pago diverge do valor da inscrição",
+			zap.String("payment_id", mpPayment.ID),
+			zap.Int64("expected_in_cents", paymentEntity.AmountInCents),
+			zap.Int64("paid_in_cents", mpPayment.AmountInCents),
+		)
+		return fmt.Errorf("valor do pagamento diverge do valor esperado")
+	}
+
+	paymentEntity.UpdateFromMercadoPago(mpPayment.ID, mpPayment.Status, mpPayment.PaymentMethod)
+
+	if paymentEntity.CreditPurchaseID != nil {
+		return uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+			if err := uc.refunds.Sync(sc, paymentEntity, mpPayment.Refunds); err != nil {
+				return err
+			}
+			if err := uc.paymentRepo.Update(sc, paymentEntity); err != nil {
+				return err
+			}
+			return uc.settleCredits.Execute(sc, paymentEntity)
+		})
+	}
 
-		payment.UpdateFromMercadoPago(input.DataID, status, "mercadopago")
-		if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+	var enrollment *entity.Enrollment
+	err = uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		// Estornos chegam como atualização do pagamento; os registros de estorno e o
+		// total devolvido são sincronizados com o que o gateway informa.
+		if err := uc.refunds.Sync(sc, paymentEntity, mpPayment.Refunds); err != nil {
 			return err
 		}
 
-		if status == "approved" && enrollment.Status == "pending" {
-			enrollment.Confirm(input.DataID)
-			if err := uc.enrollmentRepo.Update(ctx, enrollment); err != nil {
+		if err := uc.paymentRepo.Update(sc, paymentEntity); err != nil {
+			return err
+		}
+
+		enrollment, err = uc.enrollmentRepo.FindByID(sc, paymentEntity.EnrollmentID)
+		if err != nil {
+			return err
+		}
+
+		return uc.applyPaymentStatus(sc, paymentEntity, enrollment)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Inscrições confirmadas e depois canceladas já tiveram o estorno decidido no próprio
+	// cancelamento; aqui só entram pagamentos que nunca chegaram a confirmar a vaga.
+	if paymentEntity.IsApproved() && enrollment.PaymentID == "" && (enrollment.IsExpired() || enrollment.IsCancelled()) {
+		return uc.refundInactive(ctx, paymentEntity, enrollment)
+	}
+
+	return nil
+}
+
+// findPayment localiza o pagamento pela referência externa, que pode ser uma inscrição ou
+// uma compra de pacote de créditos.
+func (uc *ProcessWebhookUseCase) findPayment(ctx context.Context, externalRef primitive.ObjectID) (*entity.Payment, error) {
+	paymentEntity, err := uc.paymentRepo.FindByEnrollmentID(ctx, externalRef)
+	if err != nil || paymentEntity != nil {
+		return paymentEntity, err
+	}
+
+	paymentEntity, err = uc.paymentRepo.FindByCreditPurchaseID(ctx, externalRef)
+	if err != nil {
+		return nil, err
+	}
+	if paymentEntity == nil {
+		return nil, repository.ErrPaymentNotFound
+	}
+	return paymentEntity, nil
+}
+
+// updateMembershipPayment sincroniza o status e os estornos de uma cobrança de assinatura.
+// O período da assinatura não é alterado: o estorno de uma mensalidade é decidido pela
+// equipe do estúdio.
+func (uc *ProcessWebhookUseCase) updateMembershipPayment(ctx context.Context, paymentEntity *entity.Payment, mpPayment *gateway.PaymentInfo) error {
+	paymentEntity.UpdateFromMercadoPago(mpPayment.ID, mpPayment.Status, mpPayment.PaymentMethod)
+
+	err := uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		if err := uc.refunds.Sync(sc, paymentEntity, mpPayment.Refunds); err != nil {
+			return err
+		}
+		return uc.paymentRepo.Update(sc, paymentEntity)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Pagamento de assinatura atualizado",
+		zap.String("membership_id", paymentEntity.MembershipID.Hex()),
+		zap.String("payment_id", mpPayment.ID),
+		zap.String("status", paymentEntity.Status),
+	)
+	return nil
+}
+
+// refundInactive devolve pagamentos aprovados depois que a reserva da vaga já expirou
+// ou a inscrição foi cancelada (por exemplo, com o cancelamento da aula).
+func (uc *ProcessWebhookUseCase) refundInactive(ctx context.Context, paymentEntity *entity.Payment, enrollment *entity.Enrollment) error {
+	logger.Warn("Pagamento aprovado para inscrição inativa, solicitando estorno",
+		zap.String("enrollment_id", enrollment.ID.Hex()),
+		zap.String("payment_id", paymentEntity.MercadoPagoID),
+		zap.String("enrollment_status", enrollment.Status),
+	)
+
+	if _, err := uc.refunds.Refund(ctx, paymentEntity, 0, "pagamento aprovado para inscrição inativa", nil); err != nil {
+		return fmt.Errorf("erro ao estornar pagamento de inscrição inativa: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *ProcessWebhookUseCase) applyPaymentStatus(ctx context.Context, paymentEntity *entity.Payment, enrollment *entity.Enrollment) error {
+	switch {
+	case paymentEntity.IsApproved() && enrollment.IsPending():
+		enrollment.Confirm(paymentEntity.MercadoPagoID)
+		if err := uc.enrollmentRepo.Update(ctx, enrollment); err != nil {
+			return err
+		}
+		if err := uc.releaseSeat.CloseOffer(ctx, enrollment.ID, true); err != nil {
+			return err
+		}
+
+		logger.Info("Inscrição confirmada com sucesso",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("payment_id", paymentEntity.MercadoPagoID),
+		)
+
+	case paymentEntity.IsFailed() && enrollment.IsPending():
+		enrollment.Reject()
+		if err := uc.enrollmentRepo.Update(ctx, enrollment); err != nil {
+			return err
+		}
+		if err := uc.releaseSeat.CloseOffer(ctx, enrollment.ID, false); err != nil {
+			return err
+		}
+		if err := uc.releaseSeat.Execute(ctx, enrollment.ClassID); err != nil {
+			return err
+		}
+		if paymentEntity.CouponID != nil {
+			if _, err := uc.couponRepo.ReleaseByEnrollment(ctx, enrollment.ID); err != nil {
 				return err
 			}
+		}
 
-			logger.Info("Inscrição confirmada com sucesso",
-				zap.String("enrollment_id", enrollment.ID.Hex()),
-				zap.String("payment_id", input.DataID),
-			)
+		logger.Info("Inscrição rejeitada e vaga liberada",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("payment_id", paymentEntity.MercadoPagoID),
+			zap.String("status", paymentEntity.Status),
+		)
+
+	case paymentEntity.IsReversed() && (enrollment.IsPending() || enrollment.IsConfirmed()):
+		enrollment.Cancel()
+		if err := uc.enrollmentRepo.Update(ctx, enrollment); err != nil {
+			return err
+		}
+		if err := uc.releaseSeat.Execute(ctx, enrollment.ClassID); err != nil {
+			return err
 		}
+
+		logger.Info("Inscrição cancelada por estorno do pagamento",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("payment_id", paymentEntity.MercadoPagoID),
+			zap.String("status", paymentEntity.Status),
+		)
+
+	default:
+		logger.Info("Status de pagamento atualizado",
+			zap.String("enrollment_id", enrollment.ID.Hex()),
+			zap.String("payment_id", paymentEntity.MercadoPagoID),
+			zap.String("status", paymentEntity.Status),
+		)
 	}
 
 	return nil
@@ -0,0 +1,102 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	webhookEventLease     = time.Minute
+	webhookEventBatchSize = 50
+)
+
+// ProcessWebhookEventUseCase processa os eventos de webhook gravados. O processamento
+// sempre consulta o estado atual no gateway, então eventos repetidos ou fora de ordem
+// levam ao mesmo resultado; falhas voltam para a fila com intervalo crescente até
+// WORKER_WEBHOOK_MAX_ATTEMPTS.
+type ProcessWebhookEventUseCase struct {
+	webhookEventRepo repository.WebhookEventRepository
+	webhook          *ProcessWebhookUseCase
+	config           *config.Config
+}
+
+func NewProcessWebhookEventUseCase(
+	webhookEventRepo repository.WebhookEventRepository,
+	webhook *ProcessWebhookUseCase,
+	config *config.Config,
+) *ProcessWebhookEventUseCase {
+	return &ProcessWebhookEventUseCase{
+		webhookEventRepo: webhookEventRepo,
+		webhook:          webhook,
+		config:           config,
+	}
+}
+
+// Execute processa um lote de eventos pendentes; é o job do worker de webhooks.
+func (uc *ProcessWebhookEventUseCase) Execute(ctx context.Context) error {
+	for i := 0; i < webhookEventBatchSize; i++ {
+		event, err := uc.webhookEventRepo.ClaimNext(ctx, time.Now(), webhookEventLease)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return nil
+		}
+
+		if err := uc.Process(ctx, event); err != nil {
+			logger.Warn("Falha ao reprocessar evento de webhook",
+				zap.String("event_id", event.ID.Hex()),
+				zap.String("type", event.Type),
+				zap.String("data_id", event.DataID),
+				zap.Int("attempts", event.Attempts),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// Process executa uma tentativa para o evento já reservado e registra o resultado.
+// Devolve o erro do processamento, se houver.
+func (uc *ProcessWebhookEventUseCase) Process(ctx context.Context, event *entity.WebhookEvent) error {
+	var input WebhookInput
+	if err := json.Unmarshal([]byte(event.Payload), &input); err != nil {
+		cause := fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+		event.MarkFailed(cause)
+		if err := uc.webhookEventRepo.Update(ctx, event); err != nil {
+			return err
+		}
+		return cause
+	}
+
+	cause := uc.webhook.Execute(ctx, input)
+	switch {
+	case cause == nil:
+		event.MarkProcessed()
+	case event.Attempts < uc.config.Worker.WebhookMaxAttempts:
+		backoff := time.Duration(event.Attempts*event.Attempts) * uc.config.Worker.WebhookInterval
+		event.RetryAt(cause, time.Now().Add(backoff))
+	default:
+		event.MarkFailed(cause)
+		logger.Error("Evento de webhook descartado após esgotar as tentativas",
+			zap.String("event_id", event.ID.Hex()),
+			zap.String("type", event.Type),
+			zap.String("data_id", event.DataID),
+			zap.Int("attempts", event.Attempts),
+			zap.Error(cause),
+		)
+	}
+
+	if err := uc.webhookEventRepo.Update(ctx, event); err != nil {
+		return err
+	}
+	return cause
+}
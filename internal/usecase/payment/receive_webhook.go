@@ -0,0 +1,108 @@
+package payment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ReceiveWebhookUseCase grava cada notificação recebida antes de processá-la. Entregas
+// repetidas da mesma notificação são reconhecidas pela chave de deduplicação e
+// confirmadas sem reprocessamento; falhas ficam para o worker de webhooks.
+type ReceiveWebhookUseCase struct {
+	webhookEventRepo repository.WebhookEventRepository
+	processEvent     *ProcessWebhookEventUseCase
+}
+
+func NewReceiveWebhookUseCase(
+	webhookEventRepo repository.WebhookEventRepository,
+	processEvent *ProcessWebhookEventUseCase,
+) *ReceiveWebhookUseCase {
+	return &ReceiveWebhookUseCase{
+		webhookEventRepo: webhookEventRepo,
+		processEvent:     processEvent,
+	}
+}
+
+type ReceiveWebhookInput struct {
+	Payload   []byte
+	RequestID string
+}
+
+// webhookEnvelope inclui o id da notificação, que o Mercado Pago mantém nas retentativas.
+type webhookEnvelope struct {
+	ID json.RawMessage `json:"id"`
+	WebhookInput
+}
+
+// Execute devolve erro apenas quando o evento não pôde ser gravado; falhas no
+// processamento ficam registradas no evento e são tentadas novamente pelo worker.
+func (uc *ReceiveWebhookUseCase) Execute(ctx context.Context, input ReceiveWebhookInput) error {
+	var envelope webhookEnvelope
+	if err := json.Unmarshal(input.Payload, &envelope); err != nil {
+		return ErrInvalidWebhookPayload
+	}
+
+	event := entity.NewWebhookEvent(
+		entity.WebhookSourceMercadoPago,
+		webhookDedupKey(envelope, input),
+		envelope.Type,
+		envelope.Action,
+		envelope.Data.ID,
+		input.RequestID,
+		input.Payload,
+	)
+	// O evento nasce reservado para esta requisição, para o worker não processá-lo junto.
+	event.Lease(time.Now().Add(webhookEventLease))
+
+	err := uc.webhookEventRepo.Create(ctx, event)
+	if errors.Is(err, repository.ErrWebhookEventExists) {
+		existing, findErr := uc.webhookEventRepo.FindByDedupKey(ctx, event.DedupKey)
+		if findErr != nil {
+			return findErr
+		}
+		logger.Info("Webhook duplicado ignorado",
+			zap.String("event_id", existing.ID.Hex()),
+			zap.String("dedup_key", existing.DedupKey),
+			zap.String("status", existing.Status),
+		)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := uc.processEvent.Process(ctx, event); err != nil {
+		logger.Error("Erro ao processar webhook; nova tentativa agendada",
+			zap.String("event_id", event.ID.Hex()),
+			zap.String("type", event.Type),
+			zap.String("data_id", event.DataID),
+			zap.Error(err),
+		)
+	}
+
+	return nil
+}
+
+// webhookDedupKey identifica a notificação pelo id enviado pelo Mercado Pago, pelo
+// x-request-id ou, na falta dos dois, pelo hash do corpo.
+func webhookDedupKey(envelope webhookEnvelope, input ReceiveWebhookInput) string {
+	notificationID := strings.Trim(string(envelope.ID), `"`)
+	if notificationID != "" && notificationID != "null" {
+		return "notification:" + notificationID
+	}
+	if input.RequestID != "" {
+		return "request:" + input.RequestID
+	}
+	sum := sha256.Sum256(input.Payload)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,257 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const reconcileBatchSize = 200
+
+// ReconcilePaymentsUseCase compara os pagamentos recentes com o gateway, buscando as
+// tentativas de pagamento pela referência externa, e corrige o estado local quando um
+// webhook se perdeu. As correções reaproveitam o processamento do webhook, de modo que
+// inscrições, vagas, créditos e cupons seguem exatamente as mesmas regras.
+type ReconcilePaymentsUseCase struct {
+	paymentRepo        repository.PaymentRepository
+	reconciliationRepo repository.ReconciliationRepository
+	paymentGateway     gateway.PaymentGateway
+	webhook            *ProcessWebhookUseCase
+	config             *config.Config
+}
+
+func NewReconcilePaymentsUseCase(
+	paymentRepo repository.PaymentRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+	paymentGateway gateway.PaymentGateway,
+	webhook *ProcessWebhookUseCase,
+	config *config.Config,
+) *ReconcilePaymentsUseCase {
+	return &ReconcilePaymentsUseCase{
+		paymentRepo:        paymentRepo,
+		reconciliationRepo: reconciliationRepo,
+		paymentGateway:     paymentGateway,
+		webhook:            webhook,
+		config:             config,
+	}
+}
+
+// Execute é a execução periódica do worker. Só grava o relatório quando há divergências.
+func (uc *ReconcilePaymentsUseCase) Execute(ctx context.Context) error {
+	report, err := uc.reconcile(ctx, entity.ReconciliationTriggerWorker, nil)
+	if err != nil {
+		return err
+	}
+	if len(report.Discrepancies) == 0 {
+		return nil
+	}
+	return uc.reconciliationRepo.Create(ctx, report)
+}
+
+// Run executa a conciliação a pedido da equipe e grava o relatório.
+func (uc *ReconcilePaymentsUseCase) Run(ctx context.Context, actorID string) (*entity.ReconciliationReport, error) {
+	requestedBy, err := primitive.ObjectIDFromHex(actorID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	report, err := uc.reconcile(ctx, entity.ReconciliationTriggerManual, &requestedBy)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.reconciliationRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (uc *ReconcilePaymentsUseCase) reconcile(ctx context.Context, trigger string, requestedBy *primitive.ObjectID) (*entity.ReconciliationReport, error) {
+	since := time.Now().Add(-uc.config.Payment.ReconcileLookback)
+	report := entity.NewReconciliationReport(trigger, requestedBy, since)
+
+	payments, err := uc.paymentRepo.FindForReconciliation(ctx, since, reconcileBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, paymentEntity := range payments {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		discrepancy, err := uc.reconcilePayment(ctx, paymentEntity)
+		if err != nil {
+			logger.Error("Erro ao conciliar pagamento",
+				zap.String("payment_id", paymentEntity.ID.Hex()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		report.Checked++
+		if discrepancy != nil {
+			report.Add(*discrepancy)
+		}
+	}
+
+	report.Finish()
+
+	logger.Info("Conciliação de pagamentos concluída",
+		zap.String("trigger", trigger),
+		zap.Int("checked", report.Checked),
+		zap.Int("discrepancies", len(report.Discrepancies)),
+		zap.Int("corrected", report.Corrected),
+		zap.Int("unresolved", report.Unresolved),
+	)
+
+	return report, nil
+}
+
+// reconcilePayment compara um pagamento com as tentativas registradas no gateway e, quando
+// o gateway tem a palavra final, aplica o status dele como se o webhook tivesse chegado.
+func (uc *ReconcilePaymentsUseCase) reconcilePayment(ctx context.Context, paymentEntity *entity.Payment) (*entity.PaymentDiscrepancy, error) {
+	mpPayments, err := uc.paymentGateway.SearchPayments(ctx, paymentEntity.ExternalRef())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pagamentos no gateway: %w", err)
+	}
+
+	mpPayment := selectGatewayPayment(mpPayments, paymentEntity)
+	kind, apply := classifyDiscrepancy(paymentEntity, mpPayment)
+	if kind == "" {
+		return nil, nil
+	}
+
+	discrepancy := &entity.PaymentDiscrepancy{
+		PaymentID:          paymentEntity.ID,
+		ExternalRef:        paymentEntity.ExternalRef(),
+		MercadoPagoID:      paymentEntity.MercadoPagoID,
+		Kind:               kind,
+		LocalStatus:        paymentEntity.Status,
+		LocalAmountInCents: paymentEntity.AmountInCents,
+	}
+	if mpPayment != nil {
+		discrepancy.MercadoPagoID = mpPayment.ID
+		discrepancy.GatewayStatus = mpPayment.Status
+		discrepancy.GatewayAmountInCents = mpPayment.AmountInCents
+	}
+
+	switch {
+	case mpPayment == nil:
+		discrepancy.Error = "nenhum pagamento encontrado no gateway"
+	case !apply:
+		discrepancy.Error = "status do gateway não confirma o pagamento; verifique manualmente"
+	default:
+		err := uc.webhook.Execute(ctx, WebhookInput{
+			Action: "payment.reconciled",
+			Type:   "payment",
+			Data:   WebhookData{ID: mpPayment.ID},
+		})
+		if err != nil {
+			discrepancy.Error = err.Error()
+		} else {
+			discrepancy.Corrected = true
+		}
+	}
+
+	logger.Warn("Divergência de pagamento encontrada na conciliação",
+		zap.String("payment_id", paymentEntity.ID.Hex()),
+		zap.String("external_reference", discrepancy.ExternalRef),
+		zap.String("kind", kind),
+		zap.String("local_status", discrepancy.LocalStatus),
+		zap.String("gateway_status", discrepancy.GatewayStatus),
+		zap.Bool("corrected", discrepancy.Corrected),
+	)
+
+	return discrepancy, nil
+}
+
+// selectGatewayPayment escolhe, entre as tentativas com a mesma referência, a que define o
+// estado do pagamento: a que chegou a ser paga, depois a já associada localmente e, por
+// fim, a mais recente.
+func selectGatewayPayment(mpPayments []*gateway.PaymentInfo, paymentEntity *entity.Payment) *gateway.PaymentInfo {
+	for _, mpPayment := range mpPayments {
+		if gatewayPaid(mpPayment.Status) {
+			return mpPayment
+		}
+	}
+	for _, mpPayment := range mpPayments {
+		if mpPayment.ID == paymentEntity.MercadoPagoID {
+			return mpPayment
+		}
+	}
+	if len(mpPayments) > 0 {
+		return mpPayments[0]
+	}
+	return nil
+}
+
+// classifyDiscrepancy devolve o tipo da divergência (vazio se não houver) e se o status do
+// gateway pode ser aplicado automaticamente. Um pagamento confirmado localmente que o
+// gateway não aprovou nunca é desfeito automaticamente: a vaga continua com o aluno até a
+// equipe verificar.
+func classifyDiscrepancy(paymentEntity *entity.Payment, mpPayment *gateway.PaymentInfo) (string, bool) {
+	localPaid := paymentEntity.IsPaid()
+
+	if mpPayment == nil {
+		if localPaid {
+			return entity.DiscrepancyConfirmedNotPaid, false
+		}
+		return "", false
+	}
+
+	switch {
+	case gatewayStatus(mpPayment) == paymentEntity.Status:
+		return "", false
+	case gatewayAwaiting(mpPayment.Status):
+		if localPaid {
+			return entity.DiscrepancyConfirmedNotPaid, false
+		}
+		return "", false
+	case gatewayFailed(mpPayment.Status):
+		if localPaid {
+			return entity.DiscrepancyConfirmedNotPaid, false
+		}
+		if paymentEntity.IsAwaiting() {
+			return entity.DiscrepancyStatusMismatch, true
+		}
+		return "", false
+	case mpPayment.Status == entity.PaymentStatusApproved && !localPaid:
+		return entity.DiscrepancyPaidNotConfirmed, true
+	default:
+		return entity.DiscrepancyStatusMismatch, true
+	}
+}
+
+// gatewayStatus traduz o status do gateway para o status local equivalente: estornos
+// parciais mantêm o pagamento aprovado no Mercado Pago.
+func gatewayStatus(mpPayment *gateway.PaymentInfo) string {
+	if mpPayment.Status == entity.PaymentStatusApproved && mpPayment.RefundedInCents > 0 {
+		if mpPayment.RefundedInCents >= mpPayment.AmountInCents {
+			return entity.PaymentStatusRefunded
+		}
+		return entity.PaymentStatusPartiallyRefunded
+	}
+	return mpPayment.Status
+}
+
+func gatewayPaid(status string) bool {
+	return status == entity.PaymentStatusApproved ||
+		status == entity.PaymentStatusRefunded ||
+		status == entity.PaymentStatusChargedBack
+}
+
+func gatewayAwaiting(status string) bool {
+	return status == entity.PaymentStatusPending || status == entity.PaymentStatusInProcess
+}
+
+func gatewayFailed(status string) bool {
+	return status == entity.PaymentStatusRejected || status == entity.PaymentStatusCancelled
+}
@@ -0,0 +1,311 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/gateway"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// RefundPaymentUseCase estorna pagamentos aprovados no gateway, total ou parcialmente, e
+// mantém um registro por estorno. O total devolvido no pagamento é sempre recalculado a
+// partir desses registros, então o pedido local e o webhook do estorno podem chegar em
+// qualquer ordem.
+type RefundPaymentUseCase struct {
+	paymentRepo    repository.PaymentRepository
+	refundRepo     repository.RefundRepository
+	outboxRepo     repository.OutboxRepository
+	classRepo      repository.ClassRepository
+	paymentGateway gateway.PaymentGateway
+}
+
+func NewRefundPaymentUseCase(
+	paymentRepo repository.PaymentRepository,
+	refundRepo repository.RefundRepository,
+	outboxRepo repository.OutboxRepository,
+	classRepo repository.ClassRepository,
+	paymentGateway gateway.PaymentGateway,
+) *RefundPaymentUseCase {
+	return &RefundPaymentUseCase{
+		paymentRepo:    paymentRepo,
+		refundRepo:     refundRepo,
+		outboxRepo:     outboxRepo,
+		classRepo:      classRepo,
+		paymentGateway: paymentGateway,
+	}
+}
+
+// RefundPaymentInput descreve um estorno pedido por um administrador. AmountInCents igual a
+// zero estorna todo o saldo restante do pagamento.
+type RefundPaymentInput struct {
+	PaymentID     string `json:"-"`
+	ActorID       string `json:"-"`
+	AmountInCents int64  `json:"amount_in_cents"`
+	Reason        string `json:"reason"`
+}
+
+type RefundPaymentOutput struct {
+	Payment *entity.Payment `json:"payment"`
+	Refund  *entity.Refund  `json:"refund"`
+}
+
+func (uc *RefundPaymentUseCase) Execute(ctx context.Context, input RefundPaymentInput) (*RefundPaymentOutput, error) {
+	paymentID, err := primitive.ObjectIDFromHex(input.PaymentID)
+	if err != nil {
+		return nil, ErrInvalidPaymentID
+	}
+
+	actorID, err := primitive.ObjectIDFromHex(input.ActorID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	paymentEntity, err := uc.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := uc.Refund(ctx, paymentEntity, input.AmountInCents, input.Reason, &actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefundPaymentOutput{Payment: paymentEntity, Refund: refund}, nil
+}
+
+// Refund estorna amountInCents do pagamento (zero estorna o saldo restante) e aguarda a
+// resposta do gateway. É usado pela rota administrativa e pelo webhook; cancelamentos usam
+// Schedule para que o estorno sobreviva a falhas do gateway. Em caso de sucesso,
+// paymentEntity reflete o novo status.
+func (uc *RefundPaymentUseCase) Refund(ctx context.Context, paymentEntity *entity.Payment, amountInCents int64, reason string, requestedBy *primitive.ObjectID) (*entity.Refund, error) {
+	var refund *entity.Refund
+	err := uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		var err error
+		refund, err = uc.reserve(sc, paymentEntity, amountInCents, reason, requestedBy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.send(ctx, paymentEntity, refund); err != nil {
+		if errors.Is(err, ErrRefundFailed) {
+			refund.Fail(err.Error())
+			if updateErr := uc.refundRepo.Update(ctx, refund); updateErr != nil {
+				logger.Error("Erro ao registrar falha do estorno",
+					zap.String("refund_id", refund.ID.Hex()),
+					zap.Error(updateErr),
+				)
+			}
+		}
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// Schedule registra o estorno como pendente junto com a mensagem de outbox que o envia ao
+// gateway. Deve ser chamado dentro da transação que originou o estorno; a mensagem já sai
+// reservada para que quem chamou a processe logo após o commit, e o worker de estornos
+// assume as novas tentativas.
+func (uc *RefundPaymentUseCase) Schedule(ctx context.Context, paymentEntity *entity.Payment, amountInCents int64, reason string, requestedBy *primitive.ObjectID) (*entity.Refund, *entity.OutboxMessage, error) {
+	refund, err := uc.reserve(ctx, paymentEntity, amountInCents, reason, requestedBy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	message := entity.NewOutboxMessage(entity.OutboxTypeRefund, refund.ID)
+	message.Lease(time.Now().Add(refundLease))
+	if err := uc.outboxRepo.Create(ctx, message); err != nil {
+		return nil, nil, err
+	}
+
+	return refund, message, nil
+}
+
+// reserve grava o estorno como pendente, o que reserva o valor até a resposta do gateway:
+// o saldo disponível desconta os estornos ainda pendentes. Deve rodar em uma transação; o
+// pagamento é regravado nela para que pedidos simultâneos entrem em conflito e sejam
+// reavaliados com o saldo atualizado, em vez de estornarem o mesmo valor duas vezes.
+func (uc *RefundPaymentUseCase) reserve(ctx context.Context, paymentEntity *entity.Payment, amountInCents int64, reason string, requestedBy *primitive.ObjectID) (*entity.Refund, error) {
+	current, err := uc.paymentRepo.FindByID(ctx, paymentEntity.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := uc.refundRepo.FindByPaymentID(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refundable := current.RefundableInCents() - pendingTotal(refunds)
+	if refundable <= 0 {
+		return nil, ErrNotRefundable
+	}
+	if amountInCents < 0 || amountInCents > refundable {
+		return nil, ErrInvalidRefundAmount
+	}
+	if amountInCents == 0 {
+		amountInCents = refundable
+	}
+
+	refund := entity.NewRefund(current.ID, amountInCents, reason, requestedBy)
+	if err := uc.refundRepo.Create(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	current.UpdatedAt = time.Now()
+	if err := uc.paymentRepo.Update(ctx, current); err != nil {
+		return nil, err
+	}
+
+	*paymentEntity = *current
+	return refund, nil
+}
+
+// send pede o estorno ao gateway e recalcula o total devolvido no pagamento. Recusas do
+// gateway são devolvidas como ErrRefundFailed.
+func (uc *RefundPaymentUseCase) send(ctx context.Context, paymentEntity *entity.Payment, refund *entity.Refund) error {
+	// O estorno integral é enviado sem valor para que o gateway devolva o pagamento completo.
+	requested := refund.AmountInCents
+	if refund.AmountInCents == paymentEntity.AmountInCents {
+		requested = 0
+	}
+
+	// O id do registro local identifica o estorno no gateway, então novas tentativas após
+	// uma resposta perdida não devolvem o valor de novo.
+	info, err := uc.paymentGateway.Refund(ctx, paymentEntity.MercadoPagoID, requested, refund.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRefundFailed, err)
+	}
+
+	refund.UpdateFromMercadoPago(info.ID, info.Status, info.AmountInCents)
+
+	err = uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		if err := uc.refundRepo.Update(sc, refund); err != nil {
+			return err
+		}
+
+		current, err := uc.paymentRepo.FindByID(sc, paymentEntity.ID)
+		if err != nil {
+			return err
+		}
+
+		refunds, err := uc.refundRepo.FindByPaymentID(sc, current.ID)
+		if err != nil {
+			return err
+		}
+
+		current.SetRefunded(refundedTotal(refunds))
+		if err := uc.paymentRepo.Update(sc, current); err != nil {
+			return err
+		}
+
+		*paymentEntity = *current
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Pagamento estornado",
+		zap.String("payment_id", paymentEntity.ID.Hex()),
+		zap.String("refund_id", refund.MercadoPagoID),
+		zap.Int64("amount_in_cents", refund.AmountInCents),
+		zap.String("payment_status", paymentEntity.Status),
+	)
+
+	return nil
+}
+
+// Sync registra os estornos informados pelo gateway para o pagamento, inclusive os feitos
+// fora da API, e atualiza o total devolvido em paymentEntity. Não persiste o pagamento.
+func (uc *RefundPaymentUseCase) Sync(ctx context.Context, paymentEntity *entity.Payment, infos []gateway.RefundInfo) error {
+	if len(infos) == 0 {
+		return nil
+	}
+
+	refunds, err := uc.refundRepo.FindByPaymentID(ctx, paymentEntity.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		refund := matchRefund(refunds, info)
+		if refund == nil {
+			refund = entity.NewRefund(paymentEntity.ID, info.AmountInCents, "", nil)
+			refund.UpdateFromMercadoPago(info.ID, info.Status, info.AmountInCents)
+			if err := uc.refundRepo.Create(ctx, refund); err != nil {
+				return err
+			}
+			refunds = append(refunds, refund)
+
+			logger.Info("Estorno feito fora da API registrado",
+				zap.String("payment_id", paymentEntity.ID.Hex()),
+				zap.String("refund_id", info.ID),
+				zap.Int64("amount_in_cents", info.AmountInCents),
+			)
+			continue
+		}
+
+		if refund.MercadoPagoID == info.ID && refund.Status == info.Status {
+			continue
+		}
+
+		refund.UpdateFromMercadoPago(info.ID, info.Status, info.AmountInCents)
+		if err := uc.refundRepo.Update(ctx, refund); err != nil {
+			return err
+		}
+	}
+
+	paymentEntity.SetRefunded(refundedTotal(refunds))
+	return nil
+}
+
+// matchRefund localiza o registro local de um estorno do gateway. Um pedido ainda sem id do
+// gateway (pendente ou que falhou por timeout) é associado pelo valor.
+func matchRefund(refunds []*entity.Refund, info gateway.RefundInfo) *entity.Refund {
+	for _, refund := range refunds {
+		if refund.MercadoPagoID == info.ID {
+			return refund
+		}
+	}
+
+	for _, refund := range refunds {
+		if refund.MercadoPagoID == "" && refund.AmountInCents == info.AmountInCents &&
+			(refund.Status == entity.RefundStatusPending || refund.Status == entity.RefundStatusFailed) {
+			return refund
+		}
+	}
+
+	return nil
+}
+
+// pendingTotal soma os estornos enviados ao gateway que ainda aguardam resposta.
+func pendingTotal(refunds []*entity.Refund) int64 {
+	var total int64
+	for _, refund := range refunds {
+		if refund.Status == entity.RefundStatusPending {
+			total += refund.AmountInCents
+		}
+	}
+	return total
+}
+
+func refundedTotal(refunds []*entity.Refund) int64 {
+	var total int64
+	for _, refund := range refunds {
+		if refund.Counts() {
+			total += refund.AmountInCents
+		}
+	}
+	return total
+}
@@ -0,0 +1,77 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// ReplayWebhookEventUseCase reprocessa um evento a pedido da equipe, em qualquer status,
+// inclusive eventos já processados ou com as tentativas esgotadas.
+type ReplayWebhookEventUseCase struct {
+	webhookEventRepo repository.WebhookEventRepository
+	processEvent     *ProcessWebhookEventUseCase
+}
+
+func NewReplayWebhookEventUseCase(
+	webhookEventRepo repository.WebhookEventRepository,
+	processEvent *ProcessWebhookEventUseCase,
+) *ReplayWebhookEventUseCase {
+	return &ReplayWebhookEventUseCase{
+		webhookEventRepo: webhookEventRepo,
+		processEvent:     processEvent,
+	}
+}
+
+type ReplayWebhookEventInput struct {
+	EventID string
+	ActorID string
+}
+
+// Execute devolve o evento com o resultado do reprocessamento. O replay reinicia a contagem
+// de tentativas, então uma falha fica registrada em last_error e o evento volta para a fila
+// de novas tentativas mesmo que as anteriores já tivessem se esgotado.
+func (uc *ReplayWebhookEventUseCase) Execute(ctx context.Context, input ReplayWebhookEventInput) (*entity.WebhookEvent, error) {
+	eventID, err := primitive.ObjectIDFromHex(input.EventID)
+	if err != nil {
+		return nil, ErrInvalidWebhookEventID
+	}
+	actorID, err := primitive.ObjectIDFromHex(input.ActorID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if _, err := uc.webhookEventRepo.FindByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	event, err := uc.webhookEventRepo.Claim(ctx, eventID, time.Now(), webhookEventLease)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrWebhookEventInProgress
+	}
+
+	event.Replay(actorID)
+	if err := uc.processEvent.Process(ctx, event); err != nil {
+		logger.Warn("Falha ao reprocessar evento de webhook",
+			zap.String("event_id", event.ID.Hex()),
+			zap.String("actor_id", input.ActorID),
+			zap.Error(err),
+		)
+		return event, nil
+	}
+
+	logger.Info("Evento de webhook reprocessado",
+		zap.String("event_id", event.ID.Hex()),
+		zap.String("actor_id", input.ActorID),
+	)
+
+	return event, nil
+}
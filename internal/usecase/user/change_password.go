@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,12 +19,14 @@ type ChangePasswordInput struct {
 }
 
 type ChangePasswordUseCase struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
 }
 
-func NewChangePasswordUseCase(userRepo repository.UserRepository) *ChangePasswordUseCase {
+func NewChangePasswordUseCase(userRepo repository.UserRepository, sessionRepo repository.SessionRepository) *ChangePasswordUseCase {
 	return &ChangePasswordUseCase{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
 	}
 }
 
@@ -74,6 +77,15 @@ func (uc *ChangePasswordUseCase) Execute(ctx context.Context, input ChangePasswo
 		return fmt.Errorf("erro ao atualizar senha")
 	}
 
+	// Encerra todas as sessões, inclusive a atual: quem tinha a senha antiga perde o acesso.
+	if err := revokeTokens(ctx, uc.userRepo, uc.sessionRepo, user.ID, entity.SessionRevokedPasswordChanged); err != nil {
+		logger.Error("Erro ao revogar tokens após alteração de senha",
+			zap.Error(err),
+			zap.String("user_id", user.ID.Hex()),
+		)
+		return fmt.Errorf("erro ao revogar sessões do usuário")
+	}
+
 	logger.Info("Senha alterada com sucesso",
 		zap.String("user_id", user.ID.Hex()),
 		zap.String("email", user.Email),
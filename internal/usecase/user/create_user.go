@@ -16,6 +16,7 @@ type CreateUserInput struct {
 	Email    string          `json:"email"`
 	Password string          `json:"password"`
 	Role     entity.UserRole `json:"role"`
+	CPF      string          `json:"cpf"`
 }
 
 type CreateUserUseCase struct {
@@ -51,11 +52,17 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput)
 		return nil, fmt.Errorf("role inválido: deve ser student, instructor ou admin")
 	}
 
+	cpf, valid := entity.NormalizeCPF(input.CPF)
+	if !valid {
+		return nil, fmt.Errorf("CPF inválido")
+	}
+
 	user, err := entity.NewUser(input.Name, input.Email, input.Password, input.Role)
 	if err != nil {
 		logger.Error("Erro ao criar hash da senha", zap.Error(err))
 		return nil, fmt.Errorf("erro ao criar usuário")
 	}
+	user.CPF = cpf
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
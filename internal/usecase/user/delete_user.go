@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
 	"github.com/marcelobritu/isayoga-api/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,12 +13,14 @@ import (
 )
 
 type DeleteUserUseCase struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
 }
 
-func NewDeleteUserUseCase(userRepo repository.UserRepository) *DeleteUserUseCase {
+func NewDeleteUserUseCase(userRepo repository.UserRepository, sessionRepo repository.SessionRepository) *DeleteUserUseCase {
 	return &DeleteUserUseCase{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
 	}
 }
 
@@ -31,6 +34,16 @@ func (uc *DeleteUserUseCase) Execute(ctx context.Context, id string) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	// A versão é incrementada antes da remoção para que nenhum token do usuário continue
+	// válido caso a remoção falhe no meio do caminho.
+	if err := revokeTokens(ctx, uc.userRepo, uc.sessionRepo, objectID, entity.SessionRevokedUserDeleted); err != nil {
+		logger.Error("Erro ao revogar tokens do usuário",
+			zap.Error(err),
+			zap.String("id", id),
+		)
+		return fmt.Errorf("erro ao deletar usuário: %w", err)
+	}
+
 	if err := uc.userRepo.Delete(ctx, objectID); err != nil {
 		logger.Error("Erro ao deletar usuário",
 			zap.Error(err),
@@ -0,0 +1,32 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// revokeTokens invalida os access tokens já emitidos para o usuário, incrementando a
+// versão de tokens, e revoga as sessões para que os refresh tokens não emitam novos.
+func revokeTokens(ctx context.Context, userRepo repository.UserRepository, sessionRepo repository.SessionRepository, userID primitive.ObjectID, reason string) error {
+	if err := userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+
+	revoked, err := sessionRepo.RevokeAllByUser(ctx, userID, reason, time.Now())
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Tokens do usuário revogados",
+		zap.String("user_id", userID.Hex()),
+		zap.String("reason", reason),
+		zap.Int64("sessions", revoked),
+	)
+
+	return nil
+}
@@ -12,20 +12,25 @@ import (
 	"go.uber.org/zap"
 )
 
+// UpdateUserInput substitui nome, email e role. CPF só é alterado quando informado; uma
+// string vazia remove o documento.
 type UpdateUserInput struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Email string  `json:"email"`
+	Role  string  `json:"role"`
+	CPF   *string `json:"cpf"`
 }
 
 type UpdateUserUseCase struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
 }
 
-func NewUpdateUserUseCase(userRepo repository.UserRepository) *UpdateUserUseCase {
+func NewUpdateUserUseCase(userRepo repository.UserRepository, sessionRepo repository.SessionRepository) *UpdateUserUseCase {
 	return &UpdateUserUseCase{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
 	}
 }
 
@@ -51,6 +56,14 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput)
 		return nil, fmt.Errorf("role inválido")
 	}
 
+	var cpf string
+	if input.CPF != nil {
+		var valid bool
+		if cpf, valid = entity.NormalizeCPF(*input.CPF); !valid {
+			return nil, fmt.Errorf("CPF inválido")
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -63,7 +76,11 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput)
 		return nil, fmt.Errorf("usuário não encontrado")
 	}
 
+	roleChanged := user.Role != role
 	user.Update(input.Name, input.Email, role)
+	if input.CPF != nil {
+		user.SetCPF(cpf)
+	}
 
 	if err := uc.userRepo.Update(ctx, user); err != nil {
 		logger.Error("Erro ao atualizar usuário",
@@ -73,6 +90,17 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput)
 		return nil, fmt.Errorf("erro ao atualizar usuário: %w", err)
 	}
 
+	// Com o papel alterado, os tokens emitidos carregam o papel antigo e precisam ser renovados.
+	if roleChanged {
+		if err := revokeTokens(ctx, uc.userRepo, uc.sessionRepo, user.ID, entity.SessionRevokedRoleChanged); err != nil {
+			logger.Error("Erro ao revogar tokens após mudança de papel",
+				zap.Error(err),
+				zap.String("id", input.ID),
+			)
+			return nil, fmt.Errorf("erro ao revogar tokens do usuário: %w", err)
+		}
+	}
+
 	logger.Info("Usuário atualizado com sucesso",
 		zap.String("id", user.ID.Hex()),
 		zap.String("email", user.Email),
@@ -0,0 +1,15 @@
+package waitlist
+
+import "errors"
+
+var (
+	ErrInvalidClassID    = errors.New("class_id inválido")
+	ErrInvalidUserID     = errors.New("user_id inválido")
+	ErrNotStudent        = errors.New("apenas estudantes podem entrar na lista de espera")
+	ErrClassHasSpots     = errors.New("aula possui vagas disponíveis, realize a inscrição")
+	ErrClassStarted      = errors.New("aula já iniciada")
+	ErrClassNotOpen      = errors.New("aula não está aberta para inscrições")
+	ErrAlreadyEnrolled   = errors.New("usuário já está inscrito nesta aula")
+	ErrAlreadyWaitlisted = errors.New("usuário já está na lista de espera desta aula")
+	ErrNotWaitlisted     = errors.New("usuário não está na lista de espera desta aula")
+)
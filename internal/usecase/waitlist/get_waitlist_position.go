@@ -0,0 +1,58 @@
+package waitlist
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+)
+
+type GetWaitlistPositionUseCase struct {
+	waitlistRepo repository.WaitlistRepository
+	paymentRepo  repository.PaymentRepository
+}
+
+func NewGetWaitlistPositionUseCase(
+	waitlistRepo repository.WaitlistRepository,
+	paymentRepo repository.PaymentRepository,
+) *GetWaitlistPositionUseCase {
+	return &GetWaitlistPositionUseCase{
+		waitlistRepo: waitlistRepo,
+		paymentRepo:  paymentRepo,
+	}
+}
+
+func (uc *GetWaitlistPositionUseCase) Execute(ctx context.Context, input WaitlistInput) (*WaitlistOutput, error) {
+	userID, classID, err := parseWaitlistInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := uc.waitlistRepo.FindActiveByUserAndClass(ctx, userID, classID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrNotWaitlisted
+	}
+
+	output := &WaitlistOutput{Entry: entry}
+
+	if entry.IsOffered() {
+		paymentEntity, err := uc.paymentRepo.FindByEnrollmentID(ctx, *entry.EnrollmentID)
+		if err != nil {
+			return nil, err
+		}
+		if paymentEntity != nil {
+			output.PaymentURL = paymentEntity.InitPointURL
+		}
+		return output, nil
+	}
+
+	ahead, err := uc.waitlistRepo.CountWaitingBefore(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	output.Position = ahead + 1
+
+	return output, nil
+}
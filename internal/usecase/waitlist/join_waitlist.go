@@ -0,0 +1,136 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+type JoinWaitlistUseCase struct {
+	classRepo      repository.ClassRepository
+	userRepo       repository.UserRepository
+	enrollmentRepo repository.EnrollmentRepository
+	waitlistRepo   repository.WaitlistRepository
+}
+
+func NewJoinWaitlistUseCase(
+	classRepo repository.ClassRepository,
+	userRepo repository.UserRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	waitlistRepo repository.WaitlistRepository,
+) *JoinWaitlistUseCase {
+	return &JoinWaitlistUseCase{
+		classRepo:      classRepo,
+		userRepo:       userRepo,
+		enrollmentRepo: enrollmentRepo,
+		waitlistRepo:   waitlistRepo,
+	}
+}
+
+// WaitlistInput identifica o aluno e a aula. Com UseCredit, a vaga liberada para o aluno é
+// paga com um crédito de pacote, como na inscrição direta; alunos com assinatura ativa não
+// pagam pela aula.
+type WaitlistInput struct {
+	UserID    string `json:"-"`
+	ClassID   string `json:"-"`
+	UseCredit bool   `json:"use_credit"`
+}
+
+type WaitlistOutput struct {
+	Entry      *entity.WaitlistEntry `json:"entry"`
+	Position   int64                 `json:"position,omitempty"`
+	PaymentURL string                `json:"payment_url,omitempty"`
+}
+
+func (uc *JoinWaitlistUseCase) Execute(ctx context.Context, input WaitlistInput) (*WaitlistOutput, error) {
+	userID, classID, err := parseWaitlistInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsStudent() {
+		return nil, ErrNotStudent
+	}
+
+	// A aula é relida na transação e marcada com Touch: se ReleaseSeat devolver uma vaga ao
+	// mesmo tempo, uma das transações é repetida e a entrada não fica esperando com vaga livre.
+	var entry *entity.WaitlistEntry
+	err = uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		class, err := uc.classRepo.FindByID(sc, classID)
+		if err != nil {
+			return err
+		}
+		if !class.StartTime.After(time.Now()) {
+			return ErrClassStarted
+		}
+		if !class.IsPublished() {
+			return ErrClassNotOpen
+		}
+		if class.HasAvailableSpots() {
+			return ErrClassHasSpots
+		}
+
+		enrolled, err := uc.enrollmentRepo.FindByUserAndClass(sc, userID, classID)
+		if err != nil {
+			return err
+		}
+		if enrolled != nil {
+			return ErrAlreadyEnrolled
+		}
+
+		entry = entity.NewWaitlistEntry(classID, userID)
+		entry.UseCredit = input.UseCredit
+		if err := uc.waitlistRepo.Create(sc, entry); err != nil {
+			if errors.Is(err, repository.ErrWaitlistEntryExists) {
+				return ErrAlreadyWaitlisted
+			}
+			return err
+		}
+
+		return uc.classRepo.Touch(sc, classID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ahead, err := uc.waitlistRepo.CountWaitingBefore(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Aluno entrou na lista de espera",
+		zap.String("class_id", classID.Hex()),
+		zap.String("user_id", userID.Hex()),
+		zap.Int64("position", ahead+1),
+	)
+
+	return &WaitlistOutput{
+		Entry:    entry,
+		Position: ahead + 1,
+	}, nil
+}
+
+func parseWaitlistInput(input WaitlistInput) (primitive.ObjectID, primitive.ObjectID, error) {
+	userID, err := primitive.ObjectIDFromHex(input.UserID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, ErrInvalidUserID
+	}
+
+	classID, err := primitive.ObjectIDFromHex(input.ClassID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, ErrInvalidClassID
+	}
+
+	return userID, classID, nil
+}
@@ -0,0 +1,97 @@
+package waitlist
+
+import (
+	"context"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+type LeaveWaitlistUseCase struct {
+	classRepo      repository.ClassRepository
+	waitlistRepo   repository.WaitlistRepository
+	enrollmentRepo repository.EnrollmentRepository
+	paymentRepo    repository.PaymentRepository
+	releaseSeat    *ReleaseSeatUseCase
+}
+
+func NewLeaveWaitlistUseCase(
+	classRepo repository.ClassRepository,
+	waitlistRepo repository.WaitlistRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	paymentRepo repository.PaymentRepository,
+	releaseSeat *ReleaseSeatUseCase,
+) *LeaveWaitlistUseCase {
+	return &LeaveWaitlistUseCase{
+		classRepo:      classRepo,
+		waitlistRepo:   waitlistRepo,
+		enrollmentRepo: enrollmentRepo,
+		paymentRepo:    paymentRepo,
+		releaseSeat:    releaseSeat,
+	}
+}
+
+// Execute remove o aluno da fila. Se a vaga já havia sido oferecida e ainda não foi paga,
+// a inscrição pendente é cancelada e a vaga segue para o próximo da fila.
+func (uc *LeaveWaitlistUseCase) Execute(ctx context.Context, input WaitlistInput) error {
+	userID, classID, err := parseWaitlistInput(input)
+	if err != nil {
+		return err
+	}
+
+	return uc.classRepo.WithTransaction(ctx, func(ctx context.Context, sc mongo.SessionContext) error {
+		entry, err := uc.waitlistRepo.FindActiveByUserAndClass(sc, userID, classID)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			return ErrNotWaitlisted
+		}
+
+		if entry.IsOffered() {
+			enrollment, err := uc.enrollmentRepo.FindByID(sc, *entry.EnrollmentID)
+			if err != nil {
+				return err
+			}
+
+			if enrollment.IsPending() {
+				enrollment.Cancel()
+				if err := uc.enrollmentRepo.Update(sc, enrollment); err != nil {
+					return err
+				}
+
+				paymentEntity, err := uc.paymentRepo.FindByEnrollmentID(sc, enrollment.ID)
+				if err != nil {
+					return err
+				}
+				if paymentEntity != nil {
+					paymentEntity.MarkCancelled()
+					if err := uc.paymentRepo.Update(sc, paymentEntity); err != nil {
+						return err
+					}
+				}
+
+				entry.Leave()
+				if err := uc.waitlistRepo.Update(sc, entry); err != nil {
+					return err
+				}
+
+				return uc.releaseSeat.Execute(sc, classID)
+			}
+		}
+
+		entry.Leave()
+		if err := uc.waitlistRepo.Update(sc, entry); err != nil {
+			return err
+		}
+
+		logger.Info("Aluno saiu da lista de espera",
+			zap.String("class_id", classID.Hex()),
+			zap.String("user_id", userID.Hex()),
+		)
+
+		return nil
+	})
+}
@@ -0,0 +1,201 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
+	"github.com/marcelobritu/isayoga-api/internal/domain/repository"
+	creditUC "github.com/marcelobritu/isayoga-api/internal/usecase/credit"
+	membershipUC "github.com/marcelobritu/isayoga-api/internal/usecase/membership"
+	"github.com/marcelobritu/isayoga-api/pkg/config"
+	"github.com/marcelobritu/isayoga-api/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// ReleaseSeatUseCase devolve uma vaga da aula. Se houver alunos na lista de espera,
+// a vaga é repassada ao primeiro da fila como inscrição pendente com prazo para pagamento;
+// caso contrário, a ocupação da aula é decrementada. Como na inscrição direta, a vaga de
+// um aluno com assinatura ativa, ou que pediu para usar crédito, já é confirmada.
+//
+// Deve ser executado com o contexto da transação que liberou a vaga.
+type ReleaseSeatUseCase struct {
+	classRepo      repository.ClassRepository
+	waitlistRepo   repository.WaitlistRepository
+	enrollmentRepo repository.EnrollmentRepository
+	paymentRepo    repository.PaymentRepository
+	outboxRepo     repository.OutboxRepository
+	creditRepo     repository.CreditRepository
+	membershipRepo repository.MembershipRepository
+	config         *config.Config
+}
+
+func NewReleaseSeatUseCase(
+	classRepo repository.ClassRepository,
+	waitlistRepo repository.WaitlistRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	paymentRepo repository.PaymentRepository,
+	outboxRepo repository.OutboxRepository,
+	creditRepo repository.CreditRepository,
+	membershipRepo repository.MembershipRepository,
+	config *config.Config,
+) *ReleaseSeatUseCase {
+	return &ReleaseSeatUseCase{
+		classRepo:      classRepo,
+		waitlistRepo:   waitlistRepo,
+		enrollmentRepo: enrollmentRepo,
+		paymentRepo:    paymentRepo,
+		outboxRepo:     outboxRepo,
+		creditRepo:     creditRepo,
+		membershipRepo: membershipRepo,
+		config:         config,
+	}
+}
+
+func (uc *ReleaseSeatUseCase) Execute(ctx context.Context, classID primitive.ObjectID) error {
+	class, err := uc.classRepo.FindByID(ctx, classID)
+	if err != nil {
+		return err
+	}
+
+	if class.IsOpenForEnrollment(time.Now()) {
+		for {
+			entry, err := uc.waitlistRepo.FindNextWaiting(ctx, classID)
+			if err != nil {
+				return err
+			}
+			if entry == nil {
+				break
+			}
+
+			promoted, err := uc.promote(ctx, class, entry)
+			if err != nil {
+				return err
+			}
+			if promoted {
+				return nil
+			}
+		}
+	}
+
+	return uc.classRepo.DecrementEnrollment(ctx, classID)
+}
+
+func (uc *ReleaseSeatUseCase) promote(ctx context.Context, class *entity.Class, entry *entity.WaitlistEntry) (bool, error) {
+	existing, err := uc.enrollmentRepo.FindByUserAndClass(ctx, entry.UserID, class.ID)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		entry.Skip()
+		return false, uc.waitlistRepo.Update(ctx, entry)
+	}
+
+	enrollment := entity.NewEnrollment(entry.UserID, class.ID)
+
+	covered, err := uc.cover(ctx, class, entry, enrollment)
+	if err != nil {
+		return false, err
+	}
+	if covered {
+		return true, uc.claimCovered(ctx, class, entry, enrollment)
+	}
+
+	expiresAt := time.Now().Add(uc.config.Enrollment.WaitlistClaimWindow)
+	enrollment.HoldUntil(expiresAt)
+	if err := uc.enrollmentRepo.Create(ctx, enrollment); err != nil {
+		return false, err
+	}
+
+	paymentEntity := entity.NewPayment(enrollment.ID, class.PriceInCents)
+	if err := uc.paymentRepo.Create(ctx, paymentEntity); err != nil {
+		return false, err
+	}
+
+	message := entity.NewOutboxMessage(entity.OutboxTypeCreateCheckout, enrollment.ID)
+	if err := uc.outboxRepo.Create(ctx, message); err != nil {
+		return false, err
+	}
+
+	entry.Offer(enrollment.ID, expiresAt)
+	if err := uc.waitlistRepo.Update(ctx, entry); err != nil {
+		return false, err
+	}
+
+	logger.Info("Vaga oferecida ao próximo da lista de espera",
+		zap.String("class_id", class.ID.Hex()),
+		zap.String("user_id", entry.UserID.Hex()),
+		zap.String("enrollment_id", enrollment.ID.Hex()),
+		zap.Time("offer_expires_at", expiresAt),
+	)
+
+	return true, nil
+}
+
+// cover aplica as regras da inscrição direta: com UseCredit a aula é paga com um crédito e,
+// sem ele, a assinatura ativa cobre a aula. Se os créditos acabaram enquanto o aluno
+// esperava, vale a assinatura ou, na falta dela, o pagamento avulso.
+func (uc *ReleaseSeatUseCase) cover(ctx context.Context, class *entity.Class, entry *entity.WaitlistEntry, enrollment *entity.Enrollment) (bool, error) {
+	now := time.Now()
+
+	if entry.UseCredit {
+		err := creditUC.ConsumeCredit(ctx, uc.creditRepo, enrollment, now)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, repository.ErrNoCredits) {
+			return false, err
+		}
+	}
+
+	return membershipUC.CoverClass(ctx, uc.membershipRepo, enrollment, class, now)
+}
+
+// claimCovered grava a inscrição já confirmada e encerra a entrada da fila. A mensagem de
+// checkout não cria cobrança para inscrições confirmadas; ela só avisa o aluno.
+func (uc *ReleaseSeatUseCase) claimCovered(ctx context.Context, class *entity.Class, entry *entity.WaitlistEntry, enrollment *entity.Enrollment) error {
+	if err := uc.enrollmentRepo.Create(ctx, enrollment); err != nil {
+		return err
+	}
+
+	message := entity.NewOutboxMessage(entity.OutboxTypeCreateCheckout, enrollment.ID)
+	if err := uc.outboxRepo.Create(ctx, message); err != nil {
+		return err
+	}
+
+	entry.ClaimCovered(enrollment.ID)
+	if err := uc.waitlistRepo.Update(ctx, entry); err != nil {
+		return err
+	}
+
+	logger.Info("Vaga da lista de espera confirmada sem pagamento",
+		zap.String("class_id", class.ID.Hex()),
+		zap.String("user_id", entry.UserID.Hex()),
+		zap.String("enrollment_id", enrollment.ID.Hex()),
+		zap.Bool("credit", enrollment.PaidWithCredit()),
+	)
+
+	return nil
+}
+
+// CloseOffer encerra a oferta da lista de espera vinculada à inscrição, se existir:
+// claimed indica que o aluno pagou; caso contrário ele perde a vez.
+func (uc *ReleaseSeatUseCase) CloseOffer(ctx context.Context, enrollmentID primitive.ObjectID, claimed bool) error {
+	entry, err := uc.waitlistRepo.FindByEnrollmentID(ctx, enrollmentID)
+	if err != nil {
+		return err
+	}
+	if entry == nil || !entry.IsOffered() {
+		return nil
+	}
+
+	if claimed {
+		entry.Claim()
+	} else {
+		entry.Skip()
+	}
+
+	return uc.waitlistRepo.Update(ctx, entry)
+}
@@ -8,35 +8,51 @@ import (
 	"github.com/marcelobritu/isayoga-api/internal/domain/entity"
 )
 
-var jwtSecret = []byte("your-secret-key-change-in-production")
+var (
+	jwtSecret      = []byte("your-secret-key-change-in-production")
+	accessTokenTTL = 15 * time.Minute
+)
 
+// Claims carrega a versão de tokens do usuário no momento da emissão; tokens de versões
+// anteriores são recusados pelo middleware de autenticação.
 type Claims struct {
-	UserID string           `json:"user_id"`
-	Email  string           `json:"email"`
-	Role   entity.UserRole  `json:"role"`
+	UserID       string          `json:"user_id"`
+	Email        string          `json:"email"`
+	Role         entity.UserRole `json:"role"`
+	SessionID    string          `json:"sid,omitempty"`
+	TokenVersion int             `json:"ver"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(user *entity.User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
-	
+// GenerateToken emite o access token de curta duração da sessão informada e devolve
+// também o instante em que ele expira.
+func GenerateToken(user *entity.User, sessionID string) (string, time.Time, error) {
+	now := time.Now()
+	expirationTime := now.Add(accessTokenTTL)
+
 	claims := &Claims{
-		UserID: user.ID.Hex(),
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:       user.ID.Hex(),
+		Email:        user.Email,
+		Role:         user.Role,
+		SessionID:    sessionID,
+		TokenVersion: user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	signed, err := token.SignedString(jwtSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expirationTime, nil
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
-	
+
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
@@ -59,3 +75,6 @@ func SetJWTSecret(secret string) {
 	jwtSecret = []byte(secret)
 }
 
+func SetAccessTokenTTL(ttl time.Duration) {
+	accessTokenTTL = ttl
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,8 +14,14 @@ type Config struct {
 	Server      ServerConfig
 	Database    DatabaseConfig
 	MercadoPago MercadoPagoConfig
+	Payment     PaymentConfig
 	Telemetry   TelemetryConfig
 	Auth        AuthConfig
+	Worker      WorkerConfig
+	Enrollment  EnrollmentConfig
+	Class       ClassConfig
+	Credit      CreditConfig
+	Studio      StudioConfig
 }
 
 type ServerConfig struct {
@@ -28,9 +36,16 @@ type DatabaseConfig struct {
 }
 
 type MercadoPagoConfig struct {
-	AccessToken string
-	NotifyURL   string
-	BackURL     string
+	AccessToken      string
+	NotifyURL        string
+	BackURL          string
+	WebhookSecret    string
+	WebhookTolerance time.Duration
+}
+
+type PaymentConfig struct {
+	Provider          string
+	ReconcileLookback time.Duration
 }
 
 type TelemetryConfig struct {
@@ -40,7 +55,47 @@ type TelemetryConfig struct {
 }
 
 type AuthConfig struct {
-	JWTSecret string
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+type WorkerConfig struct {
+	OutboxInterval     time.Duration
+	OutboxMaxAttempts  int
+	ExpiryInterval     time.Duration
+	SeriesInterval     time.Duration
+	LifecycleInterval  time.Duration
+	CreditInterval     time.Duration
+	ReconcileInterval  time.Duration
+	WebhookInterval    time.Duration
+	WebhookMaxAttempts int
+}
+
+type ClassConfig struct {
+	SeriesHorizon         time.Duration
+	AvailabilityHeartbeat time.Duration
+}
+
+type EnrollmentConfig struct {
+	HoldTTL                  time.Duration
+	PixExpiration            time.Duration
+	WaitlistClaimWindow      time.Duration
+	CancellationRefundWindow time.Duration
+	LateCancelRefundPercent  int
+	LateCancelAction         string
+}
+
+type CreditConfig struct {
+	Validity time.Duration
+}
+
+// StudioConfig identifica o estúdio nos recibos emitidos.
+type StudioConfig struct {
+	Name     string
+	Document string
+	Address  string
+	Email    string
 }
 
 func Load() (*Config, error) {
@@ -59,9 +114,15 @@ func Load() (*Config, error) {
 			MongoDBName: getEnv("MONGO_DB_NAME", "isayoga"),
 		},
 		MercadoPago: MercadoPagoConfig{
-			AccessToken: getEnv("MERCADOPAGO_ACCESS_TOKEN", ""),
-			NotifyURL:   getEnv("MERCADOPAGO_NOTIFY_URL", ""),
-			BackURL:     getEnv("MERCADOPAGO_BACK_URL", "http://localhost:8080"),
+			AccessToken:      getEnv("MERCADOPAGO_ACCESS_TOKEN", ""),
+			NotifyURL:        getEnv("MERCADOPAGO_NOTIFY_URL", ""),
+			BackURL:          getEnv("MERCADOPAGO_BACK_URL", "http://localhost:8080"),
+			WebhookSecret:    getEnv("MERCADOPAGO_WEBHOOK_SECRET", ""),
+			WebhookTolerance: getEnvDuration("MERCADOPAGO_WEBHOOK_TOLERANCE", 5*time.Minute),
+		},
+		Payment: PaymentConfig{
+			Provider:          getEnv("PAYMENT_PROVIDER", "mercadopago"),
+			ReconcileLookback: getEnvDuration("PAYMENT_RECONCILE_LOOKBACK", 7*24*time.Hour),
 		},
 		Telemetry: TelemetryConfig{
 			ZipkinURL:      getEnv("ZIPKIN_URL", "http://localhost:9411/api/v2/spans"),
@@ -69,7 +130,41 @@ func Load() (*Config, error) {
 			ServiceVersion: getEnv("SERVICE_VERSION", "1.0.0"),
 		},
 		Auth: AuthConfig{
-			JWTSecret: getEnv("JWT_SECRET", "change-this-secret-in-production"),
+			JWTSecret:       getEnv("JWT_SECRET", "change-this-secret-in-production"),
+			AccessTokenTTL:  getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
+			RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
+		},
+		Worker: WorkerConfig{
+			OutboxInterval:     getEnvDuration("WORKER_OUTBOX_INTERVAL", 10*time.Second),
+			OutboxMaxAttempts:  getEnvInt("WORKER_OUTBOX_MAX_ATTEMPTS", 5),
+			ExpiryInterval:     getEnvDuration("WORKER_EXPIRY_INTERVAL", time.Minute),
+			SeriesInterval:     getEnvDuration("WORKER_SERIES_INTERVAL", time.Hour),
+			LifecycleInterval:  getEnvDuration("WORKER_LIFECYCLE_INTERVAL", time.Minute),
+			CreditInterval:     getEnvDuration("WORKER_CREDIT_INTERVAL", time.Hour),
+			ReconcileInterval:  getEnvDuration("WORKER_RECONCILE_INTERVAL", 30*time.Minute),
+			WebhookInterval:    getEnvDuration("WORKER_WEBHOOK_INTERVAL", 30*time.Second),
+			WebhookMaxAttempts: getEnvInt("WORKER_WEBHOOK_MAX_ATTEMPTS", 8),
+		},
+		Class: ClassConfig{
+			SeriesHorizon:         getEnvDuration("CLASS_SERIES_HORIZON", 8*7*24*time.Hour),
+			AvailabilityHeartbeat: getEnvDuration("CLASS_AVAILABILITY_HEARTBEAT", 15*time.Second),
+		},
+		Enrollment: EnrollmentConfig{
+			HoldTTL:                  getEnvDuration("ENROLLMENT_HOLD_TTL", 15*time.Minute),
+			PixExpiration:            getEnvDuration("PIX_EXPIRATION", 30*time.Minute),
+			WaitlistClaimWindow:      getEnvDuration("WAITLIST_CLAIM_WINDOW", 2*time.Hour),
+			CancellationRefundWindow: getEnvDuration("CANCELLATION_REFUND_WINDOW", 24*time.Hour),
+			LateCancelRefundPercent:  getEnvInt("CANCELLATION_LATE_REFUND_PERCENT", 50),
+			LateCancelAction:         getEnv("CANCELLATION_LATE_ACTION", "refund"),
+		},
+		Credit: CreditConfig{
+			Validity: getEnvDuration("CREDIT_VALIDITY", 90*24*time.Hour),
+		},
+		Studio: StudioConfig{
+			Name:     getEnv("STUDIO_NAME", "Isa Yoga"),
+			Document: getEnv("STUDIO_DOCUMENT", ""),
+			Address:  getEnv("STUDIO_ADDRESS", ""),
+			Email:    getEnv("STUDIO_EMAIL", ""),
 		},
 	}
 
@@ -77,6 +172,26 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("MONGO_URI é obrigatório")
 	}
 
+	if config.Payment.Provider != "mercadopago" && config.Payment.Provider != "fake" {
+		return nil, fmt.Errorf("PAYMENT_PROVIDER inválido: deve ser mercadopago ou fake")
+	}
+
+	if config.Payment.Provider == "fake" && config.Server.Env != "development" {
+		return nil, fmt.Errorf("PAYMENT_PROVIDER=fake só é permitido com SERVER_ENV=development")
+	}
+
+	if config.Enrollment.PixExpiration < 30*time.Minute {
+		return nil, fmt.Errorf("PIX_EXPIRATION inválido: o Mercado Pago exige no mínimo 30m")
+	}
+
+	if percent := config.Enrollment.LateCancelRefundPercent; percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("CANCELLATION_LATE_REFUND_PERCENT inválido: deve estar entre 0 e 100")
+	}
+
+	if action := config.Enrollment.LateCancelAction; action != "refund" && action != "credit" {
+		return nil, fmt.Errorf("CANCELLATION_LATE_ACTION inválido: deve ser refund ou credit")
+	}
+
 	return config, nil
 }
 
@@ -86,3 +201,23 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+		log.Printf("Valor inválido para %s, usando padrão %d", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+		log.Printf("Valor inválido para %s, usando padrão %s", key, defaultValue)
+	}
+	return defaultValue
+}